@@ -2,6 +2,9 @@ package bun
 
 import (
 	"context"
+	"database/sql"
+	"iter"
+	"slices"
 
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
@@ -16,6 +19,9 @@ type (
 	BaseModel = schema.BaseModel
 	Query     = schema.Query
 
+	Index        = schema.Index
+	ModelIndexer = schema.ModelIndexer
+
 	BeforeAppendModelHook = schema.BeforeAppendModelHook
 
 	BeforeScanRowHook = schema.BeforeScanRowHook
@@ -83,6 +89,63 @@ func In(slice any) schema.QueryAppender {
 	return schema.In(slice)
 }
 
+// InChunks splits the values into chunks which fit the dialect's limit of values
+// in a statement, see schema.MaxValuesDialect, and yields In for each chunk.
+// Use ExecInChunks to execute a query for each chunk in one transaction.
+//
+// It yields nothing if there are no values.
+func InChunks[T any](db IDB, values []T) iter.Seq[schema.QueryAppender] {
+	return func(yield func(schema.QueryAppender) bool) {
+		size := len(values)
+		if d, ok := db.Dialect().(schema.MaxValuesDialect); ok && d.MaxValues() > 0 {
+			size = d.MaxValues()
+		}
+		if size == 0 {
+			return
+		}
+		for chunk := range slices.Chunk(values, size) {
+			if !yield(In(chunk)) {
+				return
+			}
+		}
+	}
+}
+
+// ExecInChunks executes the query built by fn for each chunk of the values, see InChunks,
+// in one transaction, or in a savepoint if db is a transaction. The result aggregates
+// RowsAffected of all statements:
+//
+//	res, err := bun.ExecInChunks(ctx, db, ids,
+//		func(ctx context.Context, tx bun.Tx, ids schema.QueryAppender) (sql.Result, error) {
+//			return tx.NewDelete().Model((*User)(nil)).Where("id IN (?)", ids).Exec(ctx)
+//		})
+//
+// Nothing is executed if there are no values.
+func ExecInChunks[T any](
+	ctx context.Context,
+	db IDB,
+	values []T,
+	fn func(ctx context.Context, tx Tx, in schema.QueryAppender) (sql.Result, error),
+) (sql.Result, error) {
+	var res chunkedResult
+	if len(values) == 0 {
+		return res, nil
+	}
+	if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx Tx) error {
+		for in := range InChunks(tx, values) {
+			r, err := fn(ctx, tx, in)
+			if err != nil {
+				return err
+			}
+			res = append(res, r)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func NullZero(value any) schema.QueryAppender {
 	return schema.NullZero(value)
 }
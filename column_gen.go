@@ -0,0 +1,156 @@
+package bun
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// GenerateColumns writes the Go source of the package pkgName with typed column references
+// for the models, see Column. For each model, e.g. User, it declares the variable UserColumns
+// with a field for each of the model's columns, e.g. UserColumns.Email. The models must be
+// declared in the package the source is written to.
+//
+// GenerateColumns is meant to be called by a program run with go:generate:
+//
+//	//go:build ignore
+//
+//	package main
+//
+//	func main() {
+//		db := bun.NewDB(sqldb, pgdialect.New())
+//		f, _ := os.Create("columns_gen.go")
+//		defer f.Close()
+//		if err := bun.GenerateColumns(f, db, "models", (*models.User)(nil)); err != nil {
+//			panic(err)
+//		}
+//	}
+func GenerateColumns(w io.Writer, db IDB, pkgName string, models ...any) error {
+	var pkgPath string
+	tables := make([]*schema.Table, len(models))
+	for i, model := range models {
+		typ := reflect.TypeOf(model)
+		for typ != nil && typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ == nil || typ.Kind() != reflect.Struct {
+			return fmt.Errorf("bun: GenerateColumns: model must be a struct, got %T", model)
+		}
+		if pkgPath != "" && typ.PkgPath() != pkgPath {
+			return fmt.Errorf("bun: GenerateColumns: models must be declared in the same package")
+		}
+		pkgPath = typ.PkgPath()
+		tables[i] = db.Dialect().Tables().Get(typ)
+	}
+
+	imports := map[string]bool{"github.com/uptrace/bun": true}
+	var body bytes.Buffer
+	for _, table := range tables {
+		name := table.Type.Name()
+
+		fmt.Fprintf(&body, "\n// %sColumns are the columns of %s.\n", name, name)
+		fmt.Fprintf(&body, "var %sColumns = struct {\n", name)
+		for _, field := range table.Fields {
+			fmt.Fprintf(&body, "%s bun.Column[%s, %s]\n",
+				columnGoName(table.Type, field.Index), name, typeString(field.StructField.Type, pkgPath, imports))
+		}
+		fmt.Fprintf(&body, "}{\n")
+		for _, field := range table.Fields {
+			fmt.Fprintf(&body, "%s: bun.NewColumn[%s, %s](%q),\n",
+				columnGoName(table.Type, field.Index), name, typeString(field.StructField.Type, pkgPath, imports), field.Name)
+		}
+		fmt.Fprintf(&body, "}\n")
+	}
+
+	// Standard library packages are imported in a separate group.
+	var std, paths []string
+	for path := range imports {
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			paths = append(paths, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	slices.Sort(std)
+	slices.Sort(paths)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by bun.GenerateColumns. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	fmt.Fprintf(&b, "import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	if len(std) > 0 {
+		fmt.Fprintf(&b, "\n")
+	}
+	for _, path := range paths {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	fmt.Fprintf(&b, ")\n")
+	b.Write(body.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("bun: GenerateColumns: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// columnGoName returns the name of the column field declared for the struct field
+// with the index. Fields of named embedded structs, e.g. `bun:"embed:home_"`,
+// are prefixed with the struct field name, so each copy gets its own field.
+func columnGoName(typ reflect.Type, index []int) string {
+	var b strings.Builder
+	for _, i := range index {
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		sf := typ.Field(i)
+		if !sf.Anonymous {
+			b.WriteString(sf.Name)
+		}
+		typ = sf.Type
+	}
+	return b.String()
+}
+
+// typeString returns the Go syntax of the type, which is used in the package pkgPath,
+// and adds the packages it references to imports.
+func typeString(typ reflect.Type, pkgPath string, imports map[string]bool) string {
+	if typ.Name() != "" {
+		switch path := typ.PkgPath(); path {
+		case "":
+			return typ.Name() // predeclared type
+		case pkgPath:
+			return typ.Name()
+		default:
+			imports[path] = true
+			return typ.String()
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return "*" + typeString(typ.Elem(), pkgPath, imports)
+	case reflect.Slice:
+		return "[]" + typeString(typ.Elem(), pkgPath, imports)
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", typ.Len(), typeString(typ.Elem(), pkgPath, imports))
+	case reflect.Map:
+		return "map[" + typeString(typ.Key(), pkgPath, imports) + "]" +
+			typeString(typ.Elem(), pkgPath, imports)
+	case reflect.Interface:
+		if typ.NumMethod() == 0 {
+			return "any"
+		}
+	}
+	return strings.ReplaceAll(typ.String(), "interface {}", "any")
+}
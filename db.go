@@ -130,6 +130,10 @@ func (db *DB) NewMerge() *MergeQuery {
 	return NewMergeQuery(db)
 }
 
+func (db *DB) NewBatch() *Batch {
+	return NewBatch(db)
+}
+
 func (db *DB) NewSelect() *SelectQuery {
 	return NewSelectQuery(db)
 }
@@ -411,6 +415,10 @@ func (c Conn) NewMerge() *MergeQuery {
 	return NewMergeQuery(c.db).Conn(c)
 }
 
+func (c Conn) NewBatch() *Batch {
+	return NewBatch(c.db).Conn(c)
+}
+
 func (c Conn) NewSelect() *SelectQuery {
 	return NewSelectQuery(c.db).Conn(c)
 }
@@ -725,6 +733,10 @@ func (tx Tx) NewMerge() *MergeQuery {
 	return NewMergeQuery(tx.db).Conn(tx)
 }
 
+func (tx Tx) NewBatch() *Batch {
+	return NewBatch(tx.db).Conn(tx)
+}
+
 func (tx Tx) NewSelect() *SelectQuery {
 	return NewSelectQuery(tx.db).Conn(tx)
 }
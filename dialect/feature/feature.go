@@ -41,8 +41,18 @@ const (
 	DeleteOrderLimit // DELETE ... ORDER BY ... LIMIT ...
 	DeleteReturning
 	MergeReturning
-	AlterColumnExists // ADD/DROP COLUMN IF NOT EXISTS/IF EXISTS
-	FKDefaultOnAction // FK ON UPDATE/ON DELETE has default value: NO ACTION
+	AlterColumnExists  // ADD/DROP COLUMN IF NOT EXISTS/IF EXISTS
+	FKDefaultOnAction  // FK ON UPDATE/ON DELETE has default value: NO ACTION
+	CheckConstraint    // ADD/DROP CONSTRAINT ... CHECK and inspection of CHECK constraints
+	EnumType           // CREATE TYPE ... AS ENUM
+	CommentOnColumn    // COMMENT ON COLUMN
+	OnlineSchemaChange // CREATE INDEX CONCURRENTLY, ADD CONSTRAINT ... NOT VALID, SET lock_timeout
+	RowValueCompare    // ... WHERE (A, B) > (N, NN)
+	NamedWindow        // SELECT ... WINDOW w AS (...)
+	WindowGroups       // ... OVER (GROUPS BETWEEN ...)
+	WindowRangeOffset  // ... OVER (RANGE BETWEEN N PRECEDING AND ...)
+	WindowExclude      // ... OVER (ROWS BETWEEN ... EXCLUDE CURRENT ROW)
+	Merge              // MERGE INTO ... USING ... ON ...
 )
 
 type NotSupportError struct {
@@ -95,4 +105,14 @@ var flag2str = map[Feature]string{
 	MergeReturning:       "MergeReturning",
 	AlterColumnExists:    "AlterColumnExists",
 	FKDefaultOnAction:    "FKDefaultOnAction",
+	CheckConstraint:      "CheckConstraint",
+	EnumType:             "EnumType",
+	CommentOnColumn:      "CommentOnColumn",
+	OnlineSchemaChange:   "OnlineSchemaChange",
+	RowValueCompare:      "RowValueCompare",
+	NamedWindow:          "NamedWindow",
+	WindowGroups:         "WindowGroups",
+	WindowRangeOffset:    "WindowRangeOffset",
+	WindowExclude:        "WindowExclude",
+	Merge:                "Merge",
 }
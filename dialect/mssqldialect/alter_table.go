@@ -0,0 +1,369 @@
+package mssqldialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
+)
+
+func (d *Dialect) NewMigrator(db *bun.DB, schemaName string) sqlschema.Migrator {
+	return &migrator{db: db, schemaName: schemaName, BaseMigrator: sqlschema.NewBaseMigrator(db)}
+}
+
+// migrator generates ALTER TABLE statements for SQL Server.
+//
+// SQL Server binds column defaults to DEFAULT constraints, which must be dropped
+// before the column's type can be altered or the column can be dropped. These constraints
+// are usually named by the server, so migrator looks up their names when the query is executed.
+type migrator struct {
+	*sqlschema.BaseMigrator
+
+	db         *bun.DB
+	schemaName string
+}
+
+var _ sqlschema.Migrator = (*migrator)(nil)
+
+func (m *migrator) AppendSQL(b []byte, operation any) (_ []byte, err error) {
+	gen := m.db.QueryGen()
+
+	// Append ALTER TABLE statement to the enclosed query bytes []byte.
+	appendAlterTable := func(query []byte, tableName string) []byte {
+		query = append(query, "ALTER TABLE "...)
+		query = m.appendFQN(gen, query, tableName)
+		return append(query, " "...)
+	}
+
+	switch change := operation.(type) {
+	case *migrate.CreateTableOp:
+		if change.Model == nil {
+			b = append(b, "CREATE TABLE "...)
+			b = m.appendFQN(gen, b, change.TableName)
+			return m.AppendTableDefinition(b, change.Table)
+		}
+		return m.AppendCreateTable(b, change.Model)
+	case *migrate.DropTableOp:
+		return m.AppendDropTable(b, m.schemaName, change.TableName)
+	case *migrate.RenameTableOp:
+		b, err = m.renameTable(gen, b, change)
+	case *migrate.RenameColumnOp:
+		b, err = m.renameColumn(gen, b, change)
+	case *migrate.AddColumnOp:
+		b, err = m.addColumn(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.DropColumnOp:
+		b = m.dropDefaultConstraint(gen, b, change.TableName, change.ColumnName)
+		b = append(b, ";\n"...)
+		b, err = m.dropColumn(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.AddPrimaryKeyOp:
+		b, err = m.addPrimaryKey(gen, appendAlterTable(b, change.TableName), change.TableName, change.PrimaryKey)
+	case *migrate.ChangePrimaryKeyOp:
+		b = m.dropPrimaryKey(gen, b, change.TableName, change.Old)
+		b = append(b, ";\n"...)
+		b, err = m.addPrimaryKey(gen, appendAlterTable(b, change.TableName), change.TableName, change.New)
+	case *migrate.DropPrimaryKeyOp:
+		b = m.dropPrimaryKey(gen, b, change.TableName, change.PrimaryKey)
+	case *migrate.AddUniqueConstraintOp:
+		b, err = m.addUnique(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.DropUniqueConstraintOp:
+		b, err = m.dropConstraint(gen, appendAlterTable(b, change.TableName), uniqueName(change.TableName, change.Unique))
+	case *migrate.ChangeColumnTypeOp:
+		b, err = m.changeColumnType(gen, b, appendAlterTable, change)
+	case *migrate.AddForeignKeyOp:
+		b, err = m.addForeignKey(gen, appendAlterTable(b, change.TableName()), change)
+	case *migrate.DropForeignKeyOp:
+		b, err = m.dropConstraint(gen, appendAlterTable(b, change.TableName()), foreignKeyName(change.ConstraintName, change.ForeignKey))
+	case *migrate.CreateIndexOp:
+		if change.Index.Expr != "" || change.Index.Using != "" {
+			err = fmt.Errorf("index %q: SQL Server does not support index expressions and access methods",
+				sqlschema.IndexName(change.TableName, change.Index))
+			break
+		}
+		b, err = m.AppendCreateIndex(b, m.schemaName, change.TableName, change.Index)
+	case *migrate.DropIndexOp:
+		b = append(b, "DROP INDEX "...)
+		b = gen.AppendName(b, sqlschema.IndexName(change.TableName, change.Index))
+		b = append(b, " ON "...)
+		b = m.appendFQN(gen, b, change.TableName)
+	default:
+		return nil, fmt.Errorf("append sql: unknown operation %T", change)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("append sql: %w", err)
+	}
+	return b, nil
+}
+
+func (m *migrator) appendFQN(gen schema.QueryGen, b []byte, tableName string) []byte {
+	return gen.AppendQuery(b, "?.?", bun.Ident(m.schemaName), bun.Ident(tableName))
+}
+
+// appendObjectName appends the N'schema.object' string literal, which system procedures
+// and functions like sp_rename and OBJECT_ID accept as the name of the object.
+func (m *migrator) appendObjectName(gen schema.QueryGen, b []byte, names ...string) []byte {
+	fqn := m.appendFQN(gen, nil, names[0])
+	for _, name := range names[1:] {
+		fqn = append(fqn, '.')
+		fqn = gen.AppendName(fqn, name)
+	}
+	return gen.Dialect().AppendString(b, string(fqn))
+}
+
+func (m *migrator) renameTable(gen schema.QueryGen, b []byte, rename *migrate.RenameTableOp) (_ []byte, err error) {
+	b = append(b, "EXEC sp_rename "...)
+	b = m.appendObjectName(gen, b, rename.TableName)
+	b = append(b, ", "...)
+	b = gen.Dialect().AppendString(b, rename.NewName)
+	return b, nil
+}
+
+func (m *migrator) renameColumn(gen schema.QueryGen, b []byte, rename *migrate.RenameColumnOp) (_ []byte, err error) {
+	b = append(b, "EXEC sp_rename "...)
+	b = m.appendObjectName(gen, b, rename.TableName, rename.OldName)
+	b = append(b, ", "...)
+	b = gen.Dialect().AppendString(b, rename.NewName)
+	b = append(b, ", 'COLUMN'"...)
+	return b, nil
+}
+
+func (m *migrator) addColumn(gen schema.QueryGen, b []byte, add *migrate.AddColumnOp) (_ []byte, err error) {
+	b = append(b, "ADD "...)
+	b = gen.AppendName(b, add.ColumnName)
+	b = append(b, " "...)
+
+	if b, err = add.Column.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+
+	if add.Column.GetIsAutoIncrement() || add.Column.GetIsIdentity() {
+		b = append(b, " IDENTITY"...)
+	}
+
+	if !add.Column.GetIsNullable() {
+		b = append(b, " NOT NULL"...)
+	}
+
+	if def := add.Column.GetDefaultValue(); def != "" {
+		b = append(b, " DEFAULT "...)
+		b = appendDefault(gen, b, def)
+	}
+	return b, nil
+}
+
+func (m *migrator) dropColumn(gen schema.QueryGen, b []byte, drop *migrate.DropColumnOp) (_ []byte, err error) {
+	b = append(b, "DROP COLUMN "...)
+	b = gen.AppendName(b, drop.ColumnName)
+
+	return b, nil
+}
+
+func (m *migrator) addPrimaryKey(gen schema.QueryGen, b []byte, tableName string, pk sqlschema.PrimaryKey) (_ []byte, err error) {
+	b = append(b, "ADD CONSTRAINT "...)
+	b = gen.AppendName(b, primaryKeyName(tableName, pk))
+	b = append(b, " PRIMARY KEY ("...)
+	b, _ = pk.Columns.AppendQuery(gen, b)
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+func (m *migrator) addUnique(gen schema.QueryGen, b []byte, change *migrate.AddUniqueConstraintOp) (_ []byte, err error) {
+	b = append(b, "ADD CONSTRAINT "...)
+	b = gen.AppendName(b, uniqueName(change.TableName, change.Unique))
+	b = append(b, " UNIQUE ("...)
+	b, _ = change.Unique.Columns.AppendQuery(gen, b)
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+func (m *migrator) dropConstraint(gen schema.QueryGen, b []byte, name string) (_ []byte, err error) {
+	b = append(b, "DROP CONSTRAINT "...)
+	b = gen.AppendName(b, name)
+
+	return b, nil
+}
+
+func (m *migrator) addForeignKey(gen schema.QueryGen, b []byte, add *migrate.AddForeignKeyOp) (_ []byte, err error) {
+	b = append(b, "ADD CONSTRAINT "...)
+	b = gen.AppendName(b, foreignKeyName(add.ConstraintName, add.ForeignKey))
+
+	b = append(b, " FOREIGN KEY ("...)
+	if b, err = add.ForeignKey.From.Column.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+	b = append(b, ")"...)
+
+	b = append(b, " REFERENCES "...)
+	b = m.appendFQN(gen, b, add.ForeignKey.To.TableName)
+
+	b = append(b, " ("...)
+	if b, err = add.ForeignKey.To.Column.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+// changeColumnType alters the column's data type and nullability.
+// The column's DEFAULT constraint is dropped before and re-created after the change,
+// because SQL Server does not allow altering a column which has a default bound to it.
+//
+// SQL Server cannot add or remove the IDENTITY property of an existing column.
+func (m *migrator) changeColumnType(gen schema.QueryGen, b []byte, appendAlterTable func([]byte, string) []byte, colDef *migrate.ChangeColumnTypeOp) (_ []byte, err error) {
+	got, want := colDef.From, colDef.To
+
+	if want.GetIsAutoIncrement() != got.GetIsAutoIncrement() || want.GetIsIdentity() != got.GetIsIdentity() {
+		return b, fmt.Errorf("mssql: cannot change IDENTITY property of column %s.%s", colDef.TableName, colDef.Column)
+	}
+	// ALTER COLUMN re-defines the data type and nullability of the column.
+	if want.GetSQLType() == "" {
+		return b, fmt.Errorf("mssql: cannot alter column %s.%s: data type is not specified", colDef.TableName, colDef.Column)
+	}
+
+	// Separate the statements which this operation is comprised of.
+	n := len(b)
+	appendStatement := func(query []byte) []byte {
+		if len(query) > n {
+			query = append(query, ";\n"...)
+		}
+		return appendAlterTable(query, colDef.TableName)
+	}
+
+	if got.GetDefaultValue() != "" {
+		b = m.dropDefaultConstraint(gen, b, colDef.TableName, colDef.Column)
+	}
+
+	inspector := m.db.Dialect().(sqlschema.InspectorDialect)
+	if !inspector.CompareType(want, got) || want.GetIsNullable() != got.GetIsNullable() {
+		b = appendStatement(b)
+		b = append(b, "ALTER COLUMN "...)
+		b = gen.AppendName(b, colDef.Column)
+		b = append(b, " "...)
+		if b, err = want.AppendQuery(gen, b); err != nil {
+			return b, err
+		}
+		if want.GetIsNullable() {
+			b = append(b, " NULL"...)
+		} else {
+			b = append(b, " NOT NULL"...)
+		}
+	}
+
+	if def := want.GetDefaultValue(); def != "" {
+		b = appendStatement(b)
+		b = append(b, "ADD DEFAULT "...)
+		b = appendDefault(gen, b, def)
+		b = append(b, " FOR "...)
+		b = gen.AppendName(b, colDef.Column)
+	}
+	return b, nil
+}
+
+// dropDefaultConstraint drops the DEFAULT constraint of the column, if it has one.
+func (m *migrator) dropDefaultConstraint(gen schema.QueryGen, b []byte, tableName, columnName string) []byte {
+	lookup := gen.AppendQuery(nil, "SELECT dc.name FROM sys.default_constraints AS dc "+
+		"JOIN sys.columns AS c ON c.object_id = dc.parent_object_id AND c.column_id = dc.parent_column_id "+
+		"WHERE dc.parent_object_id = OBJECT_ID(?) AND c.name = ?",
+		bun.Safe(m.appendObjectName(gen, nil, tableName)), columnName)
+	return m.dropConstraintByLookup(gen, b, tableName, lookup)
+}
+
+// dropPrimaryKey drops the PRIMARY KEY constraint. If its name is not known, e.g. because it was
+// generated by the server, the name is looked up when the statement is executed.
+func (m *migrator) dropPrimaryKey(gen schema.QueryGen, b []byte, tableName string, pk sqlschema.PrimaryKey) []byte {
+	if pk.Name != "" {
+		b = append(b, "ALTER TABLE "...)
+		b = m.appendFQN(gen, b, tableName)
+		b = append(b, " "...)
+		b, _ = m.dropConstraint(gen, b, pk.Name)
+		return b
+	}
+	lookup := gen.AppendQuery(nil, "SELECT name FROM sys.key_constraints WHERE parent_object_id = OBJECT_ID(?) AND type = 'PK'",
+		bun.Safe(m.appendObjectName(gen, nil, tableName)))
+	return m.dropConstraintByLookup(gen, b, tableName, lookup)
+}
+
+// dropConstraintByLookup drops the constraint whose name is selected by the lookup query
+// when the statement is executed. The statement is a no-op if the lookup returns no rows.
+//
+// The statement is wrapped in sp_executesql to give the local variable its own scope,
+// so that several such statements can be executed in one batch.
+func (m *migrator) dropConstraintByLookup(gen schema.QueryGen, b []byte, tableName string, lookup []byte) []byte {
+	alter := m.appendFQN(gen, []byte("ALTER TABLE "), tableName)
+	alter = append(alter, " DROP CONSTRAINT "...)
+
+	var stmt []byte
+	stmt = append(stmt, "DECLARE @name sysname = ("...)
+	stmt = append(stmt, lookup...)
+	stmt = append(stmt, "); IF @name IS NOT NULL EXEC("...)
+	stmt = gen.Dialect().AppendString(stmt, string(alter))
+	stmt = append(stmt, " + QUOTENAME(@name))"...)
+
+	b = append(b, "EXEC sp_executesql "...)
+	return gen.Dialect().AppendString(b, string(stmt))
+}
+
+// appendDefault appends the default value of a column. sqlschema.Column reports string literals
+// without quotes, so the value is quoted unless it is a number, a keyword or a function call.
+func appendDefault(gen schema.QueryGen, b []byte, def string) []byte {
+	if _, err := strconv.ParseFloat(def, 64); err == nil {
+		return append(b, def...)
+	}
+	switch strings.ToLower(def) {
+	case "null", "current_timestamp":
+		return append(b, def...)
+	}
+	if strings.HasPrefix(def, "(") || isFunctionCall(def) {
+		return append(b, def...)
+	}
+	return gen.Dialect().AppendString(b, def)
+}
+
+// isFunctionCall reports whether s looks like a function call, e.g. getdate().
+func isFunctionCall(s string) bool {
+	i := strings.IndexByte(s, '(')
+	if i <= 0 || !strings.HasSuffix(s, ")") {
+		return false
+	}
+	for _, r := range s[:i] {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// primaryKeyName returns the name of the PRIMARY KEY constraint or generates one
+// using the same naming scheme as Postgres: <table>_pkey.
+func primaryKeyName(tableName string, pk sqlschema.PrimaryKey) string {
+	if pk.Name != "" {
+		return pk.Name
+	}
+	return tableName + "_pkey"
+}
+
+// uniqueName returns the name of the UNIQUE constraint or generates one
+// using the same naming scheme as Postgres: <table>_<column>_key.
+func uniqueName(tableName string, unique sqlschema.Unique) string {
+	if unique.Name != "" {
+		return unique.Name
+	}
+	return fmt.Sprintf("%s_%s_key", tableName, strings.Join(unique.Columns.Split(), "_"))
+}
+
+// foreignKeyName returns the name of the FOREIGN KEY constraint or generates one
+// using the same naming scheme as Postgres: <table>_<column>_fkey.
+func foreignKeyName(name string, fk sqlschema.ForeignKey) string {
+	if name != "" {
+		return name
+	}
+	colRef := fk.From
+	columns := strings.Join(colRef.Column.Split(), "_")
+	return fmt.Sprintf("%s_%s_fkey", colRef.TableName, columns)
+}
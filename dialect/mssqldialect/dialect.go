@@ -15,6 +15,7 @@ import (
 	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -35,12 +36,18 @@ func init() {
 type Dialect struct {
 	schema.BaseDialect
 
-	tables   *schema.Tables
-	features feature.Feature
+	tables    *schema.Tables
+	features  feature.Feature
+	maxValues int
 
 	unicode bool
 }
 
+var _ schema.Dialect = (*Dialect)(nil)
+var _ schema.MaxValuesDialect = (*Dialect)(nil)
+var _ sqlschema.InspectorDialect = (*Dialect)(nil)
+var _ sqlschema.MigratorDialect = (*Dialect)(nil)
+
 func New(opts ...DialectOption) *Dialect {
 	d := new(Dialect)
 	d.tables = schema.NewTables(d)
@@ -51,9 +58,11 @@ func New(opts ...DialectOption) *Dialect {
 		feature.OffsetFetch |
 		feature.FKDefaultOnAction |
 		feature.UpdateFromTable |
-		feature.MSSavepoint
+		feature.MSSavepoint |
+		feature.Merge
 
 	d.unicode = true
+	d.maxValues = 1000
 
 	for _, opt := range opts {
 		opt(d)
@@ -69,6 +78,15 @@ func WithoutFeature(other feature.Feature) DialectOption {
 	}
 }
 
+// WithMaxValues sets the maximum number of values in a single statement,
+// see schema.MaxValuesDialect. The default is 1000 values, so a chunk never exceeds
+// the limit of 1000 rows in VALUES nor the limit of 2100 parameters.
+func WithMaxValues(n int) DialectOption {
+	return func(d *Dialect) {
+		d.maxValues = n
+	}
+}
+
 func WithUnicode(on bool) DialectOption {
 	return func(d *Dialect) {
 		d.unicode = on
@@ -102,6 +120,10 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+func (d *Dialect) MaxValues() int {
+	return d.maxValues
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
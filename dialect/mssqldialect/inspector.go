@@ -0,0 +1,332 @@
+package mssqldialect
+
+import (
+	"context"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
+)
+
+type (
+	Schema = sqlschema.BaseDatabase
+	Table  = sqlschema.BaseTable
+	Column = sqlschema.BaseColumn
+)
+
+func (d *Dialect) NewInspector(db *bun.DB, options ...sqlschema.InspectorOption) sqlschema.Inspector {
+	return newInspector(db, options...)
+}
+
+type Inspector struct {
+	sqlschema.InspectorConfig
+	db *bun.DB
+}
+
+var _ sqlschema.Inspector = (*Inspector)(nil)
+
+func newInspector(db *bun.DB, options ...sqlschema.InspectorOption) *Inspector {
+	i := &Inspector{db: db}
+	i.SchemaName = db.Dialect().DefaultSchema()
+	sqlschema.ApplyInspectorOptions(&i.InspectorConfig, options...)
+	return i
+}
+
+func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
+	dbSchema := Schema{
+		ForeignKeys: make(map[sqlschema.ForeignKey]string),
+	}
+
+	var tables []*SysTable
+	if err := in.db.NewRaw(sqlInspectTables, in.SchemaName, in.excludeTables("t.name")).Scan(ctx, &tables); err != nil {
+		return dbSchema, err
+	}
+
+	for _, table := range tables {
+		var columns []*SysColumn
+		if err := in.db.NewRaw(sqlInspectColumns, table.ObjectID).Scan(ctx, &columns); err != nil {
+			return dbSchema, err
+		}
+
+		var colDefs []sqlschema.Column
+		for _, c := range columns {
+			colDefs = append(colDefs, c.toColumn())
+		}
+
+		var keys []*SysKeyColumn
+		if err := in.db.NewRaw(sqlInspectKeyConstraints, table.ObjectID).Scan(ctx, &keys); err != nil {
+			return dbSchema, err
+		}
+
+		var pk *sqlschema.PrimaryKey
+		var unique []sqlschema.Unique
+		for start := 0; start < len(keys); {
+			end := start
+			var columns []string
+			for end < len(keys) && keys[end].ConstraintName == keys[start].ConstraintName {
+				columns = append(columns, keys[end].ColumnName)
+				end++
+			}
+			key := keys[start]
+			start = end
+
+			if key.IsPrimaryKey {
+				pk = &sqlschema.PrimaryKey{
+					Name:    key.ConstraintName,
+					Columns: sqlschema.NewColumns(columns...),
+				}
+				continue
+			}
+			unique = append(unique, sqlschema.Unique{
+				Name:    key.ConstraintName,
+				Columns: sqlschema.NewColumns(columns...),
+			})
+		}
+
+		var indexColumns []*SysIndexColumn
+		if err := in.db.NewRaw(sqlInspectIndexes, table.ObjectID).Scan(ctx, &indexColumns); err != nil {
+			return dbSchema, err
+		}
+
+		var indexes []sqlschema.Index
+		for start := 0; start < len(indexColumns); {
+			end := start
+			var columns, include []string
+			for end < len(indexColumns) && indexColumns[end].IndexName == indexColumns[start].IndexName {
+				if indexColumns[end].IsIncluded {
+					include = append(include, indexColumns[end].ColumnName)
+				} else {
+					columns = append(columns, indexColumns[end].ColumnName)
+				}
+				end++
+			}
+			idx := indexColumns[start]
+			start = end
+
+			indexes = append(indexes, sqlschema.Index{
+				Name:    idx.IndexName,
+				Unique:  idx.IsUnique,
+				Columns: columns,
+				Include: include,
+				Where:   idx.Filter,
+			})
+		}
+
+		dbSchema.Tables = append(dbSchema.Tables, &Table{
+			Schema:            in.SchemaName,
+			Name:              table.Name,
+			Columns:           colDefs,
+			PrimaryKey:        pk,
+			UniqueConstraints: unique,
+			Indexes:           indexes,
+		})
+	}
+
+	var fks []*ForeignKey
+	if err := in.db.NewRaw(sqlInspectForeignKeys,
+		in.SchemaName, in.excludeTables("st.name"), in.excludeTables("tt.name"),
+	).Scan(ctx, &fks); err != nil {
+		return dbSchema, err
+	}
+
+	// Each row describes one column pair of the FOREIGN KEY constraint.
+	for start := 0; start < len(fks); {
+		end := start
+		var fromCols, toCols []string
+		for end < len(fks) && fks[end].ObjectID == fks[start].ObjectID {
+			fromCols = append(fromCols, fks[end].SourceColumn)
+			toCols = append(toCols, fks[end].TargetColumn)
+			end++
+		}
+		fk := fks[start]
+		start = end
+
+		dbFK := sqlschema.ForeignKey{
+			From: sqlschema.NewColumnReference(fk.SourceTable, fromCols...),
+			To:   sqlschema.NewColumnReference(fk.TargetTable, toCols...),
+		}
+		if _, exclude := in.ExcludeForeignKeys[dbFK]; exclude {
+			continue
+		}
+		dbSchema.ForeignKeys[dbFK] = fk.ConstraintName
+	}
+	return dbSchema, nil
+}
+
+// excludeTables creates a condition which filters out the tables matching any of the ExcludeTables patterns.
+func (in *Inspector) excludeTables(column string) schema.QueryAppender {
+	if len(in.ExcludeTables) == 0 {
+		return bun.Safe("1 = 1")
+	}
+	conds := make([]string, len(in.ExcludeTables))
+	args := make([]any, 0, 2*len(in.ExcludeTables))
+	for i, pattern := range in.ExcludeTables {
+		conds[i] = "? NOT LIKE ?"
+		args = append(args, bun.Safe(column), pattern)
+	}
+	return bun.SafeQuery(strings.Join(conds, " AND "), args...)
+}
+
+type SysTable struct {
+	ObjectID int64  `bun:"object_id"`
+	Name     string `bun:"table_name"`
+}
+
+type SysColumn struct {
+	Name       string `bun:"column_name"`
+	DataType   string `bun:"data_type"`
+	MaxLength  int    `bun:"max_length"`
+	IsNullable bool   `bun:"is_nullable"`
+	IsIdentity bool   `bun:"is_identity"`
+	Default    string `bun:"default"`
+}
+
+func (c *SysColumn) toColumn() *Column {
+	col := &Column{
+		Name:            c.Name,
+		SQLType:         strings.ToLower(c.DataType),
+		DefaultValue:    normalizeDefault(c.Default),
+		IsNullable:      c.IsNullable,
+		IsAutoIncrement: c.IsIdentity,
+	}
+
+	switch col.SQLType {
+	case "char", "varchar", "binary", "varbinary", "nchar", "nvarchar":
+		switch {
+		case c.MaxLength == -1:
+			col.SQLType += "(max)"
+		case strings.HasPrefix(col.SQLType, "n"):
+			// max_length is reported in bytes and Unicode types use 2 bytes per character.
+			col.VarcharLen = c.MaxLength / 2
+		default:
+			col.VarcharLen = c.MaxLength
+		}
+	}
+	return col
+}
+
+// normalizeDefault trims parentheses which SQL Server adds around every DEFAULT expression,
+// as well as the quotes around string literals, and lowercases expressions.
+// This is the convention sqlschema.BunModelInspector uses for the model's defaults.
+func normalizeDefault(s string) string {
+	for len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		s = s[1 : len(s)-1]
+	}
+	if lit := strings.TrimPrefix(s, "N"); len(lit) >= 2 && lit[0] == '\'' && lit[len(lit)-1] == '\'' {
+		return strings.ReplaceAll(lit[1:len(lit)-1], "''", "'")
+	}
+	return strings.ToLower(s)
+}
+
+type SysKeyColumn struct {
+	ConstraintName string `bun:"constraint_name"`
+	IsPrimaryKey   bool   `bun:"is_primary_key"`
+	ColumnName     string `bun:"column_name"`
+}
+
+type SysIndexColumn struct {
+	IndexName  string `bun:"index_name"`
+	IsUnique   bool   `bun:"is_unique"`
+	Filter     string `bun:"filter"`
+	ColumnName string `bun:"column_name"`
+	IsIncluded bool   `bun:"is_included_column"`
+}
+
+type ForeignKey struct {
+	ObjectID       int64  `bun:"object_id"`
+	ConstraintName string `bun:"constraint_name"`
+	SourceTable    string `bun:"table_name"`
+	SourceColumn   string `bun:"column_name"`
+	TargetTable    string `bun:"target_table"`
+	TargetColumn   string `bun:"target_column"`
+}
+
+const (
+	// sqlInspectTables retrieves all user-defined tables in the selected schema.
+	// Pass schema name and the condition excluding tables from this inspection as arguments.
+	sqlInspectTables = `
+SELECT t.object_id, t.name AS table_name
+FROM sys.tables AS t
+	JOIN sys.schemas AS s ON s.schema_id = t.schema_id
+WHERE s.name = ?
+	AND t.is_ms_shipped = 0
+	AND ?
+ORDER BY t.name
+`
+
+	// sqlInspectColumns retrieves column definitions for the specified table.
+	// Pass table's object_id as argument.
+	sqlInspectColumns = `
+SELECT
+	c.name AS column_name,
+	ty.name AS data_type,
+	c.max_length,
+	c.is_nullable,
+	c.is_identity,
+	COALESCE(dc.definition, '') AS "default"
+FROM sys.columns AS c
+	JOIN sys.types AS ty ON ty.user_type_id = c.user_type_id
+	LEFT JOIN sys.default_constraints AS dc ON dc.object_id = c.default_object_id
+WHERE c.object_id = ?
+ORDER BY c.column_id
+`
+
+	// sqlInspectKeyConstraints retrieves the columns of PRIMARY KEY and UNIQUE constraints on the table.
+	// Pass table's object_id as argument.
+	sqlInspectKeyConstraints = `
+SELECT
+	kc.name AS constraint_name,
+	CAST(CASE WHEN kc.type = 'PK' THEN 1 ELSE 0 END AS BIT) AS is_primary_key,
+	c.name AS column_name
+FROM sys.key_constraints AS kc
+	JOIN sys.index_columns AS ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+	JOIN sys.columns AS c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE kc.parent_object_id = ?
+ORDER BY kc.name, ic.key_ordinal
+`
+
+	// sqlInspectIndexes retrieves the columns of the indexes on the table,
+	// except the ones which back PRIMARY KEY and UNIQUE constraints.
+	// Pass table's object_id as argument.
+	sqlInspectIndexes = `
+SELECT
+	i.name AS index_name,
+	i.is_unique,
+	COALESCE(i.filter_definition, '') AS filter,
+	c.name AS column_name,
+	ic.is_included_column
+FROM sys.indexes AS i
+	JOIN sys.index_columns AS ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+	JOIN sys.columns AS c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE i.object_id = ?
+	AND i.type IN (1, 2)
+	AND i.is_hypothetical = 0
+	AND i.is_primary_key = 0
+	AND i.is_unique_constraint = 0
+ORDER BY i.name, ic.is_included_column, ic.key_ordinal, ic.index_column_id
+`
+
+	// sqlInspectForeignKeys retrieves FOREIGN KEY constraints between the tables in the selected schema.
+	// Pass schema name and the conditions excluding source and target tables from this inspection as arguments.
+	sqlInspectForeignKeys = `
+SELECT
+	fk.object_id,
+	fk.name AS constraint_name,
+	st.name AS table_name,
+	sc.name AS column_name,
+	tt.name AS target_table,
+	tc.name AS target_column
+FROM sys.foreign_keys AS fk
+	JOIN sys.foreign_key_columns AS fkc ON fkc.constraint_object_id = fk.object_id
+	JOIN sys.tables AS st ON st.object_id = fk.parent_object_id
+	JOIN sys.columns AS sc ON sc.object_id = fkc.parent_object_id AND sc.column_id = fkc.parent_column_id
+	JOIN sys.tables AS tt ON tt.object_id = fk.referenced_object_id
+	JOIN sys.columns AS tc ON tc.object_id = fkc.referenced_object_id AND tc.column_id = fkc.referenced_column_id
+WHERE SCHEMA_NAME(st.schema_id) = ?
+	AND tt.schema_id = st.schema_id
+	AND ?
+	AND ?
+ORDER BY fk.object_id, fkc.constraint_column_id
+`
+)
@@ -0,0 +1,89 @@
+package mssqldialect
+
+import (
+	"strings"
+
+	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+const (
+	// Numeric Types
+	mssqlTypeInt     = "INT"     // alias for INTEGER
+	mssqlTypeFloat   = "FLOAT"   // FLOAT(53) is the same as DOUBLE PRECISION
+	mssqlTypeDecimal = "DECIMAL" // fixed-point number
+	mssqlTypeNumeric = "NUMERIC" // functionally equivalent to DECIMAL
+
+	// Character Types
+	mssqlTypeChar             = "CHAR"                       // fixed length string
+	mssqlTypeCharacter        = "CHARACTER"                  // alias for CHAR
+	mssqlTypeCharacterVarying = "CHARACTER VARYING"          // alias for VARCHAR
+	mssqlTypeNVarchar         = "NVARCHAR"                   // variable length Unicode string
+	mssqlTypeNationalVarying  = "NATIONAL CHARACTER VARYING" // alias for NVARCHAR
+)
+
+var (
+	boolean  = newAliases(sqltype.Boolean, bitType)
+	integer  = newAliases(sqltype.Integer, mssqlTypeInt)
+	double   = newAliases(sqltype.DoublePrecision, mssqlTypeFloat)
+	decimal  = newAliases(mssqlTypeDecimal, mssqlTypeNumeric)
+	char     = newAliases(mssqlTypeChar, mssqlTypeCharacter)
+	varchar  = newAliases(sqltype.VarChar, mssqlTypeCharacterVarying)
+	nvarchar = newAliases(mssqlTypeNVarchar, mssqlTypeNationalVarying)
+)
+
+func (d *Dialect) CompareType(col1, col2 sqlschema.Column) bool {
+	typ1, typ2 := strings.ToUpper(col1.GetSQLType()), strings.ToUpper(col2.GetSQLType())
+
+	if typ1 == typ2 {
+		return checkVarcharLen(col1, col2, d.DefaultVarcharLen())
+	}
+
+	switch {
+	case char.IsAlias(typ1) && char.IsAlias(typ2),
+		varchar.IsAlias(typ1) && varchar.IsAlias(typ2),
+		nvarchar.IsAlias(typ1) && nvarchar.IsAlias(typ2):
+		return checkVarcharLen(col1, col2, d.DefaultVarcharLen())
+	case boolean.IsAlias(typ1) && boolean.IsAlias(typ2),
+		integer.IsAlias(typ1) && integer.IsAlias(typ2),
+		double.IsAlias(typ1) && double.IsAlias(typ2),
+		decimal.IsAlias(typ1) && decimal.IsAlias(typ2):
+		return true
+	}
+	return false
+}
+
+// checkVarcharLen returns true if columns have the same VarcharLen, or,
+// if one specifies no VarcharLen and the other one has the default length for mssqldialect.
+// We assume that the types are otherwise equivalent and that any non-character column
+// would have VarcharLen == 0;
+func checkVarcharLen(col1, col2 sqlschema.Column, defaultLen int) bool {
+	vl1, vl2 := col1.GetVarcharLen(), col2.GetVarcharLen()
+
+	if vl1 == vl2 {
+		return true
+	}
+
+	if (vl1 == 0 && vl2 == defaultLen) || (vl1 == defaultLen && vl2 == 0) {
+		return true
+	}
+	return false
+}
+
+// typeAlias defines aliases for common data types. It is a lightweight string set implementation.
+type typeAlias map[string]struct{}
+
+// IsAlias checks if typ1 and typ2 are aliases of the same data type.
+func (t typeAlias) IsAlias(typ string) bool {
+	_, ok := t[typ]
+	return ok
+}
+
+// newAliases creates a set of aliases.
+func newAliases(aliases ...string) typeAlias {
+	types := make(typeAlias)
+	for _, a := range aliases {
+		types[a] = struct{}{}
+	}
+	return types
+}
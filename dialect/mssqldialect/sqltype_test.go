@@ -0,0 +1,76 @@
+package mssqldialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+func TestInspectorDialect_CompareType(t *testing.T) {
+	d := New()
+
+	t.Run("common types", func(t *testing.T) {
+		for _, tt := range []struct {
+			typ1, typ2 string
+			want       bool
+		}{
+			{"text", "text", true},     // identical types
+			{"bigint", "BIGINT", true}, // case-insensitive
+			{"nvarchar(max)", nvarcharType, true},
+			{"varbinary(max)", varbinaryType, true},
+			{"nvarchar(max)", varbinaryType, false},
+
+			{"int", sqltype.Integer, true},
+			{"bit", sqltype.Boolean, true},
+			{"float", sqltype.DoublePrecision, true},
+			{"decimal", "numeric", true},
+			{"datetime", datetimeType, true},
+			{"datetime2", datetimeType, false},
+
+			{sqltype.VarChar, mssqlTypeCharacterVarying, true},
+			{sqltype.VarChar, mssqlTypeNVarchar, false},
+			{mssqlTypeNVarchar, mssqlTypeNationalVarying, true},
+			{mssqlTypeCharacter, mssqlTypeChar, true},
+		} {
+			eq := " ~ "
+			if !tt.want {
+				eq = " !~ "
+			}
+			t.Run(tt.typ1+eq+tt.typ2, func(t *testing.T) {
+				got := d.CompareType(
+					&sqlschema.BaseColumn{SQLType: tt.typ1},
+					&sqlschema.BaseColumn{SQLType: tt.typ2},
+				)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("custom varchar length", func(t *testing.T) {
+		for _, tt := range []struct {
+			name       string
+			col1, col2 sqlschema.BaseColumn
+			want       bool
+		}{
+			{
+				name: "varchars of different length are not equivalent",
+				col1: sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 10},
+				col2: sqlschema.BaseColumn{SQLType: "varchar"},
+				want: false,
+			},
+			{
+				name: "varchar with no explicit length is equivalent to varchar of default length",
+				col1: sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: d.DefaultVarcharLen()},
+				col2: sqlschema.BaseColumn{SQLType: "varchar"},
+				want: true,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				got := d.CompareType(&tt.col1, &tt.col2)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+}
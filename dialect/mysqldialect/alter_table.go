@@ -0,0 +1,364 @@
+package mysqldialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
+)
+
+func (d *Dialect) NewMigrator(db *bun.DB, schemaName string) sqlschema.Migrator {
+	return &migrator{db: db, schemaName: schemaName, BaseMigrator: sqlschema.NewBaseMigrator(db)}
+}
+
+// migrator generates ALTER TABLE statements for MySQL and MariaDB.
+//
+// Column definitions in MySQL cannot be altered piecemeal, so MODIFY COLUMN
+// always re-states the complete definition of the target column.
+// Renaming columns relies on RENAME COLUMN, which requires MySQL 8.0 or MariaDB 10.5.2.
+type migrator struct {
+	*sqlschema.BaseMigrator
+
+	db         *bun.DB
+	schemaName string
+}
+
+var _ sqlschema.Migrator = (*migrator)(nil)
+
+func (m *migrator) AppendSQL(b []byte, operation any) (_ []byte, err error) {
+	gen := m.db.QueryGen()
+
+	// Append ALTER TABLE statement to the enclosed query bytes []byte.
+	appendAlterTable := func(query []byte, tableName string) []byte {
+		query = append(query, "ALTER TABLE "...)
+		query = m.appendFQN(gen, query, tableName)
+		return append(query, " "...)
+	}
+
+	switch change := operation.(type) {
+	case *migrate.CreateTableOp:
+		if change.Model == nil {
+			b = append(b, "CREATE TABLE "...)
+			b = m.appendFQN(gen, b, change.TableName)
+			return m.AppendTableDefinition(b, change.Table)
+		}
+		return m.AppendCreateTable(b, change.Model)
+	case *migrate.DropTableOp:
+		b = append(b, "DROP TABLE "...)
+		return m.appendFQN(gen, b, change.TableName), nil
+	case *migrate.RenameTableOp:
+		b, err = m.renameTable(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.RenameColumnOp:
+		b, err = m.renameColumn(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.AddColumnOp:
+		b, err = m.addColumn(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.DropColumnOp:
+		b, err = m.dropColumn(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.AddPrimaryKeyOp:
+		b, err = m.addPrimaryKey(gen, appendAlterTable(b, change.TableName), change.PrimaryKey)
+	case *migrate.ChangePrimaryKeyOp:
+		b, err = m.changePrimaryKey(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.DropPrimaryKeyOp:
+		b, err = m.dropPrimaryKey(gen, appendAlterTable(b, change.TableName))
+	case *migrate.AddUniqueConstraintOp:
+		b, err = m.addUnique(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.DropUniqueConstraintOp:
+		b, err = m.dropUnique(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.ChangeColumnTypeOp:
+		b, err = m.changeColumnType(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.AddForeignKeyOp:
+		b, err = m.addForeignKey(gen, appendAlterTable(b, change.TableName()), change)
+	case *migrate.DropForeignKeyOp:
+		b, err = m.dropForeignKey(gen, appendAlterTable(b, change.TableName()), change)
+	case *migrate.CreateIndexOp:
+		b, err = m.createIndex(gen, b, change)
+	case *migrate.DropIndexOp:
+		b = append(b, "DROP INDEX "...)
+		b = gen.AppendName(b, sqlschema.IndexName(change.TableName, change.Index))
+		b = append(b, " ON "...)
+		b = m.appendFQN(gen, b, change.TableName)
+	default:
+		return nil, fmt.Errorf("append sql: unknown operation %T", change)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("append sql: %w", err)
+	}
+	return b, nil
+}
+
+// appendFQN appends the table name, qualified with the database name unless
+// it is the dialect's default schema, which refers to the current database.
+func (m *migrator) appendFQN(gen schema.QueryGen, b []byte, tableName string) []byte {
+	if m.schemaName == "" || m.schemaName == m.db.Dialect().DefaultSchema() {
+		return gen.AppendName(b, tableName)
+	}
+	return gen.AppendQuery(b, "?.?", bun.Ident(m.schemaName), bun.Ident(tableName))
+}
+
+func (m *migrator) renameTable(gen schema.QueryGen, b []byte, rename *migrate.RenameTableOp) (_ []byte, err error) {
+	b = append(b, "RENAME TO "...)
+	b = gen.AppendName(b, rename.NewName)
+	return b, nil
+}
+
+func (m *migrator) renameColumn(gen schema.QueryGen, b []byte, rename *migrate.RenameColumnOp) (_ []byte, err error) {
+	b = append(b, "RENAME COLUMN "...)
+	b = gen.AppendName(b, rename.OldName)
+
+	b = append(b, " TO "...)
+	b = gen.AppendName(b, rename.NewName)
+
+	return b, nil
+}
+
+func (m *migrator) addColumn(gen schema.QueryGen, b []byte, add *migrate.AddColumnOp) (_ []byte, err error) {
+	b = append(b, "ADD COLUMN "...)
+	b = gen.AppendName(b, add.ColumnName)
+	b = append(b, " "...)
+
+	return appendColumnDefinition(gen, b, add.Column)
+}
+
+func (m *migrator) dropColumn(gen schema.QueryGen, b []byte, drop *migrate.DropColumnOp) (_ []byte, err error) {
+	b = append(b, "DROP COLUMN "...)
+	b = gen.AppendName(b, drop.ColumnName)
+
+	return b, nil
+}
+
+func (m *migrator) addPrimaryKey(gen schema.QueryGen, b []byte, pk sqlschema.PrimaryKey) (_ []byte, err error) {
+	b = append(b, "ADD PRIMARY KEY ("...)
+	b, _ = pk.Columns.AppendQuery(gen, b)
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+func (m *migrator) changePrimaryKey(gen schema.QueryGen, b []byte, change *migrate.ChangePrimaryKeyOp) (_ []byte, err error) {
+	b, _ = m.dropPrimaryKey(gen, b)
+	b = append(b, ", "...)
+	b, _ = m.addPrimaryKey(gen, b, change.New)
+	return b, nil
+}
+
+func (m *migrator) dropPrimaryKey(_ schema.QueryGen, b []byte) (_ []byte, err error) {
+	return append(b, "DROP PRIMARY KEY"...), nil
+}
+
+func (m *migrator) addUnique(gen schema.QueryGen, b []byte, change *migrate.AddUniqueConstraintOp) (_ []byte, err error) {
+	b = append(b, "ADD CONSTRAINT "...)
+	b = gen.AppendName(b, uniqueName(change.TableName, change.Unique))
+	b = append(b, " UNIQUE ("...)
+	b, _ = change.Unique.Columns.AppendQuery(gen, b)
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+// dropUnique drops the index which backs the UNIQUE constraint.
+func (m *migrator) dropUnique(gen schema.QueryGen, b []byte, change *migrate.DropUniqueConstraintOp) (_ []byte, err error) {
+	b = append(b, "DROP INDEX "...)
+	b = gen.AppendName(b, uniqueName(change.TableName, change.Unique))
+
+	return b, nil
+}
+
+func (m *migrator) addForeignKey(gen schema.QueryGen, b []byte, add *migrate.AddForeignKeyOp) (_ []byte, err error) {
+	b = append(b, "ADD CONSTRAINT "...)
+	b = gen.AppendName(b, foreignKeyName(add.ConstraintName, add.ForeignKey))
+
+	b = append(b, " FOREIGN KEY ("...)
+	if b, err = add.ForeignKey.From.Column.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+	b = append(b, ")"...)
+
+	b = append(b, " REFERENCES "...)
+	b = m.appendFQN(gen, b, add.ForeignKey.To.TableName)
+
+	b = append(b, " ("...)
+	if b, err = add.ForeignKey.To.Column.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+func (m *migrator) dropForeignKey(gen schema.QueryGen, b []byte, drop *migrate.DropForeignKeyOp) (_ []byte, err error) {
+	b = append(b, "DROP FOREIGN KEY "...)
+	b = gen.AppendName(b, foreignKeyName(drop.ConstraintName, drop.ForeignKey))
+
+	return b, nil
+}
+
+// createIndex appends CREATE INDEX statement. FULLTEXT and SPATIAL index types are
+// declared with a keyword before INDEX, other access methods in the USING clause.
+// MySQL does not support partial indexes and INCLUDE columns.
+func (m *migrator) createIndex(gen schema.QueryGen, b []byte, create *migrate.CreateIndexOp) (_ []byte, err error) {
+	index := create.Index
+	name := sqlschema.IndexName(create.TableName, index)
+	if index.Where != "" || len(index.Include) > 0 {
+		return b, fmt.Errorf("index %q: MySQL does not support WHERE and INCLUDE clauses", name)
+	}
+
+	b = append(b, "CREATE "...)
+	using := strings.ToUpper(index.Using)
+	switch {
+	case index.Unique:
+		b = append(b, "UNIQUE "...)
+	case using == "FULLTEXT", using == "SPATIAL":
+		b = append(b, using...)
+		b = append(b, " "...)
+		using = ""
+	}
+	b = append(b, "INDEX "...)
+	b = gen.AppendName(b, name)
+	b = append(b, " ON "...)
+	b = m.appendFQN(gen, b, create.TableName)
+	b = append(b, " ("...)
+	if index.Expr != "" {
+		// Functional key parts must be enclosed in parentheses (MySQL 8.0.13+).
+		b = append(b, '(')
+		b = append(b, index.Expr...)
+		b = append(b, ')')
+	} else {
+		b = appendNames(gen, b, index.Columns)
+	}
+	b = append(b, ")"...)
+
+	if using != "" {
+		b = append(b, " USING "...)
+		b = append(b, using...)
+	}
+	return b, nil
+}
+
+// changeColumnType re-defines the column with MODIFY COLUMN. Unlike other dialects,
+// MySQL resets any attribute (nullability, default, AUTO_INCREMENT) that is omitted
+// from the new column definition, so the complete target definition is appended.
+func (m *migrator) changeColumnType(gen schema.QueryGen, b []byte, colDef *migrate.ChangeColumnTypeOp) (_ []byte, err error) {
+	if colDef.To.GetSQLType() == "" {
+		return b, fmt.Errorf("mysql: cannot modify column %s.%s: data type is not specified", colDef.TableName, colDef.Column)
+	}
+
+	b = append(b, "MODIFY COLUMN "...)
+	b = gen.AppendName(b, colDef.Column)
+	b = append(b, " "...)
+
+	return appendColumnDefinition(gen, b, colDef.To)
+}
+
+// appendColumnDefinition appends data type, nullability, default value and AUTO_INCREMENT attribute of the column.
+func appendColumnDefinition(gen schema.QueryGen, b []byte, col sqlschema.Column) (_ []byte, err error) {
+	if b, err = col.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+
+	if !col.GetIsNullable() {
+		b = append(b, " NOT NULL"...)
+	}
+
+	if def := col.GetDefaultValue(); def != "" {
+		b = append(b, " DEFAULT "...)
+		b = appendDefault(gen, b, def)
+	}
+
+	// MySQL has no identity columns, AUTO_INCREMENT is the closest equivalent.
+	if col.GetIsAutoIncrement() || col.GetIsIdentity() {
+		b = append(b, " AUTO_INCREMENT"...)
+	}
+	return b, nil
+}
+
+// appendDefault appends the default value of a column. sqlschema.Column reports string literals
+// without quotes, so the value is quoted unless it is a number, a keyword or an expression.
+func appendDefault(gen schema.QueryGen, b []byte, def string) []byte {
+	switch {
+	case isConstant(def), strings.HasPrefix(def, "("):
+		return append(b, def...)
+	case isFunctionCall(def):
+		// Expressions other than CURRENT_TIMESTAMP must be enclosed in parentheses (MySQL 8.0.13+).
+		if isCurrentTimestamp(def) {
+			return append(b, def...)
+		}
+		b = append(b, '(')
+		b = append(b, def...)
+		return append(b, ')')
+	}
+	return gen.Dialect().AppendString(b, def)
+}
+
+// isConstant reports whether the value can be used in the DEFAULT clause as-is.
+func isConstant(s string) bool {
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "true", "false":
+		return true
+	}
+	return isCurrentTimestamp(s) && !strings.Contains(s, "(")
+}
+
+// isCurrentTimestamp reports whether s is CURRENT_TIMESTAMP or one of its synonyms,
+// which MySQL accepts in the DEFAULT clause without enclosing parentheses.
+func isCurrentTimestamp(s string) bool {
+	name := strings.ToLower(s)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "current_timestamp", "now", "localtime", "localtimestamp":
+		return true
+	}
+	return false
+}
+
+// isFunctionCall reports whether s looks like a function call, e.g. uuid().
+func isFunctionCall(s string) bool {
+	i := strings.IndexByte(s, '(')
+	if i <= 0 || !strings.HasSuffix(s, ")") {
+		return false
+	}
+	for _, r := range s[:i] {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func appendNames(gen schema.QueryGen, b []byte, names []string) []byte {
+	for i, name := range names {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = gen.AppendName(b, name)
+	}
+	return b
+}
+
+// uniqueName returns the name of the UNIQUE constraint or generates one
+// using the same naming scheme as Postgres: <table>_<column>_key.
+func uniqueName(tableName string, unique sqlschema.Unique) string {
+	if unique.Name != "" {
+		return unique.Name
+	}
+	return fmt.Sprintf("%s_%s_key", tableName, strings.Join(unique.Columns.Split(), "_"))
+}
+
+// foreignKeyName returns the name of the FOREIGN KEY constraint or generates one
+// using the same naming scheme as Postgres: <table>_<column>_fkey.
+func foreignKeyName(name string, fk sqlschema.ForeignKey) string {
+	if name != "" {
+		return name
+	}
+	colRef := fk.From
+	columns := strings.Join(colRef.Column.Split(), "_")
+	return fmt.Sprintf("%s_%s_fkey", colRef.TableName, columns)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -30,11 +31,17 @@ func init() {
 type Dialect struct {
 	schema.BaseDialect
 
-	tables   *schema.Tables
-	features feature.Feature
-	loc      *time.Location
+	tables    *schema.Tables
+	features  feature.Feature
+	maxValues int
+	loc       *time.Location
 }
 
+var _ schema.Dialect = (*Dialect)(nil)
+var _ schema.MaxValuesDialect = (*Dialect)(nil)
+var _ sqlschema.InspectorDialect = (*Dialect)(nil)
+var _ sqlschema.MigratorDialect = (*Dialect)(nil)
+
 func New(opts ...DialectOption) *Dialect {
 	d := new(Dialect)
 	d.tables = schema.NewTables(d)
@@ -50,7 +57,12 @@ func New(opts ...DialectOption) *Dialect {
 		feature.CompositeIn |
 		feature.FKDefaultOnAction |
 		feature.UpdateOrderLimit |
-		feature.DeleteOrderLimit
+		feature.DeleteOrderLimit |
+		feature.RowValueCompare |
+		feature.NamedWindow |
+		feature.WindowRangeOffset
+
+	d.maxValues = 65535
 
 	for _, opt := range opts {
 		opt(d)
@@ -77,6 +89,15 @@ func WithoutFeature(other feature.Feature) DialectOption {
 	}
 }
 
+// WithMaxValues sets the maximum number of values in a single statement,
+// see schema.MaxValuesDialect. The default is 65535, the limit of placeholders
+// in a prepared statement.
+func WithMaxValues(n int) DialectOption {
+	return func(d *Dialect) {
+		d.maxValues = n
+	}
+}
+
 func (d *Dialect) Init(db *sql.DB) {
 	var version string
 	if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
@@ -119,6 +140,10 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+func (d *Dialect) MaxValues() int {
+	return d.maxValues
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
@@ -0,0 +1,358 @@
+package mysqldialect
+
+import (
+	"context"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
+)
+
+type (
+	Schema = sqlschema.BaseDatabase
+	Table  = sqlschema.BaseTable
+	Column = sqlschema.BaseColumn
+)
+
+func (d *Dialect) NewInspector(db *bun.DB, options ...sqlschema.InspectorOption) sqlschema.Inspector {
+	return newInspector(db, options...)
+}
+
+type Inspector struct {
+	sqlschema.InspectorConfig
+	db *bun.DB
+}
+
+var _ sqlschema.Inspector = (*Inspector)(nil)
+
+func newInspector(db *bun.DB, options ...sqlschema.InspectorOption) *Inspector {
+	i := &Inspector{db: db}
+	i.SchemaName = db.Dialect().DefaultSchema()
+	sqlschema.ApplyInspectorOptions(&i.InspectorConfig, options...)
+	return i
+}
+
+func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
+	dbSchema := Schema{
+		ForeignKeys: make(map[sqlschema.ForeignKey]string),
+	}
+	schemaName := schemaExpr(in.db, in.SchemaName)
+
+	var tables []*InformationSchemaTable
+	q := in.db.NewRaw(sqlInspectTables, schemaName, in.excludeTables("table_name"))
+	if err := q.Scan(ctx, &tables); err != nil {
+		return dbSchema, err
+	}
+
+	var isMariaDB bool
+	if err := in.db.NewRaw("SELECT VERSION() LIKE '%MariaDB%'").Scan(ctx, &isMariaDB); err != nil {
+		return dbSchema, err
+	}
+
+	for _, table := range tables {
+		var columns []*InformationSchemaColumn
+		if err := in.db.NewRaw(sqlInspectColumns, schemaName, table.Name).Scan(ctx, &columns); err != nil {
+			return dbSchema, err
+		}
+
+		var colDefs []sqlschema.Column
+		for _, c := range columns {
+			colDefs = append(colDefs, c.toColumn(isMariaDB))
+		}
+
+		var indexes []*InformationSchemaIndex
+		if err := in.db.NewRaw(sqlInspectUniqueIndexes, schemaName, table.Name).Scan(ctx, &indexes); err != nil {
+			return dbSchema, err
+		}
+
+		var pk *sqlschema.PrimaryKey
+		var unique []sqlschema.Unique
+		for _, idx := range groupIndexColumns(indexes) {
+			// Functional key parts (MySQL 8.0.13+) do not reference any column
+			// and cannot be represented as a sqlschema.Unique.
+			if idx.functional {
+				continue
+			}
+			if idx.name == "PRIMARY" {
+				pk = &sqlschema.PrimaryKey{
+					Name:    idx.name,
+					Columns: sqlschema.NewColumns(idx.columns...),
+				}
+				continue
+			}
+			unique = append(unique, sqlschema.Unique{
+				Name:    idx.name,
+				Columns: sqlschema.NewColumns(idx.columns...),
+			})
+		}
+
+		var nonUnique []*InformationSchemaIndex
+		if err := in.db.NewRaw(sqlInspectIndexes, schemaName, table.Name).Scan(ctx, &nonUnique); err != nil {
+			return dbSchema, err
+		}
+
+		var idxDefs []sqlschema.Index
+		for _, idx := range groupIndexColumns(nonUnique) {
+			if idx.functional {
+				continue
+			}
+			index := sqlschema.Index{
+				Name:    idx.name,
+				Columns: idx.columns,
+			}
+			if idx.indexType != "BTREE" {
+				index.Using = strings.ToLower(idx.indexType)
+			}
+			idxDefs = append(idxDefs, index)
+		}
+
+		dbSchema.Tables = append(dbSchema.Tables, &Table{
+			Schema:            in.SchemaName,
+			Name:              table.Name,
+			Columns:           colDefs,
+			PrimaryKey:        pk,
+			UniqueConstraints: unique,
+			Indexes:           idxDefs,
+		})
+	}
+
+	var fks []*ForeignKey
+	if err := in.db.NewRaw(sqlInspectForeignKeys,
+		schemaName, in.excludeTables("table_name"), in.excludeTables("referenced_table_name"),
+	).Scan(ctx, &fks); err != nil {
+		return dbSchema, err
+	}
+
+	// Each row describes one column pair of the FOREIGN KEY constraint.
+	for start := 0; start < len(fks); {
+		end := start
+		for end < len(fks) && fks[end].SourceTable == fks[start].SourceTable &&
+			fks[end].ConstraintName == fks[start].ConstraintName {
+			end++
+		}
+
+		var fromCols, toCols []string
+		for _, row := range fks[start:end] {
+			fromCols = append(fromCols, row.SourceColumn)
+			toCols = append(toCols, row.TargetColumn)
+		}
+		fk := fks[start]
+		start = end
+
+		dbFK := sqlschema.ForeignKey{
+			From: sqlschema.NewColumnReference(fk.SourceTable, fromCols...),
+			To:   sqlschema.NewColumnReference(fk.TargetTable, toCols...),
+		}
+		if _, exclude := in.ExcludeForeignKeys[dbFK]; exclude {
+			continue
+		}
+		dbSchema.ForeignKeys[dbFK] = fk.ConstraintName
+	}
+	return dbSchema, nil
+}
+
+// excludeTables creates a condition which filters out the tables matching any of the ExcludeTables patterns.
+func (in *Inspector) excludeTables(column string) schema.QueryAppender {
+	if len(in.ExcludeTables) == 0 {
+		return bun.Safe("TRUE")
+	}
+	conds := make([]string, len(in.ExcludeTables))
+	args := make([]any, 0, 2*len(in.ExcludeTables))
+	for i, pattern := range in.ExcludeTables {
+		conds[i] = "? NOT LIKE ?"
+		args = append(args, bun.Ident(column), pattern)
+	}
+	return bun.SafeQuery(strings.Join(conds, " AND "), args...)
+}
+
+// schemaExpr returns an expression for the name of the inspected database.
+//
+// MySQL does not distinguish between schemas and databases and the dialect's
+// default schema name is only a placeholder, so it is resolved to the database
+// the connection is currently using.
+func schemaExpr(db *bun.DB, schemaName string) any {
+	if schemaName == "" || schemaName == db.Dialect().DefaultSchema() {
+		return bun.Safe("DATABASE()")
+	}
+	return schemaName
+}
+
+type indexInfo struct {
+	name       string
+	indexType  string
+	columns    []string
+	functional bool
+}
+
+// groupIndexColumns collects the columns of each index, preserving their order.
+func groupIndexColumns(rows []*InformationSchemaIndex) []*indexInfo {
+	var indexes []*indexInfo
+	for _, row := range rows {
+		if n := len(indexes); n == 0 || indexes[n-1].name != row.IndexName {
+			indexes = append(indexes, &indexInfo{name: row.IndexName, indexType: row.IndexType})
+		}
+		idx := indexes[len(indexes)-1]
+		if row.ColumnName == "" {
+			idx.functional = true
+			continue
+		}
+		idx.columns = append(idx.columns, row.ColumnName)
+	}
+	return indexes
+}
+
+type InformationSchemaTable struct {
+	Name string `bun:"table_name"`
+}
+
+type InformationSchemaColumn struct {
+	Name       string `bun:"column_name"`
+	DataType   string `bun:"data_type"`
+	ColumnType string `bun:"column_type"`
+	VarcharLen int    `bun:"varchar_len"`
+	HasDefault bool   `bun:"has_default"`
+	Default    string `bun:"default"`
+	IsNullable bool   `bun:"is_nullable"`
+	Extra      string `bun:"extra"`
+}
+
+func (c *InformationSchemaColumn) toColumn(isMariaDB bool) *Column {
+	col := &Column{
+		Name:            c.Name,
+		SQLType:         strings.ToLower(c.ColumnType),
+		IsNullable:      c.IsNullable,
+		IsAutoIncrement: strings.Contains(strings.ToLower(c.Extra), "auto_increment"),
+	}
+
+	// Length of character types is reported separately to make them comparable
+	// to the columns declared in bun models, e.g. "varchar(255)" -> "varchar", 255.
+	switch dataType := strings.ToLower(c.DataType); dataType {
+	case "char", "varchar", "binary", "varbinary":
+		col.SQLType = dataType
+		col.VarcharLen = c.VarcharLen
+	}
+
+	if c.HasDefault {
+		col.DefaultValue = normalizeDefault(c.Default, c.Extra, isMariaDB)
+	}
+	return col
+}
+
+// normalizeDefault trims quotes around string literals and lowercases expressions,
+// which is the convention sqlschema.BunModelInspector uses for the model's defaults.
+//
+// MySQL reports string literals without quotes and marks expressions with DEFAULT_GENERATED
+// (except for CURRENT_TIMESTAMP in MySQL 5.7), while MariaDB quotes string literals
+// and reports explicit NULL defaults as "NULL".
+func normalizeDefault(s, extra string, isMariaDB bool) string {
+	switch {
+	case isMariaDB && s == "NULL":
+		return ""
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	case isMariaDB,
+		strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED"),
+		strings.HasPrefix(strings.ToUpper(s), "CURRENT_TIMESTAMP"):
+		for len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+			s = s[1 : len(s)-1]
+		}
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+type InformationSchemaIndex struct {
+	IndexName  string `bun:"index_name"`
+	IndexType  string `bun:"index_type"`
+	ColumnName string `bun:"column_name"`
+}
+
+type ForeignKey struct {
+	ConstraintName string `bun:"constraint_name"`
+	SourceTable    string `bun:"table_name"`
+	SourceColumn   string `bun:"column_name"`
+	TargetTable    string `bun:"target_table"`
+	TargetColumn   string `bun:"target_column"`
+}
+
+const (
+	// sqlInspectTables retrieves all user-defined tables in the selected database.
+	// Pass database name and the condition excluding tables from this inspection as arguments.
+	sqlInspectTables = `
+SELECT table_name AS table_name
+FROM information_schema.tables
+WHERE table_schema = ?
+	AND table_type = 'BASE TABLE'
+	AND ?
+ORDER BY table_name
+`
+
+	// sqlInspectColumns retrieves column definitions for the specified table.
+	// Pass database name and table name as arguments.
+	sqlInspectColumns = `
+SELECT
+	column_name AS column_name,
+	data_type AS data_type,
+	column_type AS column_type,
+	COALESCE(character_maximum_length, 0) AS varchar_len,
+	column_default IS NOT NULL AS has_default,
+	COALESCE(column_default, '') AS ` + "`default`" + `,
+	is_nullable = 'YES' AS is_nullable,
+	extra AS extra
+FROM information_schema.columns
+WHERE table_schema = ? AND table_name = ?
+ORDER BY ordinal_position
+`
+
+	// sqlInspectUniqueIndexes retrieves the columns of the PRIMARY KEY and all UNIQUE indexes on the table.
+	// Pass database name and table name as arguments.
+	sqlInspectUniqueIndexes = `
+SELECT
+	index_name AS index_name,
+	COALESCE(column_name, '') AS column_name
+FROM information_schema.statistics
+WHERE table_schema = ? AND table_name = ?
+	AND non_unique = 0
+ORDER BY index_name, seq_in_index
+`
+
+	// sqlInspectIndexes retrieves the columns of non-unique indexes on the table, except the ones
+	// which MySQL creates for FOREIGN KEY constraints and which share the name with the constraint.
+	// Pass database name and table name as arguments.
+	sqlInspectIndexes = `
+SELECT
+	s.index_name AS index_name,
+	s.index_type AS index_type,
+	COALESCE(s.column_name, '') AS column_name
+FROM information_schema.statistics AS s
+WHERE s.table_schema = ? AND s.table_name = ?
+	AND s.non_unique = 1
+	AND NOT EXISTS (
+		SELECT 1 FROM information_schema.table_constraints AS tc
+		WHERE tc.table_schema = s.table_schema
+			AND tc.table_name = s.table_name
+			AND tc.constraint_name = s.index_name
+			AND tc.constraint_type = 'FOREIGN KEY'
+	)
+ORDER BY s.index_name, s.seq_in_index
+`
+
+	// sqlInspectForeignKeys retrieves FOREIGN KEY constraints between the tables in the selected database.
+	// Pass database name and the conditions excluding source and target tables from this inspection as arguments.
+	sqlInspectForeignKeys = `
+SELECT
+	constraint_name AS constraint_name,
+	table_name AS table_name,
+	column_name AS column_name,
+	referenced_table_name AS target_table,
+	referenced_column_name AS target_column
+FROM information_schema.key_column_usage
+WHERE table_schema = ?
+	AND referenced_table_schema = table_schema
+	AND referenced_table_name IS NOT NULL
+	AND ?
+	AND ?
+ORDER BY table_name, constraint_name, ordinal_position
+`
+)
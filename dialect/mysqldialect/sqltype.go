@@ -0,0 +1,109 @@
+package mysqldialect
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+const (
+	// Numeric Types
+	mysqlTypeBool     = "BOOL"       // alias for BOOLEAN
+	mysqlTypeTinyInt1 = "TINYINT(1)" // BOOLEAN is stored as TINYINT(1)
+	mysqlTypeInt      = "INT"        // alias for INTEGER
+	mysqlTypeDouble   = "DOUBLE"     // alias for DOUBLE PRECISION (and REAL, unless REAL_AS_FLOAT is set)
+	mysqlTypeDecimal  = "DECIMAL"    // fixed-point number
+	mysqlTypeDec      = "DEC"        // alias for DECIMAL
+	mysqlTypeNumeric  = "NUMERIC"    // alias for DECIMAL
+	mysqlTypeFixed    = "FIXED"      // alias for DECIMAL
+
+	// Character Types
+	mysqlTypeChar             = "CHAR"              // fixed length string
+	mysqlTypeCharacter        = "CHARACTER"         // alias for CHAR
+	mysqlTypeCharacterVarying = "CHARACTER VARYING" // alias for VARCHAR
+	mysqlTypeLongText         = "LONGTEXT"          // MariaDB stores JSON as LONGTEXT
+)
+
+var (
+	boolean  = newAliases(sqltype.Boolean, mysqlTypeBool, mysqlTypeTinyInt1)
+	integer  = newAliases(sqltype.Integer, mysqlTypeInt)
+	double   = newAliases(sqltype.DoublePrecision, mysqlTypeDouble, sqltype.Real)
+	decimal  = newAliases(mysqlTypeDecimal, mysqlTypeDec, mysqlTypeNumeric, mysqlTypeFixed)
+	char     = newAliases(mysqlTypeChar, mysqlTypeCharacter)
+	varchar  = newAliases(sqltype.VarChar, mysqlTypeCharacterVarying)
+	jsonText = newAliases(sqltype.JSON, mysqlTypeLongText)
+)
+
+// reDisplayWidth matches the display width of integer types, e.g. INT(11),
+// which MySQL 5.7 and MariaDB include in the column type.
+var reDisplayWidth = regexp.MustCompile(`^(TINYINT|SMALLINT|MEDIUMINT|INT|INTEGER|BIGINT)\(\d+\)`)
+
+func (d *Dialect) CompareType(col1, col2 sqlschema.Column) bool {
+	typ1, typ2 := normalizeType(col1.GetSQLType()), normalizeType(col2.GetSQLType())
+
+	if typ1 == typ2 {
+		return checkVarcharLen(col1, col2, d.DefaultVarcharLen())
+	}
+
+	switch {
+	case char.IsAlias(typ1) && char.IsAlias(typ2):
+		return checkVarcharLen(col1, col2, d.DefaultVarcharLen())
+	case varchar.IsAlias(typ1) && varchar.IsAlias(typ2):
+		return checkVarcharLen(col1, col2, d.DefaultVarcharLen())
+	case boolean.IsAlias(typ1) && boolean.IsAlias(typ2),
+		integer.IsAlias(typ1) && integer.IsAlias(typ2),
+		double.IsAlias(typ1) && double.IsAlias(typ2),
+		decimal.IsAlias(typ1) && decimal.IsAlias(typ2),
+		jsonText.IsAlias(typ1) && jsonText.IsAlias(typ2):
+		return true
+	}
+	return false
+}
+
+// normalizeType converts the type name to upper case and drops the display width
+// of integer types, which does not affect the range of stored values.
+// TINYINT(1) is kept as-is, because it is how MySQL stores BOOLEAN columns.
+func normalizeType(typ string) string {
+	typ = strings.ToUpper(strings.TrimSpace(typ))
+	if strings.HasPrefix(typ, mysqlTypeTinyInt1) {
+		return typ
+	}
+	return reDisplayWidth.ReplaceAllString(typ, "$1")
+}
+
+// checkVarcharLen returns true if columns have the same VarcharLen, or,
+// if one specifies no VarcharLen and the other one has the default length for mysqldialect.
+// We assume that the types are otherwise equivalent and that any non-character column
+// would have VarcharLen == 0;
+func checkVarcharLen(col1, col2 sqlschema.Column, defaultLen int) bool {
+	vl1, vl2 := col1.GetVarcharLen(), col2.GetVarcharLen()
+
+	if vl1 == vl2 {
+		return true
+	}
+
+	if (vl1 == 0 && vl2 == defaultLen) || (vl1 == defaultLen && vl2 == 0) {
+		return true
+	}
+	return false
+}
+
+// typeAlias defines aliases for common data types. It is a lightweight string set implementation.
+type typeAlias map[string]struct{}
+
+// IsAlias checks if typ1 and typ2 are aliases of the same data type.
+func (t typeAlias) IsAlias(typ string) bool {
+	_, ok := t[typ]
+	return ok
+}
+
+// newAliases creates a set of aliases.
+func newAliases(aliases ...string) typeAlias {
+	types := make(typeAlias)
+	for _, a := range aliases {
+		types[a] = struct{}{}
+	}
+	return types
+}
@@ -0,0 +1,92 @@
+package mysqldialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+func TestInspectorDialect_CompareType(t *testing.T) {
+	d := New()
+
+	t.Run("common types", func(t *testing.T) {
+		for _, tt := range []struct {
+			typ1, typ2 string
+			want       bool
+		}{
+			{"text", "text", true},     // identical types
+			{"bigint", "BIGINT", true}, // case-insensitive
+
+			// MySQL 5.7 and MariaDB report display width of integer types
+			{"int(11)", "int", true},
+			{"int(11)", sqltype.Integer, true},
+			{"bigint(20)", sqltype.BigInt, true},
+			{"bigint(20) unsigned", "bigint unsigned", true},
+			{"smallint(6)", sqltype.SmallInt, true},
+			{"int(11)", sqltype.BigInt, false},
+
+			// BOOLEAN is stored as TINYINT(1)
+			{"tinyint(1)", sqltype.Boolean, true},
+			{mysqlTypeBool, sqltype.Boolean, true},
+			{"tinyint(4)", sqltype.Boolean, false},
+			{"tinyint", sqltype.Boolean, false},
+
+			{sqltype.VarChar, mysqlTypeCharacterVarying, true},
+			{sqltype.VarChar, mysqlTypeChar, false},
+			{mysqlTypeCharacter, mysqlTypeChar, true},
+
+			{"double", sqltype.DoublePrecision, true},
+			{"double", sqltype.Real, true},
+			{"decimal", "numeric", true},
+			{"longtext", sqltype.JSON, true},
+			{datetimeType, "datetime", true},
+			{datetimeType, sqltype.Timestamp, false},
+		} {
+			eq := " ~ "
+			if !tt.want {
+				eq = " !~ "
+			}
+			t.Run(tt.typ1+eq+tt.typ2, func(t *testing.T) {
+				got := d.CompareType(
+					&sqlschema.BaseColumn{SQLType: tt.typ1},
+					&sqlschema.BaseColumn{SQLType: tt.typ2},
+				)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("custom varchar length", func(t *testing.T) {
+		for _, tt := range []struct {
+			name       string
+			col1, col2 sqlschema.BaseColumn
+			want       bool
+		}{
+			{
+				name: "varchars of different length are not equivalent",
+				col1: sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 10},
+				col2: sqlschema.BaseColumn{SQLType: "varchar"},
+				want: false,
+			},
+			{
+				name: "varchar with no explicit length is equivalent to varchar of default length",
+				col1: sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: d.DefaultVarcharLen()},
+				col2: sqlschema.BaseColumn{SQLType: "varchar"},
+				want: true,
+			},
+			{
+				name: "characters with equal custom length",
+				col1: sqlschema.BaseColumn{SQLType: "character varying", VarcharLen: 200},
+				col2: sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 200},
+				want: true,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				got := d.CompareType(&tt.col1, &tt.col2)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+}
@@ -41,7 +41,8 @@ func New(opts ...DialectOption) *Dialect {
 		feature.SelectExists |
 		feature.AutoIncrement |
 		feature.CompositeIn |
-		feature.DeleteReturning
+		feature.DeleteReturning |
+		feature.WindowRangeOffset
 
 	for _, opt := range opts {
 		opt(d)
@@ -36,6 +36,11 @@ func (m *migrator) AppendSQL(b []byte, operation any) (_ []byte, err error) {
 
 	switch change := operation.(type) {
 	case *migrate.CreateTableOp:
+		if change.Model == nil {
+			b = append(b, "CREATE TABLE "...)
+			b = m.appendFQN(gen, b, change.TableName)
+			return m.AppendTableDefinition(b, change.Table)
+		}
 		return m.AppendCreateTable(b, change.Model)
 	case *migrate.DropTableOp:
 		return m.AppendDropTable(b, m.schemaName, change.TableName)
@@ -68,6 +73,41 @@ func (m *migrator) AppendSQL(b []byte, operation any) (_ []byte, err error) {
 		b, err = m.addForeignKey(gen, appendAlterTable(b, change.TableName()), change)
 	case *migrate.DropForeignKeyOp:
 		b, err = m.dropConstraint(gen, appendAlterTable(b, change.TableName()), change.ConstraintName)
+	case *migrate.CreateIndexOp:
+		if change.Concurrently {
+			return m.NewCreateIndexQuery(m.schemaName, change.TableName, change.Index).
+				Concurrently().AppendQuery(gen, b)
+		}
+		return m.AppendCreateIndex(b, m.schemaName, change.TableName, change.Index)
+	case *migrate.DropIndexOp:
+		if change.Concurrently {
+			return m.db.NewDropIndex().Concurrently().
+				Index("?.?", bun.Ident(m.schemaName), bun.Ident(sqlschema.IndexName(change.TableName, change.Index))).
+				AppendQuery(gen, b)
+		}
+		return m.AppendDropIndex(b, m.schemaName, sqlschema.IndexName(change.TableName, change.Index))
+	case *migrate.AddCheckOp:
+		b, err = m.addCheck(gen, appendAlterTable(b, change.TableName), change)
+	case *migrate.DropCheckOp:
+		b, err = m.dropConstraint(gen, appendAlterTable(b, change.TableName), change.Check.Name)
+	case *migrate.CreateEnumOp:
+		b, err = m.createEnum(gen, b, change.Enum)
+	case *migrate.DropEnumOp:
+		b = append(b, "DROP TYPE "...)
+		b = m.appendFQN(gen, b, change.Enum.Name)
+	case *migrate.AlterEnumAddValueOp:
+		b, err = m.addEnumValue(gen, b, change)
+	case *migrate.SetCommentOp:
+		b, err = m.setComment(gen, b, change)
+	case *migrate.ValidateConstraintOp:
+		b = append(appendAlterTable(b, change.TableName), "VALIDATE CONSTRAINT "...)
+		b = gen.AppendName(b, change.ConstraintName)
+	case *migrate.SetLockTimeoutOp:
+		if change.Timeout == 0 {
+			b = append(b, "RESET lock_timeout"...)
+		} else {
+			b = gen.AppendQuery(b, "SET lock_timeout = ?", fmt.Sprintf("%dms", change.Timeout.Milliseconds()))
+		}
 	default:
 		return nil, fmt.Errorf("append sql: unknown operation %T", change)
 	}
@@ -150,6 +190,13 @@ func (m *migrator) addUnique(gen schema.QueryGen, b []byte, change *migrate.AddU
 		// Default naming scheme for unique constraints in Postgres is <table>_<column>_key
 		b = gen.AppendName(b, fmt.Sprintf("%s_%s_key", change.TableName, change.Unique.Columns))
 	}
+	if change.UsingIndex {
+		// The index has the same name as the constraint, see migrate.AddUniqueConstraintOp.
+		b = append(b, " UNIQUE USING INDEX "...)
+		b = gen.AppendName(b, change.Unique.Name)
+		return b, nil
+	}
+
 	b = append(b, " UNIQUE ("...)
 	b, _ = change.Unique.Columns.AppendQuery(gen, b)
 	b = append(b, ")"...)
@@ -190,6 +237,74 @@ func (m *migrator) addForeignKey(gen schema.QueryGen, b []byte, add *migrate.Add
 	}
 	b = append(b, ")"...)
 
+	if add.NotValid {
+		b = append(b, " NOT VALID"...)
+	}
+
+	return b, nil
+}
+
+func (m *migrator) addCheck(gen schema.QueryGen, b []byte, add *migrate.AddCheckOp) (_ []byte, err error) {
+	check := add.Check
+	b = append(b, "ADD "...)
+	if check.Name != "" {
+		b = append(b, "CONSTRAINT "...)
+		b = gen.AppendName(b, check.Name)
+		b = append(b, " "...)
+	}
+	b = append(b, "CHECK ("...)
+	b = append(b, check.Expr...)
+	b = append(b, ")"...)
+
+	if add.NotValid {
+		b = append(b, " NOT VALID"...)
+	}
+
+	return b, nil
+}
+
+func (m *migrator) createEnum(gen schema.QueryGen, b []byte, enum sqlschema.Enum) (_ []byte, err error) {
+	b = append(b, "CREATE TYPE "...)
+	b = m.appendFQN(gen, b, enum.Name)
+	b = append(b, " AS ENUM ("...)
+	for i, value := range enum.Values {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = gen.AppendQuery(b, "?", value)
+	}
+	b = append(b, ")"...)
+
+	return b, nil
+}
+
+func (m *migrator) addEnumValue(gen schema.QueryGen, b []byte, add *migrate.AlterEnumAddValueOp) (_ []byte, err error) {
+	b = append(b, "ALTER TYPE "...)
+	b = m.appendFQN(gen, b, add.EnumName)
+	b = gen.AppendQuery(b, " ADD VALUE ?", add.Value)
+
+	switch {
+	case add.Before != "":
+		b = gen.AppendQuery(b, " BEFORE ?", add.Before)
+	case add.After != "":
+		b = gen.AppendQuery(b, " AFTER ?", add.After)
+	}
+
+	return b, nil
+}
+
+func (m *migrator) setComment(gen schema.QueryGen, b []byte, set *migrate.SetCommentOp) (_ []byte, err error) {
+	b = append(b, "COMMENT ON COLUMN "...)
+	b = m.appendFQN(gen, b, set.TableName)
+	b = append(b, "."...)
+	b = gen.AppendName(b, set.ColumnName)
+
+	if set.Comment == "" {
+		b = append(b, " IS NULL"...)
+	} else {
+		b = gen.AppendQuery(b, " IS ?", set.Comment)
+	}
+
 	return b, nil
 }
 
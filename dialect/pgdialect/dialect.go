@@ -28,10 +28,12 @@ type Dialect struct {
 
 	tables    *schema.Tables
 	features  feature.Feature
+	maxValues int
 	uintAsInt bool
 }
 
 var _ schema.Dialect = (*Dialect)(nil)
+var _ schema.MaxValuesDialect = (*Dialect)(nil)
 var _ sqlschema.InspectorDialect = (*Dialect)(nil)
 var _ sqlschema.MigratorDialect = (*Dialect)(nil)
 
@@ -58,7 +60,19 @@ func New(opts ...DialectOption) *Dialect {
 		feature.FKDefaultOnAction |
 		feature.DeleteReturning |
 		feature.MergeReturning |
-		feature.AlterColumnExists
+		feature.AlterColumnExists |
+		feature.CheckConstraint |
+		feature.EnumType |
+		feature.CommentOnColumn |
+		feature.OnlineSchemaChange |
+		feature.RowValueCompare |
+		feature.NamedWindow |
+		feature.WindowGroups |
+		feature.WindowRangeOffset |
+		feature.WindowExclude |
+		feature.Merge
+
+	d.maxValues = 65535
 
 	for _, opt := range opts {
 		opt(d)
@@ -75,6 +89,15 @@ func WithoutFeature(other feature.Feature) DialectOption {
 	}
 }
 
+// WithMaxValues sets the maximum number of values in a single statement,
+// see schema.MaxValuesDialect. The default is 65535, the limit of bind parameters
+// in the extended protocol.
+func WithMaxValues(n int) DialectOption {
+	return func(d *Dialect) {
+		d.maxValues = n
+	}
+}
+
 func WithAppendUintAsInt(on bool) DialectOption {
 	return func(d *Dialect) {
 		d.uintAsInt = on
@@ -91,6 +114,10 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+func (d *Dialect) MaxValues() int {
+	return d.maxValues
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
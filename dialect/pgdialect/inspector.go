@@ -47,6 +47,17 @@ func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
 		return dbSchema, err
 	}
 
+	var enums []*Enum
+	if err := in.db.NewRaw(sqlInspectEnums, in.SchemaName, bun.In(exclude)).Scan(ctx, &enums); err != nil {
+		return dbSchema, err
+	}
+	for _, enum := range enums {
+		dbSchema.Enums = append(dbSchema.Enums, sqlschema.Enum{
+			Name:   enum.Name,
+			Values: enum.Values,
+		})
+	}
+
 	var fks []*ForeignKey
 	if err := in.db.NewRaw(sqlInspectForeignKeys, in.SchemaName, bun.In(exclude), bun.In(exclude)).Scan(ctx, &fks); err != nil {
 		return dbSchema, err
@@ -78,6 +89,7 @@ func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
 				IsNullable:      c.IsNullable,
 				IsAutoIncrement: c.IsSerial,
 				IsIdentity:      c.IsIdentity,
+				Comment:         c.Comment,
 			})
 
 			for _, group := range c.UniqueGroups {
@@ -93,6 +105,21 @@ func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
 			})
 		}
 
+		var indexes []*Index
+		if err := in.db.NewRaw(sqlInspectIndexes, table.Schema, table.Name).Scan(ctx, &indexes); err != nil {
+			return dbSchema, err
+		}
+
+		var idxDefs []sqlschema.Index
+		for _, idx := range indexes {
+			idxDefs = append(idxDefs, idx.toIndex())
+		}
+
+		var checks []sqlschema.Check
+		if err := in.db.NewRaw(sqlInspectChecks, table.Schema, table.Name).Scan(ctx, &checks); err != nil {
+			return dbSchema, err
+		}
+
 		var pk *sqlschema.PrimaryKey
 		if len(table.PrimaryKey.Columns) > 0 {
 			pk = &sqlschema.PrimaryKey{
@@ -107,6 +134,8 @@ func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
 			Columns:           colDefs,
 			PrimaryKey:        pk,
 			UniqueConstraints: unique,
+			Indexes:           idxDefs,
+			Checks:            checks,
 		})
 	}
 
@@ -146,6 +175,7 @@ type InformationSchemaColumn struct {
 	IsSerial         bool     `bun:"is_serial"`
 	IsNullable       bool     `bun:"is_nullable"`
 	UniqueGroups     []string `bun:"unique_groups,array"`
+	Comment          string   `bun:"comment"`
 }
 
 type ForeignKey struct {
@@ -158,6 +188,39 @@ type ForeignKey struct {
 	TargetColumns  []string `bun:"target_columns,array"`
 }
 
+type Index struct {
+	Name           string   `bun:"index_name"`
+	IsUnique       bool     `bun:"is_unique"`
+	Method         string   `bun:"method"`
+	Columns        []string `bun:"columns,array"`
+	HasExpressions bool     `bun:"has_expressions"`
+	Include        []string `bun:"include,array"`
+	Where          string   `bun:"where"`
+}
+
+func (idx *Index) toIndex() sqlschema.Index {
+	index := sqlschema.Index{
+		Name:    idx.Name,
+		Unique:  idx.IsUnique,
+		Columns: idx.Columns,
+		Include: idx.Include,
+		Where:   idx.Where,
+	}
+	if idx.Method != "btree" {
+		index.Using = idx.Method
+	}
+	if idx.HasExpressions {
+		index.Columns = nil
+		index.Expr = strings.Join(idx.Columns, ", ")
+	}
+	return index
+}
+
+type Enum struct {
+	Name   string   `bun:"enum_name"`
+	Values []string `bun:"values,array"`
+}
+
 type PrimaryKey struct {
 	ConstraintName string   `bun:"name"`
 	Columns        []string `bun:"columns,array"`
@@ -212,13 +275,15 @@ SELECT
 	"c".column_default = format('nextval(''%s_%s_seq''::regclass)', "c".table_name, "c".column_name) AS is_serial,
 	COALESCE("c".identity_type, '') AS identity_type,
 	"c".is_nullable = 'YES' AS is_nullable,
-	"c"."unique_groups" AS unique_groups
+	"c"."unique_groups" AS unique_groups,
+	COALESCE(col_description(format('%I.%I', "c".table_schema, "c".table_name)::regclass, "c".ordinal_position::integer), '') AS "comment"
 FROM (
 	SELECT
 		"table_schema",
 		"table_name",
 		"column_name",
-		"c".data_type,
+		"c".ordinal_position,
+		CASE WHEN "c".data_type = 'USER-DEFINED' THEN "c".udt_name ELSE "c".data_type END AS data_type,
 		"c".character_maximum_length,
 		"c".column_default,
 		"c".is_identity,
@@ -255,6 +320,79 @@ FROM (
 	) "c"
 WHERE "table_schema" = ? AND "table_name" = ?
 ORDER BY "table_schema", "table_name", "column_name"
+`
+
+	// sqlInspectIndexes retrieves the indexes defined on the specified table,
+	// except the ones which back PRIMARY KEY, UNIQUE and EXCLUDE constraints.
+	// Key columns which are expressions are reported in the same form as in pg_indexes.indexdef.
+	// Pass table_schema and table_name as arguments.
+	sqlInspectIndexes = `
+SELECT
+	"idx".relname AS index_name,
+	i.indisunique AS is_unique,
+	am.amname AS "method",
+	ARRAY(
+		SELECT COALESCE("a".attname, pg_get_indexdef(i.indexrelid, k, true))
+		FROM generate_series(1, i.indnkeyatts::integer) AS k
+			LEFT JOIN pg_attribute "a" ON "a".attrelid = i.indrelid AND "a".attnum = i.indkey[k - 1] AND "a".attnum > 0
+		ORDER BY k
+	) AS "columns",
+	i.indexprs IS NOT NULL AS has_expressions,
+	ARRAY(
+		SELECT "a".attname
+		FROM generate_series(i.indnkeyatts::integer + 1, i.indnatts::integer) AS k
+			JOIN pg_attribute "a" ON "a".attrelid = i.indrelid AND "a".attnum = i.indkey[k - 1]
+		ORDER BY k
+	) AS "include",
+	COALESCE(pg_get_expr(i.indpred, i.indrelid, true), '') AS "where"
+FROM pg_index i
+	JOIN pg_class "idx" ON "idx".oid = i.indexrelid
+	JOIN pg_class "t" ON "t".oid = i.indrelid
+	JOIN pg_namespace s ON s.oid = "t".relnamespace
+	JOIN pg_am am ON am.oid = "idx".relam
+WHERE s.nspname = ? AND "t".relname = ?
+	AND NOT i.indisprimary
+	AND NOT EXISTS (
+		SELECT 1 FROM pg_constraint con
+		WHERE con.conrelid = i.indrelid AND con.conindid = i.indexrelid AND con.contype IN ('p', 'u', 'x')
+	)
+ORDER BY "idx".relname
+`
+
+	// sqlInspectChecks retrieves CHECK constraints defined on the specified table.
+	// Pass table_schema and table_name as arguments.
+	sqlInspectChecks = `
+SELECT con.conname AS "name", pg_get_expr(con.conbin, con.conrelid, true) AS "expr"
+FROM pg_constraint con
+	JOIN pg_class "t" ON "t".oid = con.conrelid
+	JOIN pg_namespace s ON s.oid = "t".relnamespace
+WHERE s.nspname = ? AND "t".relname = ?
+	AND con.contype = 'c'
+ORDER BY con.conname
+`
+
+	// sqlInspectEnums retrieves enum types in the selected schema, except the ones
+	// which are used by the tables excluded from the inspection.
+	// Pass bun.In([]string{...}) to exclude tables from this inspection or bun.In([]string{''}) to include all results.
+	sqlInspectEnums = `
+SELECT
+	"t".typname AS enum_name,
+	ARRAY(
+		SELECT e.enumlabel
+		FROM pg_enum e
+		WHERE e.enumtypid = "t".oid
+		ORDER BY e.enumsortorder
+	) AS "values"
+FROM pg_type "t"
+	JOIN pg_namespace s ON s.oid = "t".typnamespace
+WHERE "t".typtype = 'e'
+	AND s.nspname = ?
+	AND NOT EXISTS (
+		SELECT 1 FROM information_schema.columns "c"
+		WHERE "c".udt_schema = s.nspname AND "c".udt_name = "t".typname
+			AND "c".table_name LIKE ANY (ARRAY[?])
+	)
+ORDER BY "t".typname
 `
 
 	// sqlInspectForeignKeys get FK definitions for user-defined tables.
@@ -0,0 +1,1004 @@
+package sqlitedialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
+)
+
+func (d *Dialect) NewMigrator(db *bun.DB, schemaName string) sqlschema.Migrator {
+	return &migrator{
+		BaseMigrator: sqlschema.NewBaseMigrator(db),
+
+		db:         db,
+		schemaName: schemaName,
+		inspector:  newInspector(db, sqlschema.WithSchemaName(schemaName)),
+		tables:     make(map[string]*tableDefinition),
+		noTx:       make(map[any]bool),
+	}
+}
+
+// migrator implements sqlschema.Migrator for SQLite.
+//
+// SQLite only supports a handful of ALTER TABLE commands (RENAME TO, RENAME COLUMN,
+// ADD COLUMN, DROP COLUMN), which is why most operations are applied by re-creating
+// the table following the "12-step" procedure described in the [documentation].
+// Doing so requires the complete definition of the table, so migrator inspects
+// each table when it is first altered and keeps track of its state afterwards.
+// Operations must therefore be appended in the same order they will be applied.
+//
+// When foreign key enforcement is enabled, the generated SQL disables it
+// for the duration of the table rebuild and runs PRAGMA foreign_key_check before
+// enabling it again. PRAGMA foreign_keys is a no-op inside a transaction, so such
+// operations are reported as non-transactional and are written to migrations
+// which are not run in a transaction.
+//
+// [documentation]: https://www.sqlite.org/lang_altertable.html#otheralter
+type migrator struct {
+	*sqlschema.BaseMigrator
+
+	db         *bun.DB
+	schemaName string
+	inspector  *Inspector
+
+	// tables holds the current definitions of the tables that have been modified.
+	tables map[string]*tableDefinition
+
+	// noTx holds the operations which must not be run in a transaction.
+	noTx map[any]bool
+	// rebuilt is set when the operation being appended disabled foreign keys to re-create a table.
+	rebuilt bool
+}
+
+var _ sqlschema.Migrator = (*migrator)(nil)
+var _ sqlschema.DatabaseTracker = (*migrator)(nil)
+var _ sqlschema.TxMigrator = (*migrator)(nil)
+
+// Transactional reports whether the operation can be run in a transaction.
+// Table rebuilds which disable foreign keys cannot, see migrator.
+func (m *migrator) Transactional(operation any) bool {
+	return !m.noTx[operation]
+}
+
+func (m *migrator) AppendSQL(b []byte, operation any) (_ []byte, err error) {
+	gen := m.db.QueryGen()
+	ctx := context.TODO()
+
+	m.rebuilt = false
+	defer func() {
+		if err == nil && m.rebuilt {
+			m.noTx[operation] = true
+		}
+	}()
+
+	// Append ALTER TABLE statement to the enclosed query bytes []byte.
+	appendAlterTable := func(query []byte, tableName string) []byte {
+		query = append(query, "ALTER TABLE "...)
+		query = m.appendFQN(gen, query, tableName)
+		return append(query, " "...)
+	}
+
+	switch change := operation.(type) {
+	case *migrate.CreateTableOp:
+		if change.Model == nil {
+			return m.appendCreateTable(gen, b, change.TableName, m.trackTable(change.TableName, change.Table))
+		}
+		m.trackModel(change.TableName, change.Model)
+		return m.AppendCreateTable(b, change.Model)
+	case *migrate.DropTableOp:
+		delete(m.tables, change.TableName)
+		return m.AppendDropTable(b, m.schemaName, change.TableName)
+	case *migrate.RenameTableOp:
+		if err = m.trackRenameTable(ctx, change); err != nil {
+			break
+		}
+		b = append(appendAlterTable(b, change.TableName), "RENAME TO "...)
+		b = gen.AppendName(b, change.NewName)
+	case *migrate.RenameColumnOp:
+		if err = m.alter(ctx, change.TableName, func(t *tableDefinition) error {
+			return m.trackRenameColumn(t, change)
+		}); err != nil {
+			break
+		}
+		b = append(appendAlterTable(b, change.TableName), "RENAME COLUMN "...)
+		b = gen.AppendName(b, change.OldName)
+		b = append(b, " TO "...)
+		b = gen.AppendName(b, change.NewName)
+	case *migrate.AddColumnOp:
+		b, err = m.addColumn(ctx, gen, b, change)
+	case *migrate.DropColumnOp:
+		b, err = m.dropColumn(ctx, gen, b, change)
+	case *migrate.ChangeColumnTypeOp:
+		if change.To.GetSQLType() == "" {
+			err = fmt.Errorf("cannot change column %s.%s: data type is not specified", change.TableName, change.Column)
+			break
+		}
+		b, err = m.rebuild(ctx, gen, b, change.TableName, func(t *tableDefinition) error {
+			return t.ChangeColumn(change.Column, change.From, change.To)
+		})
+	case *migrate.AddForeignKeyOp:
+		b, err = m.rebuild(ctx, gen, b, change.TableName(), func(t *tableDefinition) error {
+			t.ForeignKeys = append(t.ForeignKeys, &foreignKeyDefinition{
+				TargetTable: change.ForeignKey.To.TableName,
+				FromColumns: change.ForeignKey.From.Column.Split(),
+				ToColumns:   change.ForeignKey.To.Column.Split(),
+			})
+			return nil
+		})
+	case *migrate.DropForeignKeyOp:
+		b, err = m.rebuild(ctx, gen, b, change.TableName(), func(t *tableDefinition) error {
+			t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *foreignKeyDefinition) bool {
+				return fk.ForeignKey(t.Name) == change.ForeignKey
+			})
+			return nil
+		})
+	case *migrate.AddUniqueConstraintOp:
+		b, err = m.addUnique(ctx, gen, b, change)
+	case *migrate.DropUniqueConstraintOp:
+		b, err = m.dropUnique(ctx, gen, b, change)
+	case *migrate.AddPrimaryKeyOp:
+		b, err = m.rebuild(ctx, gen, b, change.TableName, func(t *tableDefinition) error {
+			t.PrimaryKey = &change.PrimaryKey
+			return nil
+		})
+	case *migrate.ChangePrimaryKeyOp:
+		b, err = m.rebuild(ctx, gen, b, change.TableName, func(t *tableDefinition) error {
+			t.PrimaryKey = &change.New
+			return nil
+		})
+	case *migrate.DropPrimaryKeyOp:
+		b, err = m.rebuild(ctx, gen, b, change.TableName, func(t *tableDefinition) error {
+			t.PrimaryKey = nil
+			return nil
+		})
+	case *migrate.CreateIndexOp:
+		b, err = m.createIndex(ctx, gen, b, change)
+	case *migrate.DropIndexOp:
+		b, err = m.dropIndex(ctx, b, change)
+	default:
+		return nil, fmt.Errorf("append sql: unknown operation %T", change)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("append sql: %w", err)
+	}
+	return b, nil
+}
+
+func (m *migrator) appendFQN(gen schema.QueryGen, b []byte, tableName string) []byte {
+	return gen.AppendQuery(b, "?.?", bun.Ident(m.schemaName), bun.Ident(tableName))
+}
+
+// table returns the current definition of the table, inspecting the database if necessary.
+func (m *migrator) table(ctx context.Context, tableName string) (*tableDefinition, error) {
+	if t, ok := m.tables[tableName]; ok {
+		return t, nil
+	}
+
+	var master SQLiteMaster
+	if err := m.db.NewSelect().
+		TableExpr("?.sqlite_master", bun.Ident(m.schemaName)).
+		Column("name", "sql", "type").
+		Where("type = 'table'").
+		Where("name = ?", tableName).
+		Scan(ctx, &master); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("table %q does not exist", tableName)
+		}
+		return nil, err
+	}
+
+	t, err := m.inspector.inspectTable(ctx, &master)
+	if err != nil {
+		return nil, err
+	}
+	m.tables[tableName] = t
+	return t, nil
+}
+
+// alter applies the change to the tracked table definition.
+func (m *migrator) alter(ctx context.Context, tableName string, change func(*tableDefinition) error) error {
+	t, err := m.table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	return change(t)
+}
+
+// rebuild applies the change to a copy of the table definition and appends
+// the statements that re-create the table to match the new definition.
+func (m *migrator) rebuild(
+	ctx context.Context, gen schema.QueryGen, b []byte, tableName string, change func(*tableDefinition) error,
+) (_ []byte, err error) {
+	old, err := m.table(ctx, tableName)
+	if err != nil {
+		return b, err
+	}
+
+	t := old.Clone()
+	if err := change(t); err != nil {
+		return b, err
+	}
+
+	var fkEnabled bool
+	if err := m.db.NewRaw("PRAGMA foreign_keys").Scan(ctx, &fkEnabled); err != nil {
+		return b, err
+	}
+
+	tmpName := "_bun_new_" + t.Name
+
+	// 1. Disable foreign key constraints, so that dropping the old table does not cascade.
+	if fkEnabled {
+		b = append(b, "PRAGMA foreign_keys = OFF;\n"...)
+	}
+
+	// 2. Create a new table with the desired definition.
+	if b, err = m.appendCreateTable(gen, b, tmpName, t); err != nil {
+		return b, err
+	}
+	b = append(b, ";\n"...)
+
+	// 3. Copy data from the columns which exist in both tables.
+	var columns []string
+	for _, col := range t.Columns {
+		if old.Column(col.GetName()) != nil {
+			columns = append(columns, col.GetName())
+		}
+	}
+	if len(columns) > 0 {
+		b = append(b, "INSERT INTO "...)
+		b = m.appendFQN(gen, b, tmpName)
+		b = append(b, " ("...)
+		b = appendNames(gen, b, columns)
+		b = append(b, ") SELECT "...)
+		b = appendNames(gen, b, columns)
+		b = append(b, " FROM "...)
+		b = m.appendFQN(gen, b, t.Name)
+		b = append(b, ";\n"...)
+	}
+
+	// 4. Drop the old table and rename the new one in its place.
+	b, err = m.AppendDropTable(b, m.schemaName, t.Name)
+	if err != nil {
+		return b, err
+	}
+	b = append(b, ";\n"...)
+
+	b = append(b, "ALTER TABLE "...)
+	b = m.appendFQN(gen, b, tmpName)
+	b = append(b, " RENAME TO "...)
+	b = gen.AppendName(b, t.Name)
+
+	// 5. Re-create the indexes and triggers which were dropped together with the old table.
+	for _, u := range t.Unique {
+		if u.IsIndex {
+			b = append(b, ";\n"...)
+			b = m.appendCreateUniqueIndex(gen, b, t.Name, u.Unique)
+		}
+	}
+	for _, idx := range t.Indexes {
+		b = append(b, ";\n"...)
+		if idx.SQL != "" {
+			b = append(b, idx.SQL...)
+			continue
+		}
+		if b, err = m.appendCreateIndex(gen, b, t.Name, idx.Index); err != nil {
+			return b, err
+		}
+	}
+	for _, query := range t.Triggers {
+		b = append(b, ";\n"...)
+		b = append(b, query...)
+	}
+
+	// 6. Verify that the new table does not break foreign key constraints and enable them back.
+	if fkEnabled {
+		b = append(b, ";\nPRAGMA foreign_key_check;\nPRAGMA foreign_keys = ON"...)
+		m.rebuilt = true
+	}
+
+	m.tables[t.Name] = t
+	return b, nil
+}
+
+func (m *migrator) appendCreateTable(gen schema.QueryGen, b []byte, tableName string, t *tableDefinition) (_ []byte, err error) {
+	b = append(b, "CREATE TABLE "...)
+	b = m.appendFQN(gen, b, tableName)
+	b = append(b, " ("...)
+
+	var inlinePK bool
+	for i, col := range t.Columns {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = gen.AppendName(b, col.GetName())
+		b = append(b, " "...)
+		if b, err = col.AppendQuery(gen, b); err != nil {
+			return b, err
+		}
+
+		if !col.GetIsNullable() {
+			b = append(b, " NOT NULL"...)
+		}
+
+		// AUTOINCREMENT is only valid for INTEGER PRIMARY KEY, see Dialect.AppendSequence.
+		if col.GetIsAutoIncrement() && t.PrimaryKey != nil &&
+			t.PrimaryKey.Columns.String() == col.GetName() &&
+			strings.EqualFold(col.GetSQLType(), "integer") {
+			b = append(b, " PRIMARY KEY AUTOINCREMENT"...)
+			inlinePK = true
+		}
+
+		if raw, ok := t.RawDefaults[col.GetName()]; ok {
+			// PRAGMA table_info reports expressions without the enclosing parentheses.
+			b = append(b, " DEFAULT "...)
+			if isConstant(raw) {
+				b = append(b, raw...)
+			} else {
+				b = append(b, '(')
+				b = append(b, raw...)
+				b = append(b, ')')
+			}
+		} else if def := col.GetDefaultValue(); def != "" {
+			b = append(b, " DEFAULT "...)
+			b = appendDefault(b, def)
+		}
+	}
+
+	if t.PrimaryKey != nil && !inlinePK {
+		b = append(b, ", PRIMARY KEY ("...)
+		b = appendNames(gen, b, t.PrimaryKey.Columns.Split())
+		b = append(b, ")"...)
+	}
+
+	for _, u := range t.Unique {
+		if u.IsIndex {
+			continue
+		}
+		b = append(b, ", "...)
+		if u.Name != "" && !strings.HasPrefix(u.Name, "sqlite_autoindex_") {
+			b = append(b, "CONSTRAINT "...)
+			b = gen.AppendName(b, u.Name)
+			b = append(b, " "...)
+		}
+		b = append(b, "UNIQUE ("...)
+		b = appendNames(gen, b, u.Columns.Split())
+		b = append(b, ")"...)
+	}
+
+	// SQLite does not allow schema-qualified table names in the REFERENCES clause.
+	for _, fk := range t.ForeignKeys {
+		b = append(b, ", FOREIGN KEY ("...)
+		b = appendNames(gen, b, fk.FromColumns)
+		b = append(b, ") REFERENCES "...)
+		b = gen.AppendName(b, fk.TargetTable)
+		b = append(b, " ("...)
+		b = appendNames(gen, b, fk.ToColumns)
+		b = append(b, ")"...)
+
+		if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+			b = append(b, " ON UPDATE "...)
+			b = append(b, fk.OnUpdate...)
+		}
+		if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+			b = append(b, " ON DELETE "...)
+			b = append(b, fk.OnDelete...)
+		}
+	}
+
+	b = append(b, ")"...)
+	return b, nil
+}
+
+func (m *migrator) addColumn(ctx context.Context, gen schema.QueryGen, b []byte, add *migrate.AddColumnOp) (_ []byte, err error) {
+	// ADD COLUMN cannot add NOT NULL columns without a default value
+	// and columns whose default value is not constant.
+	col := newColumn(add.ColumnName, add.Column)
+	def := col.DefaultValue
+	if col.IsAutoIncrement ||
+		(!col.IsNullable && def == "") ||
+		(def != "" && !isConstant(def)) {
+		return m.rebuild(ctx, gen, b, add.TableName, func(t *tableDefinition) error {
+			t.Columns = append(t.Columns, col)
+			return nil
+		})
+	}
+
+	if err = m.alter(ctx, add.TableName, func(t *tableDefinition) error {
+		t.Columns = append(t.Columns, col)
+		return nil
+	}); err != nil {
+		return b, err
+	}
+
+	b = append(b, "ALTER TABLE "...)
+	b = m.appendFQN(gen, b, add.TableName)
+	b = append(b, " ADD COLUMN "...)
+	b = gen.AppendName(b, add.ColumnName)
+	b = append(b, " "...)
+	if b, err = add.Column.AppendQuery(gen, b); err != nil {
+		return b, err
+	}
+	if !add.Column.GetIsNullable() {
+		b = append(b, " NOT NULL"...)
+	}
+	if def != "" {
+		b = append(b, " DEFAULT "...)
+		b = appendDefault(b, def)
+	}
+	return b, nil
+}
+
+func (m *migrator) dropColumn(ctx context.Context, gen schema.QueryGen, b []byte, drop *migrate.DropColumnOp) (_ []byte, err error) {
+	t, err := m.table(ctx, drop.TableName)
+	if err != nil {
+		return b, err
+	}
+
+	// DROP COLUMN fails if the column is part of a PRIMARY KEY, UNIQUE or FOREIGN KEY constraint.
+	if t.HasConstraintOn(drop.ColumnName) {
+		return m.rebuild(ctx, gen, b, drop.TableName, func(t *tableDefinition) error {
+			t.DropColumn(drop.ColumnName)
+			return nil
+		})
+	}
+	t.DropColumn(drop.ColumnName)
+
+	b = append(b, "ALTER TABLE "...)
+	b = m.appendFQN(gen, b, drop.TableName)
+	b = append(b, " DROP COLUMN "...)
+	b = gen.AppendName(b, drop.ColumnName)
+	return b, nil
+}
+
+func (m *migrator) addUnique(ctx context.Context, gen schema.QueryGen, b []byte, add *migrate.AddUniqueConstraintOp) (_ []byte, err error) {
+	unique := add.Unique
+	if unique.Name == "" {
+		// Follow the naming convention Postgres uses for unique constraints: <table>_<column>_key.
+		unique.Name = fmt.Sprintf("%s_%s_key", add.TableName, strings.Join(unique.Columns.Split(), "_"))
+	}
+
+	if err = m.alter(ctx, add.TableName, func(t *tableDefinition) error {
+		t.Unique = append(t.Unique, uniqueDefinition{Unique: unique, IsIndex: true})
+		return nil
+	}); err != nil {
+		return b, err
+	}
+	return m.appendCreateUniqueIndex(gen, b, add.TableName, unique), nil
+}
+
+func (m *migrator) dropUnique(ctx context.Context, gen schema.QueryGen, b []byte, drop *migrate.DropUniqueConstraintOp) (_ []byte, err error) {
+	t, err := m.table(ctx, drop.TableName)
+	if err != nil {
+		return b, err
+	}
+
+	i := slices.IndexFunc(t.Unique, func(u uniqueDefinition) bool {
+		return u.Equals(drop.Unique)
+	})
+	if i == -1 {
+		return b, fmt.Errorf("table %q has no unique constraint on (%s)", drop.TableName, drop.Unique.Columns)
+	}
+
+	// Constraints declared in CREATE TABLE are backed by internal indexes,
+	// which cannot be dropped without re-creating the table.
+	if !t.Unique[i].IsIndex {
+		return m.rebuild(ctx, gen, b, drop.TableName, func(t *tableDefinition) error {
+			t.Unique = slices.Delete(t.Unique, i, i+1)
+			return nil
+		})
+	}
+
+	name := t.Unique[i].Name
+	t.Unique = slices.Delete(t.Unique, i, i+1)
+
+	b = append(b, "DROP INDEX "...)
+	b = gen.AppendQuery(b, "?.?", bun.Ident(m.schemaName), bun.Ident(name))
+	return b, nil
+}
+
+func (m *migrator) appendCreateUniqueIndex(gen schema.QueryGen, b []byte, tableName string, unique sqlschema.Unique) []byte {
+	b, _ = m.appendCreateIndex(gen, b, tableName, sqlschema.Index{
+		Name:    unique.Name,
+		Unique:  true,
+		Columns: unique.Columns.Split(),
+	})
+	return b
+}
+
+func (m *migrator) createIndex(ctx context.Context, gen schema.QueryGen, b []byte, create *migrate.CreateIndexOp) (_ []byte, err error) {
+	index := create.Index
+	index.Name = sqlschema.IndexName(create.TableName, index)
+
+	if b, err = m.appendCreateIndex(gen, b, create.TableName, index); err != nil {
+		return b, err
+	}
+	if err = m.alter(ctx, create.TableName, func(t *tableDefinition) error {
+		t.Indexes = append(t.Indexes, indexDefinition{Index: index})
+		return nil
+	}); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+func (m *migrator) dropIndex(ctx context.Context, b []byte, drop *migrate.DropIndexOp) (_ []byte, err error) {
+	t, err := m.table(ctx, drop.TableName)
+	if err != nil {
+		return b, err
+	}
+
+	name := sqlschema.IndexName(drop.TableName, drop.Index)
+	i := slices.IndexFunc(t.Indexes, func(idx indexDefinition) bool {
+		return idx.Name == name
+	})
+	if i == -1 {
+		return b, fmt.Errorf("table %q has no index %q", drop.TableName, name)
+	}
+	t.Indexes = slices.Delete(t.Indexes, i, i+1)
+
+	return m.AppendDropIndex(b, m.schemaName, name)
+}
+
+// appendCreateIndex appends CREATE INDEX statement. SQLite expects the schema name
+// to qualify the name of the index rather than the table and does not support
+// index access methods and INCLUDE columns.
+func (m *migrator) appendCreateIndex(gen schema.QueryGen, b []byte, tableName string, index sqlschema.Index) (_ []byte, err error) {
+	if index.Using != "" || len(index.Include) > 0 {
+		return b, fmt.Errorf("index %q: SQLite does not support USING and INCLUDE clauses", index.Name)
+	}
+
+	b = append(b, "CREATE "...)
+	if index.Unique {
+		b = append(b, "UNIQUE "...)
+	}
+	b = append(b, "INDEX "...)
+	b = gen.AppendQuery(b, "?.?", bun.Ident(m.schemaName), bun.Ident(index.Name))
+	b = append(b, " ON "...)
+	b = gen.AppendName(b, tableName)
+	b = append(b, " ("...)
+	if index.Expr != "" {
+		b = append(b, index.Expr...)
+	} else {
+		b = appendNames(gen, b, index.Columns)
+	}
+	b = append(b, ")"...)
+
+	if index.Where != "" {
+		b = append(b, " WHERE "...)
+		b = append(b, index.Where...)
+	}
+	return b, nil
+}
+
+// trackTable starts tracking the definition of the table created from its inspected state.
+func (m *migrator) trackTable(tableName string, table sqlschema.Table) *tableDefinition {
+	t := &tableDefinition{
+		Schema:      m.schemaName,
+		Name:        tableName,
+		Columns:     table.GetColumns(),
+		PrimaryKey:  table.GetPrimaryKey(),
+		RawDefaults: make(map[string]string),
+	}
+	for _, u := range table.GetUniqueConstraints() {
+		t.Unique = append(t.Unique, uniqueDefinition{Unique: u})
+	}
+	m.tables[tableName] = t
+	return t
+}
+
+// TrackDatabase starts tracking the definitions of all tables in the state,
+// so that they are not inspected when altered, see sqlschema.DatabaseTracker.
+func (m *migrator) TrackDatabase(state sqlschema.Database) {
+	for _, table := range state.GetTables() {
+		t := m.trackTable(table.GetName(), table)
+		t.Columns = slices.Clone(t.Columns)
+		for _, index := range table.GetIndexes() {
+			t.Indexes = append(t.Indexes, indexDefinition{Index: index})
+		}
+	}
+	for fk := range state.GetForeignKeys() {
+		t, ok := m.tables[fk.From.TableName]
+		if !ok {
+			continue
+		}
+		t.ForeignKeys = append(t.ForeignKeys, &foreignKeyDefinition{
+			TargetTable: fk.To.TableName,
+			FromColumns: fk.From.Column.Split(),
+			ToColumns:   fk.To.Column.Split(),
+		})
+	}
+}
+
+// trackModel starts tracking the definition of the table created from the model.
+func (m *migrator) trackModel(tableName string, model any) {
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	table := m.db.Dialect().Tables().Get(typ)
+
+	t := &tableDefinition{
+		Schema:      m.schemaName,
+		Name:        tableName,
+		RawDefaults: make(map[string]string),
+	}
+	for _, f := range table.Fields {
+		sqlType, varcharLen := parseType(f.CreateTableSQLType)
+		t.Columns = append(t.Columns, &Column{
+			Name:         f.Name,
+			SQLType:      sqlType,
+			VarcharLen:   varcharLen,
+			DefaultValue: normalizeDefault(f.SQLDefault),
+			IsNullable:   !f.NotNull,
+			IsAutoIncrement: f.AutoIncrement && f.IsPK && len(table.PKs) == 1 &&
+				sqlType == "integer",
+		})
+		if f.SQLDefault != "" {
+			t.RawDefaults[f.Name] = f.SQLDefault
+		}
+	}
+
+	if len(table.PKs) > 0 {
+		var columns []string
+		for _, f := range table.PKs {
+			columns = append(columns, f.Name)
+		}
+		t.PrimaryKey = &sqlschema.PrimaryKey{Columns: sqlschema.NewColumns(columns...)}
+	}
+
+	for name, group := range table.Unique {
+		if name == "" {
+			for _, f := range group {
+				t.Unique = append(t.Unique, uniqueDefinition{
+					Unique: sqlschema.Unique{Columns: sqlschema.NewColumns(f.Name)},
+				})
+			}
+			continue
+		}
+		var columns []string
+		for _, f := range group {
+			columns = append(columns, f.Name)
+		}
+		t.Unique = append(t.Unique, uniqueDefinition{
+			Unique: sqlschema.Unique{Name: name, Columns: sqlschema.NewColumns(columns...)},
+		})
+	}
+
+	m.tables[tableName] = t
+}
+
+// trackRenameTable updates the tracked definitions of the renamed table
+// and the tables that reference it.
+func (m *migrator) trackRenameTable(ctx context.Context, rename *migrate.RenameTableOp) error {
+	t, err := m.table(ctx, rename.TableName)
+	if err != nil {
+		return err
+	}
+	delete(m.tables, rename.TableName)
+	t.Name = rename.NewName
+	m.tables[rename.NewName] = t
+
+	// CREATE INDEX statements still refer to the old table name.
+	for i := range t.Indexes {
+		t.Indexes[i].SQL = ""
+	}
+
+	for _, t := range m.tables {
+		for _, fk := range t.ForeignKeys {
+			if fk.TargetTable == rename.TableName {
+				fk.TargetTable = rename.NewName
+			}
+		}
+	}
+	return nil
+}
+
+// trackRenameColumn updates the tracked definitions of the table
+// and the tables that reference the renamed column.
+func (m *migrator) trackRenameColumn(t *tableDefinition, rename *migrate.RenameColumnOp) error {
+	col := t.Column(rename.OldName)
+	if col == nil {
+		return fmt.Errorf("table %q has no column %q", t.Name, rename.OldName)
+	}
+
+	t.ReplaceColumn(rename.OldName, &Column{
+		Name:            rename.NewName,
+		SQLType:         col.GetSQLType(),
+		VarcharLen:      col.GetVarcharLen(),
+		DefaultValue:    col.GetDefaultValue(),
+		IsNullable:      col.GetIsNullable(),
+		IsAutoIncrement: col.GetIsAutoIncrement(),
+		IsIdentity:      col.GetIsIdentity(),
+	})
+	if raw, ok := t.RawDefaults[rename.OldName]; ok {
+		delete(t.RawDefaults, rename.OldName)
+		t.RawDefaults[rename.NewName] = raw
+	}
+	if t.PrimaryKey != nil {
+		t.PrimaryKey.Columns.Replace(rename.OldName, rename.NewName)
+	}
+	for i := range t.Unique {
+		t.Unique[i].Columns.Replace(rename.OldName, rename.NewName)
+	}
+	for _, fk := range t.ForeignKeys {
+		replaceName(fk.FromColumns, rename.OldName, rename.NewName)
+	}
+	for i := range t.Indexes {
+		idx := &t.Indexes[i]
+		if idx.DependsOnColumn(rename.OldName) {
+			idx.Columns = slices.Clone(idx.Columns)
+			idx.Include = slices.Clone(idx.Include)
+			replaceName(idx.Columns, rename.OldName, rename.NewName)
+			replaceName(idx.Include, rename.OldName, rename.NewName)
+			idx.SQL = ""
+		}
+	}
+
+	for _, other := range m.tables {
+		for _, fk := range other.ForeignKeys {
+			if fk.TargetTable == t.Name {
+				replaceName(fk.ToColumns, rename.OldName, rename.NewName)
+			}
+		}
+	}
+	return nil
+}
+
+// tableDefinition is the complete definition of an SQLite table.
+type tableDefinition struct {
+	Schema string
+	Name   string
+
+	Columns    []sqlschema.Column
+	PrimaryKey *sqlschema.PrimaryKey
+	Unique     []uniqueDefinition
+
+	ForeignKeys []*foreignKeyDefinition
+
+	// RawDefaults maps column names to their DEFAULT expressions as they appear in CREATE TABLE.
+	// Column.DefaultValue is normalized for comparison and cannot always be used to re-create the column.
+	RawDefaults map[string]string
+
+	// Indexes defined on the table, except the UNIQUE indexes which are listed in Unique.
+	Indexes []indexDefinition
+
+	// Triggers hold CREATE statements for the triggers
+	// that will be dropped together with the table.
+	Triggers []string
+}
+
+// indexDefinition describes an index.
+type indexDefinition struct {
+	sqlschema.Index
+
+	// SQL is the original CREATE INDEX statement, which is used to re-create the index
+	// as long as it is up-to-date. If it is empty, the statement is generated from Index.
+	SQL string
+}
+
+// uniqueDefinition describes a UNIQUE constraint.
+type uniqueDefinition struct {
+	sqlschema.Unique
+
+	// IsIndex is true if the constraint is implemented by a UNIQUE INDEX,
+	// and false if it has been declared in the CREATE TABLE statement.
+	IsIndex bool
+}
+
+// foreignKeyDefinition describes a FOREIGN KEY constraint.
+// Unlike sqlschema.ForeignKey it preserves the order of the columns and referential actions.
+type foreignKeyDefinition struct {
+	TargetTable string
+	FromColumns []string
+	ToColumns   []string
+	OnUpdate    string
+	OnDelete    string
+}
+
+// ForeignKey returns sqlschema.ForeignKey for the constraint declared on the table.
+func (fk *foreignKeyDefinition) ForeignKey(tableName string) sqlschema.ForeignKey {
+	return sqlschema.ForeignKey{
+		From: sqlschema.NewColumnReference(tableName, fk.FromColumns...),
+		To:   sqlschema.NewColumnReference(fk.TargetTable, fk.ToColumns...),
+	}
+}
+
+// Table returns sqlschema.Table representation of the definition.
+func (t *tableDefinition) Table() *Table {
+	var unique []sqlschema.Unique
+	for _, u := range t.Unique {
+		unique = append(unique, u.Unique)
+	}
+	var indexes []sqlschema.Index
+	for _, idx := range t.Indexes {
+		indexes = append(indexes, idx.Index)
+	}
+	// Primary key columns are sorted to be comparable with the ones defined in the models.
+	var pk *sqlschema.PrimaryKey
+	if t.PrimaryKey != nil {
+		pk = &sqlschema.PrimaryKey{
+			Name:    t.PrimaryKey.Name,
+			Columns: sqlschema.NewColumns(t.PrimaryKey.Columns.Split()...),
+		}
+	}
+	return &Table{
+		Schema:            t.Schema,
+		Name:              t.Name,
+		Columns:           t.Columns,
+		PrimaryKey:        pk,
+		UniqueConstraints: unique,
+		Indexes:           indexes,
+	}
+}
+
+// Clone returns a copy of the definition which can be modified independently.
+func (t *tableDefinition) Clone() *tableDefinition {
+	clone := *t
+	clone.Columns = slices.Clone(t.Columns)
+	clone.Unique = slices.Clone(t.Unique)
+	clone.Indexes = slices.Clone(t.Indexes)
+	clone.Triggers = slices.Clone(t.Triggers)
+
+	if t.PrimaryKey != nil {
+		pk := *t.PrimaryKey
+		clone.PrimaryKey = &pk
+	}
+
+	clone.ForeignKeys = make([]*foreignKeyDefinition, 0, len(t.ForeignKeys))
+	for _, fk := range t.ForeignKeys {
+		fkCopy := *fk
+		fkCopy.FromColumns = slices.Clone(fk.FromColumns)
+		fkCopy.ToColumns = slices.Clone(fk.ToColumns)
+		clone.ForeignKeys = append(clone.ForeignKeys, &fkCopy)
+	}
+
+	clone.RawDefaults = make(map[string]string, len(t.RawDefaults))
+	for k, v := range t.RawDefaults {
+		clone.RawDefaults[k] = v
+	}
+	return &clone
+}
+
+// Column returns the column with the given name or nil if there isn't one.
+func (t *tableDefinition) Column(name string) sqlschema.Column {
+	for _, col := range t.Columns {
+		if col.GetName() == name {
+			return col
+		}
+	}
+	return nil
+}
+
+// ReplaceColumn replaces the column definition in place.
+func (t *tableDefinition) ReplaceColumn(name string, col sqlschema.Column) {
+	for i := range t.Columns {
+		if t.Columns[i].GetName() == name {
+			t.Columns[i] = col
+		}
+	}
+}
+
+// ChangeColumn sets a new definition for the column. The original DEFAULT expression
+// is preserved if the default value has not been changed.
+func (t *tableDefinition) ChangeColumn(name string, from, to sqlschema.Column) error {
+	if t.Column(name) == nil {
+		return fmt.Errorf("table %q has no column %q", t.Name, name)
+	}
+	if from.GetDefaultValue() != to.GetDefaultValue() {
+		delete(t.RawDefaults, name)
+	}
+	t.ReplaceColumn(name, newColumn(name, to))
+	return nil
+}
+
+// newColumn copies the column definition, which may not include its name.
+func newColumn(name string, col sqlschema.Column) *Column {
+	return &Column{
+		Name:            name,
+		SQLType:         col.GetSQLType(),
+		VarcharLen:      col.GetVarcharLen(),
+		DefaultValue:    col.GetDefaultValue(),
+		IsNullable:      col.GetIsNullable(),
+		IsAutoIncrement: col.GetIsAutoIncrement(),
+		IsIdentity:      col.GetIsIdentity(),
+	}
+}
+
+// DropColumn removes the column and the constraints which depend on it.
+func (t *tableDefinition) DropColumn(name string) {
+	t.Columns = slices.DeleteFunc(t.Columns, func(col sqlschema.Column) bool {
+		return col.GetName() == name
+	})
+	delete(t.RawDefaults, name)
+	t.Unique = slices.DeleteFunc(t.Unique, func(u uniqueDefinition) bool {
+		return u.Columns.Contains(name)
+	})
+	t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *foreignKeyDefinition) bool {
+		return slices.Contains(fk.FromColumns, name)
+	})
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(idx indexDefinition) bool {
+		return idx.DependsOnColumn(name)
+	})
+}
+
+// HasConstraintOn checks if the column is part of any PRIMARY KEY, UNIQUE or FOREIGN KEY constraint,
+// or is used in an index.
+func (t *tableDefinition) HasConstraintOn(name string) bool {
+	if t.PrimaryKey != nil && t.PrimaryKey.Columns.Contains(name) {
+		return true
+	}
+	for _, u := range t.Unique {
+		if u.Columns.Contains(name) {
+			return true
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		if slices.Contains(fk.FromColumns, name) {
+			return true
+		}
+	}
+	for _, idx := range t.Indexes {
+		if idx.DependsOnColumn(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func replaceName(names []string, oldName, newName string) {
+	for i := range names {
+		if names[i] == oldName {
+			names[i] = newName
+		}
+	}
+}
+
+func appendNames(gen schema.QueryGen, b []byte, names []string) []byte {
+	for i, name := range names {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = gen.AppendName(b, name)
+	}
+	return b
+}
+
+// isConstant checks if the normalized default value can be used with ADD COLUMN,
+// which does not allow CURRENT_* keywords and expressions in parentheses.
+func isConstant(def string) bool {
+	switch strings.ToLower(def) {
+	case "current_time", "current_date", "current_timestamp":
+		return false
+	}
+	return !strings.Contains(def, "(")
+}
+
+// appendDefault appends the normalized default value as an SQL expression.
+// Numbers, keywords and function calls are appended as-is, anything else is quoted as a string literal.
+func appendDefault(b []byte, def string) []byte {
+	if _, err := strconv.ParseFloat(def, 64); err == nil {
+		return append(b, def...)
+	}
+	switch strings.ToLower(def) {
+	case "null", "true", "false", "current_time", "current_date", "current_timestamp":
+		return append(b, def...)
+	}
+	if strings.Contains(def, "(") {
+		b = append(b, '(')
+		b = append(b, def...)
+		return append(b, ')')
+	}
+	b = append(b, '\'')
+	b = append(b, strings.ReplaceAll(def, "'", "''")...)
+	return append(b, '\'')
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/migrate/sqlschema"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -22,10 +23,16 @@ func init() {
 type Dialect struct {
 	schema.BaseDialect
 
-	tables   *schema.Tables
-	features feature.Feature
+	tables    *schema.Tables
+	features  feature.Feature
+	maxValues int
 }
 
+var _ schema.Dialect = (*Dialect)(nil)
+var _ schema.MaxValuesDialect = (*Dialect)(nil)
+var _ sqlschema.InspectorDialect = (*Dialect)(nil)
+var _ sqlschema.MigratorDialect = (*Dialect)(nil)
+
 func New(opts ...DialectOption) *Dialect {
 	d := new(Dialect)
 	d.tables = schema.NewTables(d)
@@ -42,7 +49,14 @@ func New(opts ...DialectOption) *Dialect {
 		feature.AutoIncrement |
 		feature.CompositeIn |
 		feature.FKDefaultOnAction |
-		feature.DeleteReturning
+		feature.DeleteReturning |
+		feature.RowValueCompare |
+		feature.NamedWindow |
+		feature.WindowGroups |
+		feature.WindowRangeOffset |
+		feature.WindowExclude
+
+	d.maxValues = 32766
 
 	for _, opt := range opts {
 		opt(d)
@@ -59,6 +73,14 @@ func WithoutFeature(other feature.Feature) DialectOption {
 	}
 }
 
+// WithMaxValues sets the maximum number of values in a single statement,
+// see schema.MaxValuesDialect. The default is 32766, the default SQLITE_MAX_VARIABLE_NUMBER.
+func WithMaxValues(n int) DialectOption {
+	return func(d *Dialect) {
+		d.maxValues = n
+	}
+}
+
 func (d *Dialect) Init(*sql.DB) {}
 
 func (d *Dialect) Name() dialect.Name {
@@ -69,6 +91,10 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+func (d *Dialect) MaxValues() int {
+	return d.maxValues
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
@@ -0,0 +1,401 @@
+package sqlitedialect
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+type (
+	Schema = sqlschema.BaseDatabase
+	Table  = sqlschema.BaseTable
+	Column = sqlschema.BaseColumn
+)
+
+func (d *Dialect) NewInspector(db *bun.DB, options ...sqlschema.InspectorOption) sqlschema.Inspector {
+	return newInspector(db, options...)
+}
+
+type Inspector struct {
+	sqlschema.InspectorConfig
+	db *bun.DB
+}
+
+var _ sqlschema.Inspector = (*Inspector)(nil)
+
+func newInspector(db *bun.DB, options ...sqlschema.InspectorOption) *Inspector {
+	i := &Inspector{db: db}
+	i.SchemaName = db.Dialect().DefaultSchema()
+	sqlschema.ApplyInspectorOptions(&i.InspectorConfig, options...)
+	return i
+}
+
+func (in *Inspector) Inspect(ctx context.Context) (sqlschema.Database, error) {
+	dbSchema := Schema{
+		ForeignKeys: make(map[sqlschema.ForeignKey]string),
+	}
+
+	var tables []*SQLiteMaster
+	q := in.db.NewSelect().
+		TableExpr("?.sqlite_master", bun.Ident(in.SchemaName)).
+		Column("name", "sql").
+		Where("type = 'table'").
+		Where(`name NOT LIKE 'sqlite\_%' ESCAPE '\'`).
+		OrderExpr("name")
+	for _, exclude := range in.ExcludeTables {
+		q = q.Where("name NOT LIKE ?", exclude)
+	}
+	if err := q.Scan(ctx, &tables); err != nil {
+		return dbSchema, err
+	}
+
+	for _, t := range tables {
+		def, err := in.inspectTable(ctx, t)
+		if err != nil {
+			return dbSchema, err
+		}
+		dbSchema.Tables = append(dbSchema.Tables, def.Table())
+
+		for _, fk := range def.ForeignKeys {
+			dbFK := fk.ForeignKey(t.Name)
+			if _, exclude := in.ExcludeForeignKeys[dbFK]; exclude {
+				continue
+			}
+			excludeTarget, err := in.isExcluded(ctx, fk.TargetTable)
+			if err != nil {
+				return dbSchema, err
+			}
+			if excludeTarget {
+				continue
+			}
+			dbSchema.ForeignKeys[dbFK] = ""
+		}
+	}
+	return dbSchema, nil
+}
+
+// isExcluded reports whether the table matches any of the ExcludeTables patterns.
+// SQLite evaluates the LIKE expressions to ensure the wildcards are interpreted
+// the same way as in the query which selects the tables.
+func (in *Inspector) isExcluded(ctx context.Context, tableName string) (bool, error) {
+	for _, pattern := range in.ExcludeTables {
+		var like bool
+		if err := in.db.NewRaw("SELECT ? LIKE ?", tableName, pattern).Scan(ctx, &like); err != nil {
+			return false, err
+		}
+		if like {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// inspectTable collects the complete definition of the table, including
+// the details (raw DEFAULT expressions, CREATE INDEX statements, triggers) that are required
+// to re-create the table but are not part of the sqlschema.Table interface.
+func (in *Inspector) inspectTable(ctx context.Context, t *SQLiteMaster) (*tableDefinition, error) {
+	def := &tableDefinition{
+		Schema:      in.SchemaName,
+		Name:        t.Name,
+		RawDefaults: make(map[string]string),
+	}
+
+	var columns []*TableInfo
+	if err := in.db.NewRaw(sqlInspectColumns, t.Name, in.SchemaName).Scan(ctx, &columns); err != nil {
+		return nil, err
+	}
+
+	// TableInfo.PK is the 1-based position of the column in the primary key,
+	// which does not have to match the order of the columns in the table.
+	var pkInfo []*TableInfo
+	for _, c := range columns {
+		if c.PK > 0 {
+			pkInfo = append(pkInfo, c)
+		}
+	}
+	slices.SortFunc(pkInfo, func(a, b *TableInfo) int {
+		return a.PK - b.PK
+	})
+	pkColumns := make([]string, 0, len(pkInfo))
+	for _, c := range pkInfo {
+		pkColumns = append(pkColumns, c.Name)
+	}
+	hasAutoIncrement := reAutoIncrement.MatchString(t.SQL)
+
+	for _, c := range columns {
+		sqlType, varcharLen := parseType(c.Type)
+		def.Columns = append(def.Columns, &Column{
+			Name:            c.Name,
+			SQLType:         sqlType,
+			VarcharLen:      varcharLen,
+			DefaultValue:    normalizeDefault(c.Default),
+			IsNullable:      !c.NotNull,
+			IsAutoIncrement: hasAutoIncrement && c.PK > 0 && len(pkColumns) == 1,
+		})
+		if c.Default != "" {
+			def.RawDefaults[c.Name] = c.Default
+		}
+	}
+
+	if len(pkColumns) > 0 {
+		// Unlike NewColumns, keep the declared order, so that the re-created table has the same primary key.
+		def.PrimaryKey = &sqlschema.PrimaryKey{Columns: sqlschema.Columns(strings.Join(pkColumns, ","))}
+	}
+
+	var indexColumns []*IndexInfo
+	if err := in.db.NewRaw(sqlInspectIndexes, t.Name, in.SchemaName, in.SchemaName).Scan(ctx, &indexColumns); err != nil {
+		return nil, err
+	}
+
+	var objects []*SQLiteMaster
+	if err := in.db.NewSelect().
+		TableExpr("?.sqlite_master", bun.Ident(in.SchemaName)).
+		Column("name", "sql", "type").
+		Where("type IN ('index', 'trigger')").
+		Where("tbl_name = ?", t.Name).
+		Where("sql IS NOT NULL").
+		OrderExpr("type, name").
+		Scan(ctx, &objects); err != nil {
+		return nil, err
+	}
+	indexSQL := make(map[string]string, len(objects))
+	for _, obj := range objects {
+		switch obj.Type {
+		case "index":
+			indexSQL[obj.Name] = obj.SQL
+		case "trigger":
+			def.Triggers = append(def.Triggers, obj.SQL)
+		}
+	}
+
+	// Each row describes one column of the index with the same name.
+	for start := 0; start < len(indexColumns); {
+		end := start
+		var columns []string
+		var hasExpressions bool
+		for end < len(indexColumns) && indexColumns[end].IndexName == indexColumns[start].IndexName {
+			// Columns of an expression index are reported with cid = -2.
+			if indexColumns[end].CID < 0 {
+				hasExpressions = true
+			}
+			columns = append(columns, indexColumns[end].ColumnName)
+			end++
+		}
+		idx := indexColumns[start]
+		start = end
+
+		// Full UNIQUE indexes on plain columns are reported as unique constraints.
+		if idx.IsUnique && !idx.IsPartial && !hasExpressions {
+			def.Unique = append(def.Unique, uniqueDefinition{
+				Unique: sqlschema.Unique{
+					Name:    idx.IndexName,
+					Columns: sqlschema.NewColumns(columns...),
+				},
+				IsIndex: idx.Origin == "c",
+			})
+			continue
+		}
+
+		query := indexSQL[idx.IndexName]
+		keys, where := parseIndexSQL(query)
+		index := sqlschema.Index{
+			Name:    idx.IndexName,
+			Unique:  idx.IsUnique,
+			Columns: columns,
+			Where:   where,
+		}
+		if hasExpressions {
+			index.Columns = nil
+			index.Expr = keys
+		}
+		def.Indexes = append(def.Indexes, indexDefinition{Index: index, SQL: query})
+	}
+
+	var fks []*ForeignKeyInfo
+	if err := in.db.NewRaw(sqlInspectForeignKeys, t.Name, in.SchemaName).Scan(ctx, &fks); err != nil {
+		return nil, err
+	}
+
+	// Each row describes one column pair of the FOREIGN KEY constraint with the same id.
+	for start := 0; start < len(fks); {
+		end := start
+		for end < len(fks) && fks[end].ID == fks[start].ID {
+			end++
+		}
+
+		fk := &foreignKeyDefinition{
+			TargetTable: fks[start].Table,
+			OnUpdate:    fks[start].OnUpdate,
+			OnDelete:    fks[start].OnDelete,
+		}
+		for _, row := range fks[start:end] {
+			fk.FromColumns = append(fk.FromColumns, row.From)
+			if row.To != "" {
+				fk.ToColumns = append(fk.ToColumns, row.To)
+			}
+		}
+		start = end
+
+		// REFERENCES clause without column names points to the primary key of the parent table.
+		if len(fk.ToColumns) == 0 {
+			if err := in.db.NewRaw(sqlInspectPrimaryKey, fk.TargetTable, in.SchemaName).
+				Scan(ctx, &fk.ToColumns); err != nil {
+				return nil, err
+			}
+		}
+		def.ForeignKeys = append(def.ForeignKeys, fk)
+	}
+
+	return def, nil
+}
+
+var reAutoIncrement = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`)
+
+// parseType splits declared column type into the type name and its length, if any.
+// Only single-argument types, e.g. VARCHAR(255), are split; types like DECIMAL(10,2)
+// are returned as-is.
+func parseType(typ string) (string, int) {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	paren := strings.Index(typ, "(")
+	if paren == -1 || !strings.HasSuffix(typ, ")") {
+		return typ, 0
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(typ[paren+1 : len(typ)-1]))
+	if err != nil {
+		return typ, 0
+	}
+	return strings.TrimSpace(typ[:paren]), length
+}
+
+// parseIndexSQL extracts the list of indexed columns and the WHERE condition
+// from the CREATE INDEX statement.
+func parseIndexSQL(query string) (keys, where string) {
+	on := reIndexOn.FindStringIndex(query)
+	if on == nil {
+		return "", ""
+	}
+	open := strings.IndexByte(query[on[1]:], '(')
+	if open == -1 {
+		return "", ""
+	}
+	open += on[1]
+
+	var depth int
+	var quote byte
+	for i := open; i < len(query); i++ {
+		switch c := query[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				keys = strings.TrimSpace(query[open+1 : i])
+				rest := strings.TrimSpace(query[i+1:])
+				if len(rest) > 5 && strings.EqualFold(rest[:5], "WHERE") {
+					where = strings.TrimSpace(rest[5:])
+				}
+				return keys, where
+			}
+		}
+	}
+	return "", ""
+}
+
+var reIndexOn = regexp.MustCompile(`(?i)\sON\s`)
+
+// normalizeDefault trims quotes around string literals and lowercases expressions,
+// which is the convention sqlschema.BunModelInspector uses for the model's defaults.
+func normalizeDefault(s string) string {
+	for len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return strings.ToLower(s)
+}
+
+type SQLiteMaster struct {
+	Type string `bun:"type"`
+	Name string `bun:"name"`
+	SQL  string `bun:"sql"`
+}
+
+type TableInfo struct {
+	CID     int    `bun:"cid"`
+	Name    string `bun:"name"`
+	Type    string `bun:"type"`
+	NotNull bool   `bun:"not_null"`
+	Default string `bun:"dflt_value"`
+	PK      int    `bun:"pk"`
+}
+
+type IndexInfo struct {
+	IndexName  string `bun:"index_name"`
+	IsUnique   bool   `bun:"is_unique"`
+	IsPartial  bool   `bun:"is_partial"`
+	Origin     string `bun:"origin"`
+	CID        int    `bun:"cid"`
+	ColumnName string `bun:"column_name"`
+}
+
+type ForeignKeyInfo struct {
+	ID       int    `bun:"id"`
+	Seq      int    `bun:"seq"`
+	Table    string `bun:"table"`
+	From     string `bun:"from"`
+	To       string `bun:"to"`
+	OnUpdate string `bun:"on_update"`
+	OnDelete string `bun:"on_delete"`
+}
+
+const (
+	// sqlInspectColumns retrieves column definitions for the specified table.
+	// Pass table name and schema name as arguments.
+	sqlInspectColumns = `
+SELECT cid, name, type, "notnull" AS not_null, COALESCE(dflt_value, '') AS dflt_value, pk
+FROM pragma_table_info(?, ?)
+ORDER BY cid
+`
+
+	// sqlInspectIndexes retrieves the columns of all indexes on the table,
+	// excluding the one that backs the PRIMARY KEY.
+	// Pass table name and schema name (twice) as arguments.
+	sqlInspectIndexes = `
+SELECT
+	il.name AS index_name,
+	il."unique" AS is_unique,
+	il.partial AS is_partial,
+	il.origin,
+	ii.cid,
+	COALESCE(ii.name, '') AS column_name
+FROM pragma_index_list(?, ?) AS il
+	JOIN pragma_index_info(il.name, ?) AS ii
+WHERE il.origin <> 'pk'
+ORDER BY il.seq, ii.seqno
+`
+
+	// sqlInspectPrimaryKey retrieves the columns of the table's PRIMARY KEY in the declared order.
+	// Pass table name and schema name as arguments.
+	sqlInspectPrimaryKey = `
+SELECT name FROM pragma_table_info(?, ?) WHERE pk > 0 ORDER BY pk
+`
+
+	// sqlInspectForeignKeys retrieves all FOREIGN KEY constraints declared on the table.
+	// Pass table name and schema name as arguments.
+	sqlInspectForeignKeys = `
+SELECT id, seq, "table", "from", COALESCE("to", '') AS "to", on_update, on_delete
+FROM pragma_foreign_key_list(?, ?)
+ORDER BY id, seq
+`
+)
@@ -0,0 +1,58 @@
+package sqlitedialect
+
+import (
+	"strings"
+
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+// Type affinities, see https://www.sqlite.org/datatype3.html#type_affinity.
+const (
+	affinityText    = "TEXT"
+	affinityNumeric = "NUMERIC"
+	affinityInteger = "INTEGER"
+	affinityReal    = "REAL"
+	affinityBlob    = "BLOB"
+)
+
+// CompareType returns true if col1 and col2 SQL types are equivalent.
+//
+// SQLite does not enforce declared types or their length, and stores values
+// according to the column's type affinity. Types with INTEGER, TEXT or REAL affinity,
+// e.g. INT ~ BIGINT or VARCHAR(100) ~ TEXT, are considered equivalent.
+// Types with NUMERIC and BLOB affinity are compared by name, so that
+// changing BOOLEAN to TIMESTAMP is still detected.
+func (d *Dialect) CompareType(col1, col2 sqlschema.Column) bool {
+	typ1, typ2 := strings.ToUpper(col1.GetSQLType()), strings.ToUpper(col2.GetSQLType())
+	if typ1 == typ2 {
+		return true
+	}
+
+	switch aff := typeAffinity(typ1); aff {
+	case affinityInteger, affinityText, affinityReal:
+		return aff == typeAffinity(typ2)
+	}
+	return false
+}
+
+// typeAffinity determines column affinity from its declared type
+// following the rules described in SQLite documentation.
+func typeAffinity(typ string) string {
+	typ = strings.ToUpper(typ)
+	switch {
+	case strings.Contains(typ, "INT"):
+		return affinityInteger
+	case strings.Contains(typ, "CHAR"),
+		strings.Contains(typ, "CLOB"),
+		strings.Contains(typ, "TEXT"):
+		return affinityText
+	case strings.Contains(typ, "BLOB"), typ == "":
+		return affinityBlob
+	case strings.Contains(typ, "REAL"),
+		strings.Contains(typ, "FLOA"),
+		strings.Contains(typ, "DOUB"):
+		return affinityReal
+	default:
+		return affinityNumeric
+	}
+}
@@ -0,0 +1,156 @@
+package pgdriver
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+type arrayType struct {
+	rawType
+	elemOID int32
+	elem    BinaryType
+}
+
+// DecodeBinary converts the array to the text representation, which is what pgdialect
+// scans arrays from:
+//
+//	ndim int32, flags int32, elem int32, [ndim](dim int32, lbound int32), elements
+//
+// where each element is its length or -1 for NULL followed by the value.
+func (t arrayType) DecodeBinary(b []byte) (any, error) {
+	if len(b) < 12 {
+		return nil, fmt.Errorf("pgdriver: invalid array length: %d", len(b))
+	}
+	ndim := int(binary.BigEndian.Uint32(b))
+	b = b[12:]
+	if ndim == 0 {
+		return []byte("{}"), nil
+	}
+	if len(b) < 8*ndim {
+		return nil, fmt.Errorf("pgdriver: invalid array with %d dimensions", ndim)
+	}
+
+	dims := make([]int, ndim)
+	for i := range dims {
+		dims[i] = int(binary.BigEndian.Uint32(b[8*i:]))
+	}
+	b = b[8*ndim:]
+
+	dst := make([]byte, 0, 2*len(b))
+	dst, b, err := t.appendDim(dst, b, dims)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, fmt.Errorf("pgdriver: array has %d extra bytes", len(b))
+	}
+	return dst, nil
+}
+
+func (t arrayType) appendDim(dst, b []byte, dims []int) (_, _ []byte, err error) {
+	dst = append(dst, '{')
+	for i := 0; i < dims[0]; i++ {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		if len(dims) > 1 {
+			dst, b, err = t.appendDim(dst, b, dims[1:])
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if len(b) < 4 {
+			return nil, nil, fmt.Errorf("pgdriver: array is too short")
+		}
+		n := int(int32(binary.BigEndian.Uint32(b)))
+		b = b[4:]
+
+		if n == -1 {
+			dst = append(dst, "NULL"...)
+			continue
+		}
+		if n < 0 || n > len(b) {
+			return nil, nil, fmt.Errorf("pgdriver: invalid array element length: %d", n)
+		}
+
+		elem, err := t.elem.DecodeBinary(b[:n])
+		if err != nil {
+			return nil, nil, err
+		}
+		b = b[n:]
+
+		dst = t.appendElem(dst, elem)
+	}
+	dst = append(dst, '}')
+	return dst, b, nil
+}
+
+// appendElem appends the element in the same text representation as PostgreSQL.
+func (t arrayType) appendElem(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return append(b, "NaN"...)
+		case math.IsInf(v, 1):
+			return append(b, "Infinity"...)
+		case math.IsInf(v, -1):
+			return append(b, "-Infinity"...)
+		}
+		bitSize := 64
+		if t.elemOID == pgFloat4 {
+			bitSize = 32
+		}
+		return strconv.AppendFloat(b, v, 'g', -1, bitSize)
+	case bool:
+		if v {
+			return append(b, 't')
+		}
+		return append(b, 'f')
+	case time.Time:
+		b = append(b, '"')
+		if t.elemOID == pgTimestamptz {
+			b = v.AppendFormat(b, "2006-01-02 15:04:05.999999-07:00")
+		} else {
+			b = v.AppendFormat(b, "2006-01-02 15:04:05.999999")
+		}
+		return append(b, '"')
+	case []byte:
+		if t.elemOID == pgBytea {
+			b = append(b, `"\\x`...)
+			b = hex.AppendEncode(b, v)
+			return append(b, '"')
+		}
+		return appendArrayString(b, v)
+	case string:
+		return appendArrayString(b, []byte(v))
+	default:
+		return appendArrayString(b, fmt.Append(nil, v))
+	}
+}
+
+func appendArrayString(b, s []byte) []byte {
+	b = append(b, '"')
+	for _, c := range s {
+		if c == '"' || c == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	return append(b, '"')
+}
+
+// AppendBinary does not encode arrays, which are passed as strings in the text representation.
+func (arrayType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	return b, false
+}
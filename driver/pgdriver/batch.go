@@ -0,0 +1,270 @@
+package pgdriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrBatchAborted is the error of the queries in a batch which are not executed or rolled back,
+// because another query in the batch failed.
+var ErrBatchAborted = errors.New("pgdriver: batch is aborted because another query failed")
+
+// Batch queues queries which Conn.SendBatch sends to the server in one round-trip.
+//
+// The queries are executed in an implicit transaction, unless the batch runs in a transaction,
+// so if a query fails, the previous queries are rolled back and the next ones are not executed.
+// Each query must be a single statement.
+type Batch struct {
+	queries []batchQuery
+	err     error
+}
+
+type batchQuery struct {
+	query string
+	args  []driver.NamedValue
+}
+
+// Queue adds the query with the $1, $2, ... placeholders for args to the batch.
+func (b *Batch) Queue(query string, args ...any) *Batch {
+	namedArgs := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		value, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil && b.err == nil {
+			b.err = fmt.Errorf("pgdriver: batch query #%d: %w", len(b.queries), err)
+		}
+		namedArgs[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+	}
+	b.queries = append(b.queries, batchQuery{query: query, args: namedArgs})
+	return b
+}
+
+// Len returns the number of queued queries.
+func (b *Batch) Len() int {
+	return len(b.queries)
+}
+
+// BatchResult is the result of a query in a batch.
+type BatchResult struct {
+	RowsAffected int64
+	// Err is the query error or ErrBatchAborted.
+	Err error
+}
+
+// SendBatch sends the queued queries using the extended query protocol with a single Sync
+// message and returns the result of each query. The returned error is only set when
+// the batch could not be executed, e.g. because of a network error or cancellation.
+//
+//	err := db.Conn.Raw(func(driverConn any) error {
+//		results, err := driverConn.(*pgdriver.Conn).SendBatch(ctx, batch)
+//		...
+//	})
+func (cn *Conn) SendBatch(ctx context.Context, b *Batch) ([]BatchResult, error) {
+	if cn.isClosed() {
+		return nil, driver.ErrBadConn
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.queries) == 0 {
+		return nil, nil
+	}
+	cn.trace(ctx)
+
+	queryCtx, c := cn.watchCancel(ctx)
+	results, err := cn.sendBatch(queryCtx, b)
+	if err != nil {
+		return nil, cn.checkBadConn(c.finish(err))
+	}
+
+	var queryErr error
+	for i := range results {
+		if err := results[i].Err; err != nil && err != ErrBatchAborted {
+			queryErr = err
+			break
+		}
+	}
+	switch err := c.finish(queryErr); err {
+	case context.Canceled, context.DeadlineExceeded:
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (cn *Conn) sendBatch(ctx context.Context, b *Batch) ([]BatchResult, error) {
+	wb := getWriteBuffer()
+	defer putWriteBuffer(wb)
+
+	if err := appendBatch(wb, b); err != nil {
+		return nil, err
+	}
+
+	// The server responds to the queries while the batch is being written. Responses are read
+	// concurrently, because once the socket buffers fill up, neither side could make progress.
+	// The first failure unblocks the other side, whose error is then only a consequence.
+	var once sync.Once
+	var firstErr error
+	fail := func(err error, unblock func(time.Time) error) {
+		once.Do(func() {
+			firstErr = err
+			_ = unblock(time.Now())
+		})
+	}
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		if err := cn.write(ctx, wb); err != nil {
+			fail(err, cn.netConn.SetReadDeadline)
+		}
+	}()
+
+	results, err := readBatch(ctx, cn, len(b.queries))
+	if err != nil {
+		fail(err, cn.netConn.SetWriteDeadline)
+	}
+	<-written
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+var _ bun.BatchExecer = (*Conn)(nil)
+
+// ExecBatch executes the queries in one round-trip, see SendBatch. It returns the results
+// of the queries or the error of the failed query.
+func (cn *Conn) ExecBatch(ctx context.Context, queries []string) ([]driver.Result, error) {
+	b := new(Batch)
+	for _, query := range queries {
+		b.Queue(query)
+	}
+
+	results, err := cn.SendBatch(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]driver.Result, len(results))
+	for i, r := range results {
+		if r.Err != nil && r.Err != ErrBatchAborted {
+			return nil, fmt.Errorf("pgdriver: batch query #%d: %w", i, r.Err)
+		}
+		res[i] = driver.RowsAffected(r.RowsAffected)
+	}
+	return res, nil
+}
+
+func appendBatch(wb *writeBuffer, b *Batch) error {
+	for _, q := range b.queries {
+		wb.StartMessage(parseMsg)
+		wb.WriteString("")
+		wb.WriteString(q.query)
+		wb.WriteInt16(0)
+		wb.FinishMessage()
+
+		wb.StartMessage(bindMsg)
+		wb.WriteString("")
+		wb.WriteString("")
+		wb.WriteInt16(0)
+		wb.WriteInt16(int16(len(q.args)))
+		for i := range q.args {
+			wb.StartParam()
+			bytes, err := appendStmtArg(wb.Bytes, q.args[i].Value)
+			if err != nil {
+				return err
+			}
+			if bytes != nil {
+				wb.Bytes = bytes
+				wb.FinishParam()
+			} else {
+				wb.FinishNullParam()
+			}
+		}
+		wb.WriteInt16(0)
+		wb.FinishMessage()
+
+		wb.StartMessage(executeMsg)
+		wb.WriteString("")
+		wb.WriteInt32(0)
+		wb.FinishMessage()
+	}
+
+	wb.StartMessage(syncMsg)
+	wb.FinishMessage()
+
+	return nil
+}
+
+func readBatch(ctx context.Context, cn *Conn, numQuery int) ([]BatchResult, error) {
+	rd := cn.reader(ctx, -1)
+	results := make([]BatchResult, numQuery)
+	failed := -1
+	i := 0
+	for {
+		c, msgLen, err := readMessageType(rd)
+		if err != nil {
+			return nil, err
+		}
+
+		switch c {
+		case parseCompleteMsg, bindCompleteMsg, dataRowMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return nil, err
+			}
+		case commandCompleteMsg: // response to EXECUTE message.
+			tmp, err := rd.ReadTemp(msgLen)
+			if err != nil {
+				return nil, err
+			}
+			if i < numQuery {
+				affected, err := parseResult(tmp)
+				if err != nil {
+					return nil, err
+				}
+				results[i].RowsAffected = int64(affected)
+			}
+			i++
+		case emptyQueryResponseMsg:
+			if i < numQuery {
+				results[i].Err = errEmptyQuery
+			}
+			i++
+		case errorResponseMsg:
+			e, err := readError(rd)
+			if err != nil {
+				return nil, err
+			}
+			// The server skips the rest of the queries until SYNC.
+			if failed == -1 && i < numQuery {
+				results[i].Err = e
+				failed = i
+			}
+		case readyForQueryMsg: // Response to SYNC message.
+			if err := rd.Discard(msgLen); err != nil {
+				return nil, err
+			}
+			if failed != -1 {
+				for j := range results {
+					if j != failed {
+						results[j].Err = ErrBatchAborted
+					}
+				}
+			}
+			return results, nil
+		case noticeResponseMsg, parameterStatusMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("pgdriver: readBatch: unexpected message %q", c)
+		}
+	}
+}
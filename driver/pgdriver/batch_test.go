@@ -0,0 +1,218 @@
+package pgdriver_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestSendBatch(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	srv := &batchServer{t: t, ln: ln}
+	go srv.serve()
+
+	db := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithAddr(ln.Addr().String()),
+		pgdriver.WithInsecure(true),
+		pgdriver.WithUser("test"),
+	))
+	defer db.Close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sendBatch := func(b *pgdriver.Batch) (results []pgdriver.BatchResult, err error) {
+		rawErr := conn.Raw(func(driverConn any) error {
+			results, err = driverConn.(*pgdriver.Conn).SendBatch(ctx, b)
+			return nil
+		})
+		require.NoError(t, rawErr)
+		return results, err
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		b := new(pgdriver.Batch).
+			Queue("INSERT INTO test VALUES ($1)", 1).
+			Queue("UPDATE test SET n = $1", 2).
+			Queue("DELETE FROM test")
+		require.Equal(t, 3, b.Len())
+
+		results, err := sendBatch(b)
+		require.NoError(t, err)
+		require.Equal(t, []pgdriver.BatchResult{
+			{RowsAffected: 1},
+			{RowsAffected: 3},
+			{RowsAffected: 5},
+		}, results)
+		require.Equal(t, int32(1), srv.syncs.Load())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		b := new(pgdriver.Batch).
+			Queue("INSERT INTO test VALUES ($1)", 1).
+			Queue("INSERT INTO fail VALUES ($1)", 2).
+			Queue("DELETE FROM test")
+
+		results, err := sendBatch(b)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.Equal(t, pgdriver.ErrBatchAborted, results[0].Err)
+		require.Equal(t, pgdriver.ErrBatchAborted, results[2].Err)
+
+		pgErr, ok := results[1].Err.(pgdriver.Error)
+		require.True(t, ok)
+		require.Equal(t, "42P01", pgErr.Field('C'))
+		require.Equal(t, int32(2), srv.syncs.Load())
+	})
+
+	t.Run("larger than socket buffers", func(t *testing.T) {
+		// The server responds with large rows while the client is still writing large queries,
+		// so the batch deadlocks unless the responses are read while it is written.
+		arg := strings.Repeat("x", 4096)
+		b := new(pgdriver.Batch)
+		for i := 0; i < 5000; i++ {
+			b.Queue("SELECT $1", arg)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		var results []pgdriver.BatchResult
+		err := conn.Raw(func(driverConn any) (err error) {
+			results, err = driverConn.(*pgdriver.Conn).SendBatch(ctx, b)
+			return err
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 5000)
+		require.Equal(t, pgdriver.BatchResult{RowsAffected: 1}, results[4999])
+	})
+
+	t.Run("ExecBatch", func(t *testing.T) {
+		err := conn.Raw(func(driverConn any) error {
+			res, err := driverConn.(*pgdriver.Conn).ExecBatch(ctx, []string{
+				"INSERT INTO test VALUES (1)",
+				"UPDATE test SET n = 2",
+			})
+			require.NoError(t, err)
+			require.Len(t, res, 2)
+
+			n, err := res[1].RowsAffected()
+			require.NoError(t, err)
+			require.Equal(t, int64(3), n)
+
+			_, err = driverConn.(*pgdriver.Conn).ExecBatch(ctx, []string{
+				"INSERT INTO test VALUES (1)",
+				"INSERT INTO fail VALUES (1)",
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "batch query #1")
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+// largeDataRow is a row with a single 8KB column.
+var largeDataRow = func() []byte {
+	b := binary.BigEndian.AppendUint16(nil, 1)
+	b = binary.BigEndian.AppendUint32(b, 8192)
+	return append(b, make([]byte, 8192)...)
+}()
+
+// batchServer is a PostgreSQL stand-in which responds to the extended query protocol
+// and fails the queries on the "fail" table.
+type batchServer struct {
+	t     *testing.T
+	ln    net.Listener
+	syncs atomic.Int32
+}
+
+func (srv *batchServer) serve() {
+	for {
+		conn, err := srv.ln.Accept()
+		if err != nil {
+			return
+		}
+		go srv.serveConn(conn)
+	}
+}
+
+func (srv *batchServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	if _, err := readStartupMessage(conn); err != nil {
+		return
+	}
+
+	var key []byte
+	key = binary.BigEndian.AppendUint32(key, cancelProcessID)
+	key = binary.BigEndian.AppendUint32(key, cancelSecretKey)
+
+	writeMessage(conn, 'R', []byte{0, 0, 0, 0})
+	writeMessage(conn, 'K', key)
+	writeMessage(conn, 'Z', []byte("I"))
+
+	var query string
+	var failed bool
+	for {
+		c, b, err := readMessage(conn)
+		if err != nil || c == 'X' {
+			return
+		}
+
+		switch c {
+		case 'P':
+			if failed {
+				continue
+			}
+			// Skip the statement name.
+			query = string(b[strings.IndexByte(string(b), 0)+1:])
+			query = query[:strings.IndexByte(query, 0)]
+			writeMessage(conn, '1', nil)
+		case 'B':
+			if !failed {
+				writeMessage(conn, '2', nil)
+			}
+		case 'E':
+			if failed {
+				continue
+			}
+			switch {
+			case strings.Contains(query, "fail"):
+				writeMessage(conn, 'E', []byte(
+					"SERROR\x00C42P01\x00Mrelation \"fail\" does not exist\x00\x00"))
+				failed = true
+			case strings.HasPrefix(query, "INSERT"):
+				writeMessage(conn, 'C', []byte("INSERT 0 1\x00"))
+			case strings.HasPrefix(query, "SELECT"):
+				writeMessage(conn, 'D', largeDataRow)
+				writeMessage(conn, 'C', []byte("SELECT 1\x00"))
+			case strings.HasPrefix(query, "UPDATE"):
+				writeMessage(conn, 'C', []byte("UPDATE 3\x00"))
+			default:
+				writeMessage(conn, 'C', []byte("DELETE 5\x00"))
+			}
+		case 'S':
+			srv.syncs.Add(1)
+			failed = false
+			writeMessage(conn, 'Z', []byte("I"))
+		default:
+			srv.t.Errorf("unexpected message %q", c)
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package pgdriver
+
+import (
+	"context"
+	"time"
+)
+
+// watchCancel makes the query cancelable with ctx. When ctx is done, it sends a CancelRequest
+// on a separate connection, so the server stops the query, and the conn can be reused
+// after the rest of the query results are read.
+//
+// The query must use the returned context, which does not have the ctx deadline, so the reads
+// are not interrupted before the server responds to the cancellation, and its error must be
+// passed to canceler.finish.
+func (cn *Conn) watchCancel(ctx context.Context) (context.Context, *canceler) {
+	c := &canceler{cn: cn, ctx: ctx}
+	if ctx.Done() == nil {
+		return ctx, c
+	}
+	c.done = make(chan struct{})
+	c.stop = context.AfterFunc(ctx, c.cancel)
+	return context.WithoutCancel(ctx), c
+}
+
+type canceler struct {
+	cn   *Conn
+	ctx  context.Context
+	stop func() bool
+	done chan struct{}
+	err  error
+}
+
+func (c *canceler) cancel() {
+	defer close(c.done)
+
+	if c.err = c.cn.cancelRequest(context.WithoutCancel(c.ctx)); c.err != nil {
+		Logger.Printf(c.ctx, "pgdriver: CancelRequest failed: %s", c.err)
+		// The query is not going to be canceled, so interrupt it instead.
+		_ = c.cn.netConn.SetDeadline(time.Now())
+	}
+}
+
+// finish stops watching the context and returns the query error. If the query was canceled,
+// it returns ctx.Err(). The conn is closed if the cancellation failed or did not cancel the query,
+// because the server can still apply it to the next query.
+func (c *canceler) finish(err error) error {
+	if c.stop == nil || c.stop() {
+		return err
+	}
+	<-c.done
+
+	if c.err == nil && isCanceled(err) {
+		// The results were read up to ReadyForQuery, so the conn can be reused.
+		return c.ctx.Err()
+	}
+	_ = c.cn.Close()
+	return err
+}
+
+// cancelRequest asks the server to cancel the query which is running on the conn.
+//
+// https://www.postgresql.org/docs/current/protocol-flow.html#PROTOCOL-FLOW-CANCELING-REQUESTS
+func (cn *Conn) cancelRequest(ctx context.Context) error {
+	netConn, err := cn.conf.Dialer(ctx, cn.conf.Network, cn.addr)
+	if err != nil {
+		return err
+	}
+
+	side := &Conn{
+		conf:    cn.conf,
+		addr:    cn.addr,
+		netConn: netConn,
+		rd:      newReader(netConn, 64),
+	}
+	defer side.Close()
+
+	if cn.conf.TLSConfig != nil {
+		if err := enableSSL(ctx, side, cn.conf.tlsConfig(cn.addr)); err != nil {
+			return err
+		}
+	}
+
+	if err := writeCancelRequest(ctx, side, cn.processID, cn.secretKey); err != nil {
+		return err
+	}
+
+	// The server closes the connection once the request is processed.
+	_, _ = side.reader(ctx, -1).ReadByte()
+	return nil
+}
+
+func isCanceled(err error) bool {
+	pgErr, ok := err.(Error)
+	return ok && pgErr.StatementTimeout()
+}
@@ -0,0 +1,220 @@
+package pgdriver_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestCancelRequest(t *testing.T) {
+	srv := newCancelServer(t)
+
+	db := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithAddr(srv.ln.Addr().String()),
+		pgdriver.WithInsecure(true),
+		pgdriver.WithUser("test"),
+	))
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	t.Run("exec", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, "SELECT pg_sleep(10)")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, [2]int32{cancelProcessID, cancelSecretKey}, <-srv.canceled)
+
+		var n int
+		err = db.QueryRow("SELECT 1").Scan(&n)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, "SELECT generate_series(1, 1000000)")
+		require.NoError(t, err)
+
+		require.True(t, rows.Next())
+		var n int
+		require.NoError(t, rows.Scan(&n))
+		require.Equal(t, 1, n)
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+		for rows.Next() {
+		}
+		require.ErrorIs(t, rows.Err(), context.Canceled)
+		require.NoError(t, rows.Close())
+		require.Equal(t, [2]int32{cancelProcessID, cancelSecretKey}, <-srv.canceled)
+
+		err = db.QueryRow("SELECT 1").Scan(&n)
+		require.NoError(t, err)
+	})
+
+	require.Equal(t, int32(1), srv.startups.Load())
+}
+
+const (
+	cancelProcessID = 1234
+	cancelSecretKey = 5678
+)
+
+// cancelServer is a PostgreSQL stand-in which runs the queries with pg_sleep and generate_series
+// until they are canceled with CancelRequest.
+type cancelServer struct {
+	t        *testing.T
+	ln       net.Listener
+	canceled chan [2]int32
+	startups atomic.Int32
+}
+
+func newCancelServer(t *testing.T) *cancelServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	srv := &cancelServer{
+		t:        t,
+		ln:       ln,
+		canceled: make(chan [2]int32, 1),
+	}
+	go srv.serve()
+	return srv
+}
+
+func (srv *cancelServer) serve() {
+	for {
+		conn, err := srv.ln.Accept()
+		if err != nil {
+			return
+		}
+		go srv.serveConn(conn)
+	}
+}
+
+func (srv *cancelServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	b, err := readStartupMessage(conn)
+	if err != nil {
+		return
+	}
+
+	if code := binary.BigEndian.Uint32(b); code == 80877102 {
+		srv.canceled <- [2]int32{
+			int32(binary.BigEndian.Uint32(b[4:])),
+			int32(binary.BigEndian.Uint32(b[8:])),
+		}
+		return
+	}
+	srv.startups.Add(1)
+
+	var key []byte
+	key = binary.BigEndian.AppendUint32(key, cancelProcessID)
+	key = binary.BigEndian.AppendUint32(key, cancelSecretKey)
+
+	writeMessage(conn, 'R', []byte{0, 0, 0, 0})
+	writeMessage(conn, 'K', key)
+	writeMessage(conn, 'Z', []byte("I"))
+
+	for {
+		c, b, err := readMessage(conn)
+		if err != nil || c == 'X' {
+			return
+		}
+		if c != 'Q' {
+			srv.t.Errorf("unexpected message %q", c)
+			return
+		}
+
+		query := strings.TrimSuffix(string(b), "\x00")
+		switch {
+		case strings.Contains(query, "pg_sleep"):
+			srv.waitCancel(conn)
+		case strings.Contains(query, "generate_series"):
+			writeMessage(conn, 'T', rowDescription("generate_series"))
+			writeMessage(conn, 'D', dataRow("1"))
+			srv.waitCancel(conn)
+		default:
+			writeMessage(conn, 'T', rowDescription("?column?"))
+			writeMessage(conn, 'D', dataRow("1"))
+			writeMessage(conn, 'C', []byte("SELECT 1\x00"))
+			writeMessage(conn, 'Z', []byte("I"))
+		}
+	}
+}
+
+func (srv *cancelServer) waitCancel(conn net.Conn) {
+	select {
+	case key := <-srv.canceled:
+		// Let the test check the key.
+		srv.canceled <- key
+	case <-time.After(5 * time.Second):
+		srv.t.Error("query is not canceled")
+		return
+	}
+
+	writeMessage(conn, 'E', []byte("SERROR\x00C57014\x00Mcanceling statement due to user request\x00\x00"))
+	writeMessage(conn, 'Z', []byte("I"))
+}
+
+func readStartupMessage(r io.Reader) ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint32(hdr[:])-4)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+func readMessage(r io.Reader) (byte, []byte, error) {
+	var c [1]byte
+	if _, err := io.ReadFull(r, c[:]); err != nil {
+		return 0, nil, err
+	}
+	b, err := readStartupMessage(r)
+	return c[0], b, err
+}
+
+func writeMessage(w io.Writer, c byte, b []byte) {
+	msg := []byte{c}
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(b)+4))
+	_, _ = w.Write(append(msg, b...))
+}
+
+func rowDescription(name string) []byte {
+	return typedRowDescription(name, 23) // int4
+}
+
+func typedRowDescription(name string, oid uint32) []byte {
+	b := binary.BigEndian.AppendUint16(nil, 1)
+	b = append(b, name...)
+	b = append(b, 0)
+	b = binary.BigEndian.AppendUint32(b, 0)   // table oid
+	b = binary.BigEndian.AppendUint16(b, 0)   // column number
+	b = binary.BigEndian.AppendUint32(b, oid) // type oid
+	b = binary.BigEndian.AppendUint16(b, 0)   // type size
+	b = binary.BigEndian.AppendUint32(b, 0)   // type modifier
+	b = binary.BigEndian.AppendUint16(b, 0)   // text format
+	return b
+}
+
+func dataRow(value string) []byte {
+	b := binary.BigEndian.AppendUint16(nil, 1)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
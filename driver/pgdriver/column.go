@@ -1,14 +1,10 @@
 package pgdriver
 
 import (
-	"encoding/hex"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 	"time"
-
-	"github.com/uptrace/bun/internal"
 )
 
 const (
@@ -28,133 +24,94 @@ const (
 	pgDate        = 1082
 	pgTimestamp   = 1114
 	pgTimestamptz = 1184
+
+	pgNumeric = 1700
+	pgUUID    = 2950
+	pgJSON    = 114
+	pgJSONB   = 3802
+
+	pgBoolArray        = 1000
+	pgByteaArray       = 1001
+	pgInt2Array        = 1005
+	pgInt4Array        = 1007
+	pgTextArray        = 1009
+	pgVarcharArray     = 1015
+	pgInt8Array        = 1016
+	pgFloat4Array      = 1021
+	pgFloat8Array      = 1022
+	pgTimestampArray   = 1115
+	pgDateArray        = 1182
+	pgTimestamptzArray = 1185
+	pgNumericArray     = 1231
+	pgUUIDArray        = 2951
+	pgJSONBArray       = 3807
+)
+
+// Format codes of the column values and arguments in the extended query protocol.
+const (
+	textFormat   = 0
+	binaryFormat = 1
 )
 
-func readColumnValue(rd *reader, dataType int32, dataLen int) (any, error) {
+func readColumnValue(rd *reader, types *TypeRegistry, dataType int32, format int16, dataLen int) (any, error) {
 	if dataLen == -1 {
 		return nil, nil
 	}
 
-	switch dataType {
-	case pgBool:
-		return readBoolCol(rd, dataLen)
-	case pgInt2:
-		return readIntCol(rd, dataLen, 16)
-	case pgInt4:
-		return readIntCol(rd, dataLen, 32)
-	case pgInt8:
-		return readIntCol(rd, dataLen, 64)
-	case pgFloat4:
-		return readFloatCol(rd, dataLen, 32)
-	case pgFloat8:
-		return readFloatCol(rd, dataLen, 64)
-	case pgTimestamp:
-		return readTimeCol(rd, dataLen)
-	case pgTimestamptz:
-		return readTimeCol(rd, dataLen)
-	case pgDate:
-		// Return a string and let the scanner convert the string to time.Time if necessary.
-		return readStringCol(rd, dataLen)
-	case pgText, pgVarchar:
-		return readStringCol(rd, dataLen)
-	case pgBytea:
-		return readBytesCol(rd, dataLen)
-	}
+	typ := types.Lookup(dataType)
 
-	b := make([]byte, dataLen)
-	if _, err := io.ReadFull(rd, b); err != nil {
-		return nil, err
+	if format == binaryFormat {
+		typ, ok := typ.(BinaryType)
+		if !ok {
+			return nil, fmt.Errorf("pgdriver: type oid=%d does not support the binary format", dataType)
+		}
+		tmp, err := rd.ReadTemp(dataLen)
+		if err != nil {
+			return nil, err
+		}
+		return typ.DecodeBinary(tmp)
 	}
-	return b, nil
-}
 
-func readBoolCol(rd *reader, n int) (any, error) {
-	tmp, err := rd.ReadTemp(n)
-	if err != nil {
-		return nil, err
+	switch typ := typ.(type) {
+	case nil:
+		return readRawCol(rd, dataLen)
+	case columnReader:
+		return typ.readColumn(rd, dataLen)
+	default:
+		tmp, err := rd.ReadTemp(dataLen)
+		if err != nil {
+			return nil, err
+		}
+		return typ.DecodeText(tmp)
 	}
-	return len(tmp) == 1 && (tmp[0] == 't' || tmp[0] == '1'), nil
 }
 
-func readIntCol(rd *reader, n int, bitSize int) (any, error) {
-	if n <= 0 {
-		return 0, nil
-	}
+// decodeColumnValue decodes the value which is already read, e.g. from COPY data.
+func decodeColumnValue(types *TypeRegistry, dataType int32, format int16, b []byte) (any, error) {
+	typ := types.Lookup(dataType)
 
-	tmp, err := rd.ReadTemp(n)
-	if err != nil {
-		return 0, err
-	}
-
-	return strconv.ParseInt(internal.String(tmp), 10, bitSize)
-}
-
-func readFloatCol(rd *reader, n int, bitSize int) (any, error) {
-	if n <= 0 {
-		return 0, nil
+	if format == binaryFormat {
+		typ, ok := typ.(BinaryType)
+		if !ok {
+			return nil, fmt.Errorf("pgdriver: type oid=%d does not support the binary format", dataType)
+		}
+		return typ.DecodeBinary(b)
 	}
 
-	tmp, err := rd.ReadTemp(n)
-	if err != nil {
-		return 0, err
+	if typ == nil {
+		return append([]byte(nil), b...), nil
 	}
-
-	return strconv.ParseFloat(internal.String(tmp), bitSize)
+	return typ.DecodeText(b)
 }
 
-func readStringCol(rd *reader, n int) (any, error) {
-	if n <= 0 {
-		return "", nil
-	}
-
+func readRawCol(rd *reader, n int) (any, error) {
 	b := make([]byte, n)
-
 	if _, err := io.ReadFull(rd, b); err != nil {
 		return nil, err
 	}
-
-	return internal.String(b), nil
-}
-
-func readBytesCol(rd *reader, n int) (any, error) {
-	if n <= 0 {
-		return []byte{}, nil
-	}
-
-	tmp, err := rd.ReadTemp(n)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(tmp) < 2 || tmp[0] != '\\' || tmp[1] != 'x' {
-		return nil, fmt.Errorf("pgdriver: can't parse bytea: %q", tmp)
-	}
-	tmp = tmp[2:] // Cut off "\x".
-
-	b := make([]byte, hex.DecodedLen(len(tmp)))
-	if _, err := hex.Decode(b, tmp); err != nil {
-		return nil, err
-	}
 	return b, nil
 }
 
-func readTimeCol(rd *reader, n int) (any, error) {
-	if n <= 0 {
-		return time.Time{}, nil
-	}
-
-	tmp, err := rd.ReadTemp(n)
-	if err != nil {
-		return time.Time{}, err
-	}
-
-	tm, err := ParseTime(internal.String(tmp))
-	if err != nil {
-		return time.Time{}, err
-	}
-	return tm, nil
-}
-
 const (
 	dateFormat         = "2006-01-02"
 	timeFormat         = "15:04:05.999999999"
@@ -20,6 +20,15 @@ type Config struct {
 	Network string
 	// TCP host:port or Unix socket depending on Network.
 	Addr string
+	// TCP host:port addresses of a multi-host setup, e.g. a primary and its standbys.
+	// The addresses are tried in order until a server matching TargetSessionAttrs is found.
+	// Addr is ignored when Addrs is set.
+	Addrs []string
+	// TargetSessionAttrs is the required session type when Addrs is set:
+	// any (default), read-write, read-only, primary, standby or prefer-standby.
+	TargetSessionAttrs string
+	// LoadBalanceHosts tries Addrs in random order.
+	LoadBalanceHosts bool
 	// Dial timeout for establishing new connections.
 	// Default is 5 seconds.
 	DialTimeout time.Duration
@@ -54,6 +63,13 @@ type Config struct {
 
 	// Allow set standard_conforming_strings=off or client_encoding=other character sets
 	UnsafeStrings bool
+
+	// Types decode column values by type OID.
+	// Default is the registry returned by NewTypeRegistry.
+	Types *TypeRegistry
+	// Use the binary format for the columns and arguments of prepared statements
+	// when their types support it, see BinaryType.
+	BinaryFormat bool
 }
 
 func newDefaultConfig() *Config {
@@ -119,6 +135,32 @@ func WithAddr(addr string) Option {
 	}
 	return func(conf *Config) {
 		conf.Addr = addr
+		conf.Addrs = nil
+	}
+}
+
+// WithAddrs configures the addresses of a multi-host setup, see Config.Addrs.
+func WithAddrs(addrs ...string) Option {
+	if len(addrs) == 0 {
+		panic("addrs are empty")
+	}
+	return func(conf *Config) {
+		conf.Addr = addrs[0]
+		conf.Addrs = addrs
+	}
+}
+
+// WithTargetSessionAttrs configures the required session type, see Config.TargetSessionAttrs.
+func WithTargetSessionAttrs(attrs string) Option {
+	return func(conf *Config) {
+		conf.TargetSessionAttrs = attrs
+	}
+}
+
+// WithLoadBalanceHosts enables trying the addresses in random order.
+func WithLoadBalanceHosts(on bool) Option {
+	return func(conf *Config) {
+		conf.LoadBalanceHosts = on
 	}
 }
 
@@ -239,6 +281,44 @@ func WithUnsafeStrings(allow bool) Option {
 	}
 }
 
+// WithTypes configures the registry of the types which decode column values.
+func WithTypes(types *TypeRegistry) Option {
+	return func(conf *Config) {
+		conf.Types = types
+	}
+}
+
+// WithBinaryFormat enables the binary format for the columns and arguments of prepared
+// statements, which is faster to decode for large results with numbers and timestamps.
+// Queries executed without a prepared statement always use the text format.
+func WithBinaryFormat(on bool) Option {
+	return func(conf *Config) {
+		conf.BinaryFormat = on
+	}
+}
+
+// tlsConfig returns the TLS config with the server name of the addr
+// when connecting to one of multiple hosts.
+func (c *Config) tlsConfig(addr string) *tls.Config {
+	if len(c.Addrs) == 0 || c.TLSConfig.ServerName != "" {
+		return c.TLSConfig
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return c.TLSConfig
+	}
+	tlsConfig := c.TLSConfig.Clone()
+	tlsConfig.ServerName = host
+	return tlsConfig
+}
+
+func (c *Config) types() *TypeRegistry {
+	if c.Types != nil {
+		return c.Types
+	}
+	return defaultTypes
+}
+
 func env(key, defValue string) string {
 	if s := os.Getenv(key); s != "" {
 		return s
@@ -257,12 +337,30 @@ func parseDSN(dsn string) ([]Option, error) {
 	q := queryOptions{q: u.Query()}
 	var opts []Option
 
+	// The TLS server name is set for each host when connecting to multiple hosts.
+	serverName := u.Host
+	if host, _, err := net.SplitHostPort(u.Host); err == nil {
+		serverName = host
+	}
+
 	switch u.Scheme {
 	case "postgres", "postgresql":
-		if u.Host != "" {
+		port := q.string("port")
+
+		if strings.Contains(u.Host, ",") {
+			addrs, err := splitHosts(u.Host, port)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithAddrs(addrs...))
+			serverName = ""
+		} else if u.Host != "" {
 			addr := u.Host
 			if !strings.Contains(addr, ":") {
-				addr += ":5432"
+				if port == "" {
+					port = "5432"
+				}
+				addr += ":" + port
 			}
 			opts = append(opts, WithAddr(addr))
 		}
@@ -271,12 +369,31 @@ func parseDSN(dsn string) ([]Option, error) {
 			opts = append(opts, WithDatabase(u.Path[1:]))
 		}
 
-		if host := q.string("host"); host != "" {
+		if host := q.string("host"); strings.Contains(host, ",") {
+			addrs, err := splitHosts(host, port)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithAddrs(addrs...))
+			serverName = ""
+		} else if host != "" {
 			opts = append(opts, WithAddr(host))
 			if host[0] == '/' {
 				opts = append(opts, WithNetwork("unix"))
 			}
 		}
+
+		if attrs := q.string("target_session_attrs"); attrs != "" {
+			opts = append(opts, WithTargetSessionAttrs(attrs))
+		}
+
+		switch lb := q.string("load_balance_hosts"); lb {
+		case "", "disable":
+		case "random":
+			opts = append(opts, WithLoadBalanceHosts(true))
+		default:
+			return nil, fmt.Errorf("pgdriver: load_balance_hosts '%s' is not supported", lb)
+		}
 	case "unix":
 		if len(u.Path) == 0 {
 			return nil, fmt.Errorf("unix socket DSN requires a path: %s", dsn)
@@ -312,10 +429,7 @@ func parseDSN(dsn string) ([]Option, error) {
 		case "require":
 			if sslRootCert == "" {
 				tlsConfig.InsecureSkipVerify = true
-				tlsConfig.ServerName = u.Host
-				if host, _, err := net.SplitHostPort(u.Host); err == nil {
-					tlsConfig.ServerName = host
-				}
+				tlsConfig.ServerName = serverName
 				break
 			}
 			// For backwards compatibility reasons, in the presence of `sslrootcert`,
@@ -330,10 +444,7 @@ func parseDSN(dsn string) ([]Option, error) {
 			// (verify chain, but skip server name).
 			// See https://github.com/golang/go/issues/21971 .
 			tlsConfig.InsecureSkipVerify = true
-			tlsConfig.ServerName = u.Host
-			if host, _, err := net.SplitHostPort(u.Host); err == nil {
-				tlsConfig.ServerName = host
-			}
+			tlsConfig.ServerName = serverName
 			tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 				certs := make([]*x509.Certificate, 0, len(rawCerts))
 				for _, rawCert := range rawCerts {
@@ -354,10 +465,7 @@ func parseDSN(dsn string) ([]Option, error) {
 				return err
 			}
 		case "verify-full":
-			tlsConfig.ServerName = u.Host
-			if host, _, err := net.SplitHostPort(u.Host); err == nil {
-				tlsConfig.ServerName = host
-			}
+			tlsConfig.ServerName = serverName
 		default:
 			return nil, fmt.Errorf("pgdriver: sslmode '%s' is not supported", sslMode)
 		}
@@ -407,6 +515,41 @@ func parseDSN(dsn string) ([]Option, error) {
 	return opts, nil
 }
 
+// splitHosts splits the comma-separated hosts, e.g. "a:5432,b", into host:port addresses
+// using the ports from the comma-separated port list or 5432.
+func splitHosts(hosts, ports string) ([]string, error) {
+	hostList := strings.Split(hosts, ",")
+
+	var portList []string
+	if ports != "" {
+		portList = strings.Split(ports, ",")
+		if len(portList) != 1 && len(portList) != len(hostList) {
+			return nil, fmt.Errorf("pgdriver: got %d ports for %d hosts", len(portList), len(hostList))
+		}
+	}
+
+	addrs := make([]string, len(hostList))
+	for i, host := range hostList {
+		if host == "" {
+			return nil, fmt.Errorf("pgdriver: empty host in %q", hosts)
+		}
+		if _, _, err := net.SplitHostPort(host); err == nil {
+			addrs[i] = host
+			continue
+		}
+
+		port := "5432"
+		switch len(portList) {
+		case 1:
+			port = portList[0]
+		case len(hostList):
+			port = portList[i]
+		}
+		addrs[i] = net.JoinHostPort(host, port)
+	}
+	return addrs, nil
+}
+
 // verify is a method to make sure if the config is legitimate
 // in the case it detects any errors, it returns with a non-nil error
 // it can be extended to check other parameters
@@ -414,6 +557,11 @@ func (c *Config) verify() error {
 	if c.User == "" {
 		return errors.New("pgdriver: User option is empty (to configure, use WithUser).")
 	}
+	switch c.TargetSessionAttrs {
+	case "", targetAny, targetReadWrite, targetReadOnly, targetPrimary, targetStandby, targetPreferStandby:
+	default:
+		return fmt.Errorf("pgdriver: target_session_attrs '%s' is not supported", c.TargetSessionAttrs)
+	}
 	return nil
 }
 
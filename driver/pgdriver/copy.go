@@ -207,14 +207,15 @@ func readCopyData(ctx context.Context, cn *Conn, w io.Writer) (res sql.Result, e
 					return nil, err
 				}
 
+				msgLen -= len(b)
+
+				// Keep reading the rest of the data after a write error.
+				if firstErr != nil {
+					continue
+				}
 				if _, err := w.Write(b); err != nil {
-					if firstErr == nil {
-						firstErr = err
-					}
-					break
+					firstErr = err
 				}
-
-				msgLen -= len(b)
 			}
 		case copyDoneMsg:
 			if err := rd.Discard(msgLen); err != nil {
@@ -0,0 +1,676 @@
+package pgdriver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"slices"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// copyChunkSize is the size of the CopyData messages sent by CopyFromModels.
+const copyChunkSize = 64 << 10
+
+// copySignature starts the data in the binary COPY format.
+var copySignature = []byte("PGCOPY\n\377\r\n\000")
+
+// CopyOption configures CopyFromModels, CopyFromIter and CopyToModels.
+type CopyOption func(*copyConfig)
+
+type copyConfig struct {
+	columns []string
+	binary  bool
+}
+
+// WithCopyColumns sets the copied columns. By default, all model columns are copied
+// except the auto-incremented and identity columns when copying to the table.
+func WithCopyColumns(columns ...string) CopyOption {
+	return func(conf *copyConfig) {
+		conf.columns = columns
+	}
+}
+
+// WithCopyBinary enables the binary COPY format, which is faster to parse for numbers
+// and timestamps. All copied columns must have a BinaryType in the type registry,
+// e.g. arrays are not supported.
+func WithCopyBinary(on bool) CopyOption {
+	return func(conf *copyConfig) {
+		conf.binary = on
+	}
+}
+
+// CopyFromModels copies the models to the model table using COPY FROM STDIN.
+// The models are encoded with the field appenders of the bun model, but the model
+// hooks are not called and the zero values are copied as NULLs or as is,
+// i.e. the column defaults are not used.
+//
+//	res, err := pgdriver.CopyFromModels(ctx, conn, users)
+func CopyFromModels[T any](
+	ctx context.Context, conn bun.Conn, models []T, opts ...CopyOption,
+) (sql.Result, error) {
+	return CopyFromIter(ctx, conn, slices.Values(models), opts...)
+}
+
+// CopyFromIter is like CopyFromModels, but streams the models from the iterator,
+// so millions of rows can be copied without loading them in memory.
+func CopyFromIter[T any](
+	ctx context.Context, conn bun.Conn, models iter.Seq[T], opts ...CopyOption,
+) (res sql.Result, err error) {
+	conf := newCopyConfig(opts)
+
+	table, fields, err := copyFields(conn.Dialect(), reflect.TypeFor[T](), conf.columns, true)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := &copyEncoder{
+		gen:    schema.NewQueryGen(conn.Dialect()),
+		fields: fields,
+		binary: conf.binary,
+	}
+
+	if err := conn.Raw(func(driverConn any) error {
+		cn := driverConn.(*Conn)
+
+		if conf.binary {
+			enc.types = cn.conf.types()
+			enc.dataTypes, err = describeColumns(ctx, cn, table, fields)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := writeQuery(ctx, cn, copyQuery(table, fields, "FROM STDIN", conf.binary)); err != nil {
+			return err
+		}
+		if err := readCopyIn(ctx, cn); err != nil {
+			return err
+		}
+
+		encErr, err := writeCopyModels(ctx, cn, enc, models)
+		if err != nil {
+			return err
+		}
+
+		res, err = readQuery(ctx, cn)
+		if encErr != nil {
+			return encErr
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// writeCopyModels sends the models in CopyData messages. If a model can't be encoded,
+// it sends CopyFail and returns the encoding error as encErr.
+func writeCopyModels[T any](
+	ctx context.Context, cn *Conn, enc *copyEncoder, models iter.Seq[T],
+) (encErr, err error) {
+	wb := getWriteBuffer()
+	defer putWriteBuffer(wb)
+
+	wb.StartMessage(copyDataMsg)
+	wb.Bytes = enc.appendHeader(wb.Bytes)
+
+	for model := range models {
+		strct := reflect.Indirect(reflect.ValueOf(&model).Elem())
+		if !strct.IsValid() {
+			encErr = errors.New("pgdriver: can't copy a nil model")
+		} else {
+			wb.Bytes, encErr = enc.appendRow(wb.Bytes, strct)
+		}
+		if encErr != nil {
+			wb.Reset()
+			wb.StartMessage(copyFailMsg)
+			wb.WriteString(encErr.Error())
+			wb.FinishMessage()
+			return encErr, cn.write(ctx, wb)
+		}
+
+		if len(wb.Bytes) >= copyChunkSize {
+			wb.FinishMessage()
+			if err := cn.write(ctx, wb); err != nil {
+				return nil, err
+			}
+			wb.StartMessage(copyDataMsg)
+		}
+	}
+
+	wb.Bytes = enc.appendTrailer(wb.Bytes)
+	wb.FinishMessage()
+
+	wb.StartMessage(copyDoneMsg)
+	wb.FinishMessage()
+
+	return nil, cn.write(ctx, wb)
+}
+
+//------------------------------------------------------------------------------
+
+// CopyToModels copies the model table using COPY TO STDOUT and appends the decoded
+// rows to the slice. The values are decoded the same way as the query results.
+//
+//	var users []User
+//	res, err := pgdriver.CopyToModels(ctx, conn, &users)
+func CopyToModels[T any](
+	ctx context.Context, conn bun.Conn, dest *[]T, opts ...CopyOption,
+) (res sql.Result, err error) {
+	conf := newCopyConfig(opts)
+
+	table, fields, err := copyFields(conn.Dialect(), reflect.TypeFor[T](), conf.columns, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := &copyDecoder{
+		fields: fields,
+		binary: conf.binary,
+		next: func() reflect.Value {
+			var model T
+			*dest = append(*dest, model)
+			v := reflect.ValueOf(&(*dest)[len(*dest)-1]).Elem()
+			if v.Kind() == reflect.Ptr {
+				v.Set(reflect.New(v.Type().Elem()))
+				v = v.Elem()
+			}
+			return v
+		},
+	}
+
+	if err := conn.Raw(func(driverConn any) error {
+		cn := driverConn.(*Conn)
+
+		dec.types = cn.conf.types()
+		dec.dataTypes, err = describeColumns(ctx, cn, table, fields)
+		if err != nil {
+			return err
+		}
+
+		if err := writeQuery(ctx, cn, copyQuery(table, fields, "TO STDOUT", conf.binary)); err != nil {
+			return err
+		}
+		if err := readCopyOut(ctx, cn); err != nil {
+			return err
+		}
+
+		res, err = readCopyData(ctx, cn, dec)
+		if err != nil {
+			return err
+		}
+		return dec.finish()
+	}); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+//------------------------------------------------------------------------------
+
+func newCopyConfig(opts []CopyOption) *copyConfig {
+	conf := new(copyConfig)
+	for _, opt := range opts {
+		opt(conf)
+	}
+	return conf
+}
+
+func copyFields(
+	dialect schema.Dialect, typ reflect.Type, columns []string, from bool,
+) (*schema.Table, []*schema.Field, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("pgdriver: copy model must be a struct, got %s", typ)
+	}
+
+	table := dialect.Tables().Get(typ)
+
+	if len(columns) == 0 {
+		fields := make([]*schema.Field, 0, len(table.Fields))
+		for _, f := range table.Fields {
+			if from && (f.AutoIncrement || f.Identity) {
+				continue
+			}
+			fields = append(fields, f)
+		}
+		return table, fields, nil
+	}
+
+	fields := make([]*schema.Field, len(columns))
+	for i, column := range columns {
+		f, ok := table.FieldMap[column]
+		if !ok {
+			return nil, nil, fmt.Errorf("pgdriver: %s does not have column=%q", table, column)
+		}
+		fields[i] = f
+	}
+	return table, fields, nil
+}
+
+func copyQuery(table *schema.Table, fields []*schema.Field, direction string, binary bool) string {
+	b := append([]byte("COPY "), table.SQLName...)
+	b = appendColumns(b, fields)
+	b = append(b, ' ')
+	b = append(b, direction...)
+	if binary {
+		b = append(b, " WITH (FORMAT binary)"...)
+	}
+	return string(b)
+}
+
+func appendColumns(b []byte, fields []*schema.Field) []byte {
+	b = append(b, " ("...)
+	for i, f := range fields {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, f.SQLName...)
+	}
+	return append(b, ')')
+}
+
+// describeColumns returns the data types of the copied columns.
+func describeColumns(
+	ctx context.Context, cn *Conn, table *schema.Table, fields []*schema.Field,
+) ([]int32, error) {
+	b := []byte("SELECT")
+	for i, f := range fields {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, ' ')
+		b = append(b, f.SQLName...)
+	}
+	b = append(b, " FROM "...)
+	b = append(b, table.SQLName...)
+
+	if err := writeParseDescribeSync(ctx, cn, "", string(b)); err != nil {
+		return nil, err
+	}
+	rowDesc, _, err := readParseDescribeSync(ctx, cn)
+	if err != nil {
+		return nil, err
+	}
+	defer rowDescPool.Put(rowDesc)
+
+	return slices.Clone(rowDesc.types), nil
+}
+
+//------------------------------------------------------------------------------
+
+type copyEncoder struct {
+	gen    schema.QueryGen
+	fields []*schema.Field
+	binary bool
+
+	// types and dataTypes are only set for the binary format.
+	types     *TypeRegistry
+	dataTypes []int32
+
+	buf []byte
+}
+
+func (e *copyEncoder) appendHeader(b []byte) []byte {
+	if !e.binary {
+		return b
+	}
+	b = append(b, copySignature...)
+	b = binary.BigEndian.AppendUint32(b, 0)    // flags
+	return binary.BigEndian.AppendUint32(b, 0) // header extension length
+}
+
+func (e *copyEncoder) appendTrailer(b []byte) []byte {
+	if !e.binary {
+		return b
+	}
+	return binary.BigEndian.AppendUint16(b, 0xffff)
+}
+
+func (e *copyEncoder) appendRow(b []byte, strct reflect.Value) (_ []byte, err error) {
+	if e.binary {
+		b = binary.BigEndian.AppendUint16(b, uint16(len(e.fields)))
+	}
+
+	for i, f := range e.fields {
+		e.buf = f.AppendValue(e.gen, e.buf[:0], strct)
+		text, isNull, err := unquoteLiteral(e.buf)
+		if err != nil {
+			return nil, fmt.Errorf("pgdriver: can't copy %s: %w", f, err)
+		}
+
+		if !e.binary {
+			if i > 0 {
+				b = append(b, '\t')
+			}
+			if isNull {
+				b = append(b, `\N`...)
+			} else {
+				b = appendCopyText(b, text)
+			}
+			continue
+		}
+
+		if isNull {
+			b = binary.BigEndian.AppendUint32(b, 0xffffffff)
+			continue
+		}
+
+		lenPos := len(b)
+		b = append(b, 0, 0, 0, 0)
+		b, err = e.appendBinary(b, i, f.Value(strct), text)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(b[lenPos:], uint32(len(b)-lenPos-4))
+	}
+
+	if !e.binary {
+		b = append(b, '\n')
+	}
+	return b, nil
+}
+
+// appendBinary encodes the field value or, if the type can't encode the value,
+// the text representation of the value, e.g. a JSON or a numeric string.
+func (e *copyEncoder) appendBinary(b []byte, i int, fv reflect.Value, text []byte) ([]byte, error) {
+	f := e.fields[i]
+
+	typ, ok := e.types.Lookup(e.dataTypes[i]).(BinaryType)
+	if !ok {
+		return nil, fmt.Errorf("pgdriver: can't copy %s: type oid=%d does not support the binary format",
+			f, e.dataTypes[i])
+	}
+
+	if v, err := driver.DefaultParameterConverter.ConvertValue(fv.Interface()); err == nil {
+		if b, ok := typ.AppendBinary(b, v); ok {
+			return b, nil
+		}
+	}
+	if b, ok := typ.AppendBinary(b, string(text)); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("pgdriver: can't copy %s in the binary format: %q", f, text)
+}
+
+// unquoteLiteral converts the SQL literal generated by a field appender, e.g. a quoted
+// string with a ::jsonb cast, to the value text. The literal is unquoted in place.
+func unquoteLiteral(b []byte) (_ []byte, isNull bool, _ error) {
+	if string(b) == "NULL" {
+		return nil, true, nil
+	}
+	if len(b) == 0 || b[0] != '\'' {
+		// Numbers and booleans.
+		if bytes.ContainsAny(b, "'() ") {
+			return nil, false, fmt.Errorf("can't copy SQL expression %q", b)
+		}
+		return b, false, nil
+	}
+
+	dst := b[:0]
+	for i := 1; i < len(b); i++ {
+		c := b[i]
+		if c != '\'' {
+			dst = append(dst, c)
+			continue
+		}
+		if i+1 < len(b) && b[i+1] == '\'' {
+			dst = append(dst, '\'')
+			i++
+			continue
+		}
+
+		if rest := b[i+1:]; len(rest) > 0 && !bytes.HasPrefix(rest, []byte("::")) {
+			return nil, false, fmt.Errorf("can't copy SQL expression %q", b)
+		}
+		return dst, false, nil
+	}
+	return nil, false, fmt.Errorf("unterminated SQL literal %q", b)
+}
+
+// appendCopyText escapes the value in the COPY text format.
+func appendCopyText(b, s []byte) []byte {
+	for _, c := range s {
+		switch c {
+		case '\\':
+			b = append(b, `\\`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+// copyDecoder decodes the COPY data and scans the rows into the models returned by next.
+type copyDecoder struct {
+	fields    []*schema.Field
+	binary    bool
+	types     *TypeRegistry
+	dataTypes []int32
+	next      func() reflect.Value
+
+	buf    []byte
+	header bool
+	done   bool
+}
+
+var _ io.Writer = (*copyDecoder)(nil)
+
+func (d *copyDecoder) Write(b []byte) (int, error) {
+	d.buf = append(d.buf, b...)
+
+	var n int
+	var err error
+	if d.binary {
+		n, err = d.decodeBinary(d.buf)
+	} else {
+		n, err = d.decodeText(d.buf)
+	}
+	d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (d *copyDecoder) finish() error {
+	if len(d.buf) > 0 {
+		return fmt.Errorf("pgdriver: COPY data has %d extra bytes", len(d.buf))
+	}
+	return nil
+}
+
+// decodeText decodes the complete rows and returns the number of decoded bytes.
+func (d *copyDecoder) decodeText(b []byte) (int, error) {
+	var n int
+	for {
+		i := bytes.IndexByte(b[n:], '\n')
+		if i == -1 {
+			return n, nil
+		}
+		line := b[n : n+i]
+		n += i + 1
+
+		if err := d.scanTextRow(line); err != nil {
+			return n, err
+		}
+	}
+}
+
+func (d *copyDecoder) scanTextRow(line []byte) error {
+	strct := d.next()
+	for i, f := range d.fields {
+		var value []byte
+		if i == len(d.fields)-1 {
+			value, line = line, nil
+		} else {
+			j := bytes.IndexByte(line, '\t')
+			if j == -1 {
+				return fmt.Errorf("pgdriver: COPY row has %d columns, wanted %d", i+1, len(d.fields))
+			}
+			value, line = line[:j], line[j+1:]
+		}
+
+		var src any
+		if string(value) != `\N` {
+			var err error
+			src, err = decodeColumnValue(d.types, d.dataTypes[i], textFormat, unescapeCopyText(value))
+			if err != nil {
+				return err
+			}
+		}
+		if err := f.ScanValue(strct, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unescapeCopyText unescapes the value in the COPY text format in place.
+func unescapeCopyText(b []byte) []byte {
+	if bytes.IndexByte(b, '\\') == -1 {
+		return b
+	}
+
+	dst := b[:0]
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if c != '\\' || i+1 == len(b) {
+			dst = append(dst, c)
+			continue
+		}
+
+		i++
+		switch c := b[i]; c {
+		case 'b':
+			dst = append(dst, '\b')
+		case 'f':
+			dst = append(dst, '\f')
+		case 'n':
+			dst = append(dst, '\n')
+		case 'r':
+			dst = append(dst, '\r')
+		case 't':
+			dst = append(dst, '\t')
+		case 'v':
+			dst = append(dst, '\v')
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// decodeBinary decodes the complete rows and returns the number of decoded bytes.
+func (d *copyDecoder) decodeBinary(b []byte) (int, error) {
+	var n int
+
+	if !d.header {
+		const headerLen = 19 // signature, flags and header extension length
+		if len(b) < headerLen {
+			return 0, nil
+		}
+		if !bytes.HasPrefix(b, copySignature) {
+			return 0, errors.New("pgdriver: invalid binary COPY signature")
+		}
+		extLen := int(binary.BigEndian.Uint32(b[headerLen-4:]))
+		if len(b) < headerLen+extLen {
+			return 0, nil
+		}
+		n = headerLen + extLen
+		d.header = true
+	}
+
+	for !d.done {
+		rowLen, ok, err := d.binaryRowLen(b[n:])
+		if err != nil || !ok {
+			return n, err
+		}
+		row := b[n : n+rowLen]
+		n += rowLen
+
+		if rowLen == 2 { // trailer
+			d.done = true
+			break
+		}
+		if err := d.scanBinaryRow(row[2:]); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// binaryRowLen returns the length of the row or the trailer and reports whether
+// the row is complete.
+func (d *copyDecoder) binaryRowLen(b []byte) (int, bool, error) {
+	if len(b) < 2 {
+		return 0, false, nil
+	}
+	numCol := int16(binary.BigEndian.Uint16(b))
+	if numCol == -1 {
+		return 2, true, nil
+	}
+	if int(numCol) != len(d.fields) {
+		return 0, false, fmt.Errorf("pgdriver: COPY row has %d columns, wanted %d", numCol, len(d.fields))
+	}
+
+	n := 2
+	for range d.fields {
+		if len(b) < n+4 {
+			return 0, false, nil
+		}
+		colLen := int(int32(binary.BigEndian.Uint32(b[n:])))
+		n += 4
+		if colLen > 0 {
+			n += colLen
+		}
+	}
+	if len(b) < n {
+		return 0, false, nil
+	}
+	return n, true, nil
+}
+
+func (d *copyDecoder) scanBinaryRow(b []byte) error {
+	strct := d.next()
+	for i, f := range d.fields {
+		colLen := int(int32(binary.BigEndian.Uint32(b)))
+		b = b[4:]
+
+		var src any
+		if colLen >= 0 {
+			var err error
+			src, err = decodeColumnValue(d.types, d.dataTypes[i], binaryFormat, b[:colLen])
+			if err != nil {
+				return err
+			}
+			b = b[colLen:]
+		}
+		if err := f.ScanValue(strct, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package pgdriver
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func TestUnquoteLiteral(t *testing.T) {
+	tests := []struct {
+		literal string
+		wanted  string
+		isNull  bool
+		err     bool
+	}{
+		{literal: "NULL", isNull: true},
+		{literal: "123", wanted: "123"},
+		{literal: "TRUE", wanted: "TRUE"},
+		{literal: "''", wanted: ""},
+		{literal: `'it''s'`, wanted: "it's"},
+		{literal: `'\x0102'`, wanted: `\x0102`},
+		{literal: `'{"a":1}'::jsonb`, wanted: `{"a":1}`},
+		{literal: "'a\tb\nc'", wanted: "a\tb\nc"},
+		{literal: "'unterminated", err: true},
+		{literal: "'a' || 'b'", err: true},
+		{literal: "now()", err: true},
+	}
+
+	for _, test := range tests {
+		got, isNull, err := unquoteLiteral([]byte(test.literal))
+		if test.err {
+			require.Error(t, err, test.literal)
+			continue
+		}
+		require.NoError(t, err, test.literal)
+		require.Equal(t, test.isNull, isNull, test.literal)
+		require.Equal(t, test.wanted, string(got), test.literal)
+	}
+}
+
+func TestCopyText(t *testing.T) {
+	for _, s := range []string{"", "hello", "a\tb", "a\nb\r\n", `back\slash`, `\N`} {
+		b := appendCopyText(nil, []byte(s))
+		require.NotContains(t, string(b), "\t")
+		require.NotContains(t, string(b), "\n")
+		require.Equal(t, s, string(unescapeCopyText(b)))
+	}
+	require.Equal(t, "\b\f\v", string(unescapeCopyText([]byte(`\b\f\v`))))
+}
+
+type copyModel struct {
+	ID    int64 `bun:",pk,autoincrement"`
+	Name  string
+	Score *float64
+}
+
+func TestCopyEncoderDecoder(t *testing.T) {
+	dialect := schema.NewNopQueryGen().Dialect()
+	table, fields, err := copyFields(dialect, reflect.TypeFor[*copyModel](), nil, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "score"}, fieldNames(fields))
+
+	_, fields, err = copyFields(dialect, reflect.TypeFor[copyModel](), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "name", "score"}, fieldNames(fields))
+	require.Equal(t,
+		`COPY "copy_models" ("id", "name", "score") TO STDOUT WITH (FORMAT binary)`,
+		copyQuery(table, fields, "TO STDOUT", true))
+
+	_, _, err = copyFields(dialect, reflect.TypeFor[copyModel](), []string{"missing"}, false)
+	require.Error(t, err)
+
+	score := 1.5
+	models := []copyModel{
+		{ID: 1, Name: "it's\ta\\test", Score: &score},
+		{ID: 2, Name: "line\nbreak"},
+	}
+
+	for _, isBinary := range []bool{false, true} {
+		enc := &copyEncoder{
+			gen:       schema.NewQueryGen(dialect),
+			fields:    fields,
+			binary:    isBinary,
+			types:     defaultTypes,
+			dataTypes: []int32{pgInt8, pgText, pgFloat8},
+		}
+
+		b := enc.appendHeader(nil)
+		for i := range models {
+			b, err = enc.appendRow(b, reflect.ValueOf(&models[i]).Elem())
+			require.NoError(t, err)
+		}
+		b = enc.appendTrailer(b)
+
+		if !isBinary {
+			require.Equal(t, "1\tit's\\ta\\\\test\t1.5\n2\tline\\nbreak\t\\N\n", string(b))
+		}
+
+		var got []*copyModel
+		dec := &copyDecoder{
+			fields:    fields,
+			binary:    isBinary,
+			types:     defaultTypes,
+			dataTypes: enc.dataTypes,
+			next: func() reflect.Value {
+				got = append(got, new(copyModel))
+				return reflect.ValueOf(got[len(got)-1]).Elem()
+			},
+		}
+
+		// Split the data to check that incomplete rows are buffered.
+		for len(b) > 0 {
+			n := min(len(b), 7)
+			_, err := dec.Write(b[:n])
+			require.NoError(t, err)
+			b = b[n:]
+		}
+		require.NoError(t, dec.finish())
+
+		require.Len(t, got, 2)
+		require.Equal(t, models[0], *got[0])
+		require.Equal(t, models[1], *got[1])
+	}
+}
+
+func TestCopyDecoderErrors(t *testing.T) {
+	_, fields, err := copyFields(
+		schema.NewNopQueryGen().Dialect(), reflect.TypeFor[copyModel](), []string{"id", "name"}, false)
+	require.NoError(t, err)
+
+	newDecoder := func(isBinary bool) *copyDecoder {
+		return &copyDecoder{
+			fields:    fields,
+			binary:    isBinary,
+			types:     defaultTypes,
+			dataTypes: []int32{pgInt8, pgText},
+			next: func() reflect.Value {
+				return reflect.ValueOf(new(copyModel)).Elem()
+			},
+		}
+	}
+
+	_, err = newDecoder(false).Write([]byte("1\n"))
+	require.Error(t, err)
+
+	_, err = newDecoder(true).Write([]byte("PGCOPY\n\377\r\n\001\000\000\000\000\000\000\000\000"))
+	require.Error(t, err)
+
+	b := append([]byte(nil), copySignature...)
+	b = binary.BigEndian.AppendUint64(b, 0)
+	b = binary.BigEndian.AppendUint16(b, 3)
+	_, err = newDecoder(true).Write(b)
+	require.Error(t, err)
+
+	dec := newDecoder(false)
+	_, err = dec.Write([]byte("1\tincomplete"))
+	require.NoError(t, err)
+	require.Error(t, dec.finish())
+}
+
+func fieldNames(fields []*schema.Field) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
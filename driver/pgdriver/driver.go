@@ -87,7 +87,10 @@ func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
 	if err := c.conf.verify(); err != nil {
 		return nil, err
 	}
-	return newConn(ctx, c.conf)
+	if len(c.conf.Addrs) > 0 {
+		return connectMultiHost(ctx, c.conf)
+	}
+	return newConn(ctx, c.conf, c.conf.Addr)
 }
 
 func (c *Connector) Driver() driver.Driver {
@@ -102,6 +105,7 @@ func (c *Connector) Config() *Config {
 
 type Conn struct {
 	conf *Config
+	addr string
 
 	netConn net.Conn
 	rd      *reader
@@ -114,20 +118,26 @@ type Conn struct {
 	closed int32
 }
 
-func newConn(ctx context.Context, conf *Config) (*Conn, error) {
-	netConn, err := conf.Dialer(ctx, conf.Network, conf.Addr)
+func newConn(ctx context.Context, conf *Config, addr string) (_ *Conn, err error) {
+	netConn, err := conf.Dialer(ctx, conf.Network, addr)
 	if err != nil {
 		return nil, err
 	}
 
 	cn := &Conn{
 		conf:    conf,
+		addr:    addr,
 		netConn: netConn,
 		rd:      newReader(netConn, conf.BufferSize),
 	}
+	defer func() {
+		if err != nil {
+			_ = cn.Close()
+		}
+	}()
 
 	if conf.TLSConfig != nil {
-		if err := enableSSL(ctx, cn, conf.TLSConfig); err != nil {
+		if err := enableSSL(ctx, cn, conf.tlsConfig(addr)); err != nil {
 			return nil, err
 		}
 	}
@@ -211,12 +221,15 @@ func (cn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt,
 		return nil, err
 	}
 
-	rowDesc, err := readParseDescribeSync(ctx, cn)
+	rowDesc, paramTypes, err := readParseDescribeSync(ctx, cn)
 	if err != nil {
 		return nil, err
 	}
+	if rowDesc != nil && cn.conf.BinaryFormat {
+		rowDesc.useBinaryFormat(cn.conf.types())
+	}
 
-	return newStmt(cn, name, rowDesc), nil
+	return newStmt(cn, name, rowDesc, paramTypes), nil
 }
 
 func (cn *Conn) Begin() (driver.Tx, error) {
@@ -267,8 +280,9 @@ func (cn *Conn) ExecContext(
 	}
 	cn.trace(ctx)
 
-	res, err := cn.exec(ctx, query, args)
-	if err != nil {
+	queryCtx, c := cn.watchCancel(ctx)
+	res, err := cn.exec(queryCtx, query, args)
+	if err = c.finish(err); err != nil {
 		return nil, cn.checkBadConn(err)
 	}
 	return res, nil
@@ -297,16 +311,18 @@ func (cn *Conn) QueryContext(
 	}
 	cn.trace(ctx)
 
-	rows, err := cn.query(ctx, query, args)
+	queryCtx, c := cn.watchCancel(ctx)
+	rows, err := cn.query(queryCtx, query, args)
 	if err != nil {
-		return nil, cn.checkBadConn(err)
+		return nil, cn.checkBadConn(c.finish(err))
 	}
+	rows.watchCancel(c)
 	return rows, nil
 }
 
 func (cn *Conn) query(
 	ctx context.Context, query string, args []driver.NamedValue,
-) (driver.Rows, error) {
+) (*rows, error) {
 	query, err := formatQuery(query, args)
 	if err != nil {
 		return nil, err
@@ -393,7 +409,7 @@ func (cn *Conn) trace(ctx context.Context) {
 		span.SetAttributes(
 			semconv.DBUserKey.String(cn.conf.User),
 			semconv.DBNameKey.String(cn.conf.Database),
-			semconv.ServerAddressKey.String(cn.conf.Addr),
+			semconv.ServerAddressKey.String(cn.addr),
 		)
 	}
 }
@@ -402,6 +418,7 @@ func (cn *Conn) trace(ctx context.Context) {
 
 type rows struct {
 	cn       *Conn
+	canceler *canceler
 	rowDesc  *rowDescription
 	reusable bool
 	closed   bool
@@ -436,7 +453,11 @@ func (r *rows) Close() error {
 			// keep going
 		case io.EOF:
 			return nil
+		case context.Canceled, context.DeadlineExceeded:
+			// The query was canceled and the conn can be reused.
+			return nil
 		default: // unexpected error
+			_ = r.finishCancel(nil)
 			_ = r.cn.Close()
 			return err
 		}
@@ -460,6 +481,10 @@ func (r *rows) Next(dest []driver.Value) error {
 	}
 
 	eof, err := r.next(dest)
+	if r.closed {
+		// The results were read up to ReadyForQuery.
+		err = r.finishCancel(err)
+	}
 	if err == io.EOF {
 		return io.ErrUnexpectedEOF
 	} else if err != nil {
@@ -471,6 +496,25 @@ func (r *rows) Next(dest []driver.Value) error {
 	return nil
 }
 
+// watchCancel keeps watching the query context while the rows are read,
+// see Conn.watchCancel.
+func (r *rows) watchCancel(c *canceler) {
+	if r.closed {
+		_ = c.finish(nil)
+		return
+	}
+	r.canceler = c
+}
+
+func (r *rows) finishCancel(err error) error {
+	if r.canceler == nil {
+		return err
+	}
+	err = r.canceler.finish(err)
+	r.canceler = nil
+	return err
+}
+
 func (r *rows) next(dest []driver.Value) (eof bool, _ error) {
 	rd := r.cn.reader(context.TODO(), -1)
 	var firstErr error
@@ -533,7 +577,8 @@ func (r *rows) readDataRow(rd *reader, dest []driver.Value) error {
 			return err
 		}
 
-		value, err := readColumnValue(rd, r.rowDesc.types[colIdx], int(dataLen))
+		value, err := readColumnValue(
+			rd, r.cn.conf.types(), r.rowDesc.types[colIdx], r.rowDesc.formats[colIdx], int(dataLen))
 		if err != nil {
 			return err
 		}
@@ -584,9 +629,10 @@ func (tx tx) Rollback() error {
 //------------------------------------------------------------------------------
 
 type stmt struct {
-	cn      *Conn
-	name    string
-	rowDesc *rowDescription
+	cn         *Conn
+	name       string
+	rowDesc    *rowDescription
+	paramTypes []int32
 }
 
 var (
@@ -595,11 +641,12 @@ var (
 	_ driver.StmtQueryContext = (*stmt)(nil)
 )
 
-func newStmt(cn *Conn, name string, rowDesc *rowDescription) *stmt {
+func newStmt(cn *Conn, name string, rowDesc *rowDescription, paramTypes []int32) *stmt {
 	return &stmt{
-		cn:      cn,
-		name:    name,
-		rowDesc: rowDesc,
+		cn:         cn,
+		name:       name,
+		rowDesc:    rowDesc,
+		paramTypes: paramTypes,
 	}
 }
 
@@ -631,10 +678,15 @@ func (stmt *stmt) Exec(args []driver.Value) (driver.Result, error) {
 }
 
 func (stmt *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	if err := writeBindExecute(ctx, stmt.cn, stmt.name, args); err != nil {
+	queryCtx, c := stmt.cn.watchCancel(ctx)
+	if err := writeBindExecute(queryCtx, stmt.cn, stmt, args); err != nil {
+		return nil, c.finish(err)
+	}
+	res, err := readExtQuery(queryCtx, stmt.cn)
+	if err = c.finish(err); err != nil {
 		return nil, err
 	}
-	return readExtQuery(ctx, stmt.cn)
+	return res, nil
 }
 
 func (stmt *stmt) Query(args []driver.Value) (driver.Rows, error) {
@@ -642,8 +694,14 @@ func (stmt *stmt) Query(args []driver.Value) (driver.Rows, error) {
 }
 
 func (stmt *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	if err := writeBindExecute(ctx, stmt.cn, stmt.name, args); err != nil {
-		return nil, err
+	queryCtx, c := stmt.cn.watchCancel(ctx)
+	if err := writeBindExecute(queryCtx, stmt.cn, stmt, args); err != nil {
+		return nil, c.finish(err)
 	}
-	return readExtQueryData(ctx, stmt.cn, stmt.rowDesc)
+	rows, err := readExtQueryData(queryCtx, stmt.cn, stmt.rowDesc)
+	if err != nil {
+		return nil, c.finish(err)
+	}
+	rows.watchCancel(c)
+	return rows, nil
 }
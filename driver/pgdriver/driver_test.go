@@ -144,6 +144,47 @@ func TestStmtNoParams(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStmtBinaryFormat(t *testing.T) {
+	db := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithDSN(dsn()),
+		pgdriver.WithBinaryFormat(true),
+	))
+	defer db.Close()
+
+	stmt, err := db.Prepare(`SELECT $1::int8, $2::float8, $3::bool, $4::timestamptz, $5::numeric,
+		$6::uuid, $7::bytea, $8::date, ARRAY[1, NULL, 3]::int4[], ARRAY['a', 'b"c']::text[]`)
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	tm := time.Date(2021, 2, 3, 4, 5, 6, 789000000, time.UTC)
+	var (
+		n       int64
+		f       float64
+		b       bool
+		ts      time.Time
+		numeric string
+		uuid    string
+		bytes   []byte
+		date    string
+		ints    string
+		texts   string
+	)
+	err = stmt.QueryRow(int64(-123), 1.5, true, tm, "-12345.6789",
+		"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", []byte{1, 2, 3}, tm).
+		Scan(&n, &f, &b, &ts, &numeric, &uuid, &bytes, &date, &ints, &texts)
+	require.NoError(t, err)
+	require.Equal(t, int64(-123), n)
+	require.Equal(t, 1.5, f)
+	require.True(t, b)
+	require.True(t, tm.Equal(ts))
+	require.Equal(t, "-12345.6789", numeric)
+	require.Equal(t, "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", uuid)
+	require.Equal(t, []byte{1, 2, 3}, bytes)
+	require.Equal(t, "2021-02-03", date)
+	require.Equal(t, "{1,NULL,3}", ints)
+	require.Equal(t, `{"a","b\"c"}`, texts)
+}
+
 func TestStmtConcurrency(t *testing.T) {
 	db := sqlDB()
 	defer db.Close()
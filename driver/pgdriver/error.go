@@ -1,6 +1,7 @@
 package pgdriver
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -51,6 +52,9 @@ func isBadConn(err error, allowTimeout bool) bool {
 		return false
 	case driver.ErrBadConn:
 		return true
+	case context.Canceled, context.DeadlineExceeded:
+		// The query was canceled with CancelRequest, see Conn.watchCancel.
+		return false
 	}
 
 	if err, ok := err.(Error); ok {
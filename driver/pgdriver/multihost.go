@@ -0,0 +1,124 @@
+package pgdriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+)
+
+const (
+	targetAny           = "any"
+	targetReadWrite     = "read-write"
+	targetReadOnly      = "read-only"
+	targetPrimary       = "primary"
+	targetStandby       = "standby"
+	targetPreferStandby = "prefer-standby"
+)
+
+// connectMultiHost tries the addresses in order and returns the first connection
+// which matches the target session attributes like libpq does.
+//
+// https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNECT-TARGET-SESSION-ATTRS
+func connectMultiHost(ctx context.Context, conf *Config) (*Conn, error) {
+	addrs := conf.Addrs
+	if conf.LoadBalanceHosts {
+		addrs = slices.Clone(addrs)
+		rand.Shuffle(len(addrs), func(i, j int) {
+			addrs[i], addrs[j] = addrs[j], addrs[i]
+		})
+	}
+
+	// With prefer-standby, the first primary is used if there are no standbys.
+	var fallback *Conn
+	var errs []error
+
+	closeFallback := func() {
+		if fallback != nil {
+			_ = fallback.Close()
+		}
+	}
+
+	for _, addr := range addrs {
+		cn, err := newConn(ctx, conf, addr)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				closeFallback()
+				return nil, ctxErr
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+
+		ok, err := cn.matchSessionAttrs(ctx, conf.TargetSessionAttrs)
+		if err != nil {
+			_ = cn.Close()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				closeFallback()
+				return nil, ctxErr
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+		if ok {
+			closeFallback()
+			return cn, nil
+		}
+
+		if conf.TargetSessionAttrs == targetPreferStandby && fallback == nil {
+			fallback = cn
+			continue
+		}
+		_ = cn.Close()
+		errs = append(errs, fmt.Errorf("%s: server is not %s", addr, conf.TargetSessionAttrs))
+	}
+
+	if fallback != nil {
+		return fallback, nil
+	}
+	return nil, fmt.Errorf("pgdriver: failed to connect to any host: %w", errors.Join(errs...))
+}
+
+// matchSessionAttrs reports whether the server matches the target session attributes.
+// With prefer-standby, it reports whether the server is a standby.
+func (cn *Conn) matchSessionAttrs(ctx context.Context, attrs string) (bool, error) {
+	switch attrs {
+	case "", targetAny:
+		return true, nil
+	case targetReadWrite, targetReadOnly:
+		readOnly, err := cn.queryValue(ctx, "SHOW transaction_read_only")
+		if err != nil {
+			return false, err
+		}
+		return (readOnly == "on") == (attrs == targetReadOnly), nil
+	case targetPrimary, targetStandby, targetPreferStandby:
+		inRecovery, err := cn.queryValue(ctx, "SELECT pg_is_in_recovery()")
+		if err != nil {
+			return false, err
+		}
+		return (inRecovery == true) == (attrs != targetPrimary), nil
+	default:
+		return false, fmt.Errorf("pgdriver: target_session_attrs '%s' is not supported", attrs)
+	}
+}
+
+// queryValue returns the value of the first column of the first row.
+func (cn *Conn) queryValue(ctx context.Context, query string) (driver.Value, error) {
+	rows, err := cn.QueryContext(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dest := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(dest); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("pgdriver: %q returned no rows", query)
+		}
+		return nil, err
+	}
+	return dest[0], nil
+}
@@ -0,0 +1,149 @@
+package pgdriver_test
+
+import (
+	"database/sql"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestMultiHost(t *testing.T) {
+	deadLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dead := deadLn.Addr().String()
+	require.NoError(t, deadLn.Close())
+
+	primary1 := newSessionServer(t, 1, false)
+	primary2 := newSessionServer(t, 2, false)
+	standby3 := newSessionServer(t, 3, true)
+	standby4 := newSessionServer(t, 4, true)
+
+	connect := func(attrs string, addrs ...string) (int, error) {
+		db := sql.OpenDB(pgdriver.NewConnector(
+			pgdriver.WithAddrs(addrs...),
+			pgdriver.WithTargetSessionAttrs(attrs),
+			pgdriver.WithInsecure(true),
+			pgdriver.WithUser("test"),
+		))
+		defer db.Close()
+
+		var id int
+		err := db.QueryRow("SELECT id").Scan(&id)
+		return id, err
+	}
+
+	tests := []struct {
+		attrs  string
+		addrs  []string
+		wanted int
+	}{
+		{attrs: "", addrs: []string{dead, standby3, primary1}, wanted: 3},
+		{attrs: "any", addrs: []string{primary2, primary1}, wanted: 2},
+		{attrs: "read-write", addrs: []string{dead, standby3, primary1}, wanted: 1},
+		{attrs: "primary", addrs: []string{standby3, standby4, primary2}, wanted: 2},
+		{attrs: "read-only", addrs: []string{primary1, standby4, standby3}, wanted: 4},
+		{attrs: "standby", addrs: []string{primary1, dead, standby3}, wanted: 3},
+		{attrs: "prefer-standby", addrs: []string{primary1, standby4}, wanted: 4},
+		{attrs: "prefer-standby", addrs: []string{dead, primary2, primary1}, wanted: 2},
+	}
+
+	for _, test := range tests {
+		id, err := connect(test.attrs, test.addrs...)
+		require.NoError(t, err, "attrs=%q", test.attrs)
+		require.Equal(t, test.wanted, id, "attrs=%q", test.attrs)
+	}
+
+	_, err = connect("standby", dead, primary1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "server is not standby")
+	require.Contains(t, err.Error(), dead)
+
+	_, err = connect("primary-only", primary1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "target_session_attrs 'primary-only' is not supported")
+
+	t.Run("load_balance_hosts", func(t *testing.T) {
+		db := sql.OpenDB(pgdriver.NewConnector(
+			pgdriver.WithAddrs(primary1, primary2),
+			pgdriver.WithLoadBalanceHosts(true),
+			pgdriver.WithInsecure(true),
+			pgdriver.WithUser("test"),
+		))
+		defer db.Close()
+		db.SetMaxIdleConns(0)
+
+		seen := make(map[int]bool)
+		for i := 0; i < 100 && len(seen) < 2; i++ {
+			var id int
+			require.NoError(t, db.QueryRow("SELECT id").Scan(&id))
+			seen[id] = true
+		}
+		require.Equal(t, map[int]bool{1: true, 2: true}, seen)
+	})
+}
+
+// newSessionServer starts a PostgreSQL stand-in which reports whether it is a standby
+// and responds with its id to other queries.
+func newSessionServer(t *testing.T, id int, standby bool) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	serveConn := func(conn net.Conn) {
+		defer conn.Close()
+
+		if _, err := readStartupMessage(conn); err != nil {
+			return
+		}
+		writeMessage(conn, 'R', []byte{0, 0, 0, 0})
+		writeMessage(conn, 'Z', []byte("I"))
+
+		for {
+			c, b, err := readMessage(conn)
+			if err != nil || c != 'Q' {
+				return
+			}
+
+			switch query := strings.TrimSuffix(string(b), "\x00"); query {
+			case "SHOW transaction_read_only":
+				value := "off"
+				if standby {
+					value = "on"
+				}
+				writeMessage(conn, 'T', typedRowDescription("transaction_read_only", 25))
+				writeMessage(conn, 'D', dataRow(value))
+				writeMessage(conn, 'C', []byte("SHOW\x00"))
+			case "SELECT pg_is_in_recovery()":
+				value := "f"
+				if standby {
+					value = "t"
+				}
+				writeMessage(conn, 'T', typedRowDescription("pg_is_in_recovery", 16))
+				writeMessage(conn, 'D', dataRow(value))
+				writeMessage(conn, 'C', []byte("SELECT 1\x00"))
+			default:
+				writeMessage(conn, 'T', rowDescription("id"))
+				writeMessage(conn, 'D', dataRow(strconv.Itoa(id)))
+				writeMessage(conn, 'C', []byte("SELECT 1\x00"))
+			}
+			writeMessage(conn, 'Z', []byte("I"))
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveConn(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
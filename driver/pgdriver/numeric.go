@@ -0,0 +1,177 @@
+package pgdriver
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The binary format of numeric is a sequence of base-10000 digits:
+//
+//	ndigits int16, weight int16, sign uint16, dscale int16, digits [ndigits]int16
+//
+// where weight is the exponent of the first digit and dscale is the number of decimal digits
+// after the point.
+const (
+	numericPos  = 0x0000
+	numericNeg  = 0x4000
+	numericNaN  = 0xC000
+	numericPInf = 0xD000
+	numericNInf = 0xF000
+)
+
+// numericType decodes numeric values as []byte in the text representation,
+// so they can be scanned into strings and decimal types without losing precision.
+type numericType struct {
+	rawType
+}
+
+func (numericType) DecodeBinary(b []byte) (any, error) {
+	if len(b) < 8 {
+		return nil, fmt.Errorf("pgdriver: invalid numeric length: %d", len(b))
+	}
+	ndigits := int(binary.BigEndian.Uint16(b))
+	weight := int(int16(binary.BigEndian.Uint16(b[2:])))
+	sign := binary.BigEndian.Uint16(b[4:])
+	dscale := int(binary.BigEndian.Uint16(b[6:]))
+	if len(b) != 8+2*ndigits {
+		return nil, fmt.Errorf("pgdriver: invalid numeric length: %d", len(b))
+	}
+	digit := func(i int) int {
+		if i < 0 || i >= ndigits {
+			return 0
+		}
+		return int(binary.BigEndian.Uint16(b[8+2*i:]))
+	}
+
+	switch sign {
+	case numericNaN:
+		return []byte("NaN"), nil
+	case numericPInf:
+		return []byte("Infinity"), nil
+	case numericNInf:
+		return []byte("-Infinity"), nil
+	}
+
+	dst := make([]byte, 0, 4*(max(weight, 0)+1)+dscale+2)
+	if sign == numericNeg {
+		dst = append(dst, '-')
+	}
+
+	if weight < 0 {
+		dst = append(dst, '0')
+	} else {
+		dst = strconv.AppendInt(dst, int64(digit(0)), 10)
+		for i := 1; i <= weight; i++ {
+			dst = appendNumericDigit(dst, digit(i))
+		}
+	}
+
+	if dscale > 0 {
+		dst = append(dst, '.')
+		end := len(dst) + dscale
+		for i := weight + 1; len(dst) < end; i++ {
+			dst = appendNumericDigit(dst, digit(i))
+		}
+		dst = dst[:end]
+	}
+
+	return dst, nil
+}
+
+func appendNumericDigit(b []byte, digit int) []byte {
+	return append(b,
+		byte('0'+digit/1000),
+		byte('0'+digit/100%10),
+		byte('0'+digit/10%10),
+		byte('0'+digit%10))
+}
+
+func (numericType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	var s string
+	switch v := v.(type) {
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return appendNumericHeader(b, 0, 0, numericNaN, 0), true
+		case math.IsInf(v, 1):
+			return appendNumericHeader(b, 0, 0, numericPInf, 0), true
+		case math.IsInf(v, -1):
+			return appendNumericHeader(b, 0, 0, numericNInf, 0), true
+		}
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		s = v
+	default:
+		return b, false
+	}
+	return appendNumeric(b, s)
+}
+
+// appendNumeric encodes the decimal number, e.g. -123.45, in the binary format.
+func appendNumeric(b []byte, s string) ([]byte, bool) {
+	sign := uint16(numericPos)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = numericNeg
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return b, false
+	}
+	for _, part := range [...]string{intPart, fracPart} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return b, false
+			}
+		}
+	}
+	dscale := len(fracPart)
+
+	// Align the digits to the groups of 4 around the decimal point.
+	intPart = strings.Repeat("0", (4-len(intPart)%4)%4) + intPart
+	fracPart += strings.Repeat("0", (4-len(fracPart)%4)%4)
+	digits := make([]uint16, 0, (len(intPart)+len(fracPart))/4)
+	for _, part := range [...]string{intPart, fracPart} {
+		for i := 0; i < len(part); i += 4 {
+			n, _ := strconv.Atoi(part[i : i+4])
+			digits = append(digits, uint16(n))
+		}
+	}
+	weight := len(intPart)/4 - 1
+
+	for len(digits) > 0 && digits[0] == 0 {
+		digits = digits[1:]
+		weight--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == 0 {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		weight = 0
+		sign = numericPos
+	}
+
+	b = appendNumericHeader(b, len(digits), weight, sign, dscale)
+	for _, d := range digits {
+		b = binary.BigEndian.AppendUint16(b, d)
+	}
+	return b, true
+}
+
+func appendNumericHeader(b []byte, ndigits, weight int, sign uint16, dscale int) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(ndigits))
+	b = binary.BigEndian.AppendUint16(b, uint16(int16(weight)))
+	b = binary.BigEndian.AppendUint16(b, sign)
+	b = binary.BigEndian.AppendUint16(b, uint16(dscale))
+	return b
+}
@@ -76,6 +76,7 @@ const (
 	copyOutResponseMsg = 'H'
 	copyDataMsg        = 'd'
 	copyDoneMsg        = 'c'
+	copyFailMsg        = 'f'
 )
 
 var errEmptyQuery = errors.New("pgdriver: query is empty")
@@ -145,6 +146,19 @@ func writeSSLMsg(ctx context.Context, cn *Conn) error {
 	return cn.write(ctx, wb)
 }
 
+func writeCancelRequest(ctx context.Context, cn *Conn, processID, secretKey int32) error {
+	wb := getWriteBuffer()
+	defer putWriteBuffer(wb)
+
+	wb.StartMessage(0)
+	wb.WriteInt32(80877102)
+	wb.WriteInt32(processID)
+	wb.WriteInt32(secretKey)
+	wb.FinishMessage()
+
+	return cn.write(ctx, wb)
+}
+
 //------------------------------------------------------------------------------
 
 func startup(ctx context.Context, cn *Conn) error {
@@ -605,6 +619,7 @@ type rowDescription struct {
 	buf      []byte
 	names    []string
 	types    []int32
+	formats  []int16
 	numInput int16
 }
 
@@ -616,6 +631,7 @@ func newRowDescription(numCol int) *rowDescription {
 		buf:      make([]byte, 0, 16*numCol),
 		names:    make([]string, 0, numCol),
 		types:    make([]int32, 0, numCol),
+		formats:  make([]int16, 0, numCol),
 		numInput: -1,
 	}
 }
@@ -624,6 +640,7 @@ func (d *rowDescription) reset(numCol int) {
 	d.buf = make([]byte, 0, 16*numCol)
 	d.names = d.names[:0]
 	d.types = d.types[:0]
+	d.formats = d.formats[:0]
 	d.numInput = -1
 }
 
@@ -637,8 +654,18 @@ func (d *rowDescription) addName(name []byte) {
 	d.names = append(d.names, internal.String(d.buf[i:]))
 }
 
-func (d *rowDescription) addType(dataType int32) {
+func (d *rowDescription) addType(dataType int32, format int16) {
 	d.types = append(d.types, dataType)
+	d.formats = append(d.formats, format)
+}
+
+// useBinaryFormat requests the columns in the binary format when their types support it.
+func (d *rowDescription) useBinaryFormat(types *TypeRegistry) {
+	for i, oid := range d.types {
+		if _, ok := types.Lookup(oid).(BinaryType); ok {
+			d.formats[i] = binaryFormat
+		}
+	}
 }
 
 func readRowDescription(rd *reader) (*rowDescription, error) {
@@ -669,11 +696,17 @@ func readRowDescription(rd *reader) (*rowDescription, error) {
 		if err != nil {
 			return nil, err
 		}
-		rowDesc.addType(dataType)
 
-		if _, err := rd.ReadTemp(8); err != nil {
+		// Skip the type size and modifier.
+		if _, err := rd.ReadTemp(6); err != nil {
 			return nil, err
 		}
+
+		format, err := readInt16(rd)
+		if err != nil {
+			return nil, err
+		}
+		rowDesc.addType(dataType, format)
 	}
 
 	return rowDesc, nil
@@ -741,80 +774,109 @@ func writeParseDescribeSync(ctx context.Context, cn *Conn, name, query string) e
 	return cn.write(ctx, wb)
 }
 
-func readParseDescribeSync(ctx context.Context, cn *Conn) (*rowDescription, error) {
+func readParseDescribeSync(ctx context.Context, cn *Conn) (*rowDescription, []int32, error) {
 	rd := cn.reader(ctx, -1)
 	var numParam int16
+	var paramTypes []int32
 	var rowDesc *rowDescription
 	var firstErr error
 	for {
 		c, msgLen, err := readMessageType(rd)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		switch c {
 		case parseCompleteMsg:
 			if err := rd.Discard(msgLen); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		case rowDescriptionMsg: // response to DESCRIBE message.
 			rowDesc, err = readRowDescription(rd)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			rowDesc.numInput = numParam
 		case parameterDescriptionMsg: // response to DESCRIBE message.
 			numParam, err = readInt16(rd)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			for i := 0; i < int(numParam); i++ {
-				if _, err := readInt32(rd); err != nil {
-					return nil, err
+			paramTypes = make([]int32, numParam)
+			for i := range paramTypes {
+				if paramTypes[i], err = readInt32(rd); err != nil {
+					return nil, nil, err
 				}
 			}
 		case noDataMsg: // response to DESCRIBE message.
 			if err := rd.Discard(msgLen); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		case readyForQueryMsg:
 			if err := rd.Discard(msgLen); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if firstErr != nil {
-				return nil, firstErr
+				return nil, nil, firstErr
 			}
-			return rowDesc, err
+			return rowDesc, paramTypes, err
 		case errorResponseMsg:
 			e, err := readError(rd)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if firstErr == nil {
 				firstErr = e
 			}
 		case noticeResponseMsg, parameterStatusMsg:
 			if err := rd.Discard(msgLen); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		default:
-			return nil, fmt.Errorf("pgdriver: readParseDescribeSync: unexpected message %q", c)
+			return nil, nil, fmt.Errorf("pgdriver: readParseDescribeSync: unexpected message %q", c)
 		}
 	}
 }
 
-func writeBindExecute(ctx context.Context, cn *Conn, name string, args []driver.NamedValue) error {
+func writeBindExecute(
+	ctx context.Context, cn *Conn, stmt *stmt, args []driver.NamedValue,
+) error {
 	wb := getWriteBuffer()
 	defer putWriteBuffer(wb)
 
 	wb.StartMessage(bindMsg)
 	wb.WriteString("")
-	wb.WriteString(name)
-	wb.WriteInt16(0)
+	wb.WriteString(stmt.name)
+
+	// The binary format is only used for the args of the known types,
+	// so the format code of each arg is set after the arg is encoded.
+	binaryArgs := cn.conf.BinaryFormat && len(stmt.paramTypes) == len(args)
+	formatsPos := len(wb.Bytes)
+	if binaryArgs {
+		wb.WriteInt16(int16(len(args)))
+		for range args {
+			wb.WriteInt16(textFormat)
+		}
+	} else {
+		wb.WriteInt16(0)
+	}
+
 	wb.WriteInt16(int16(len(args)))
 	for i := range args {
 		wb.StartParam()
+
+		if binaryArgs && args[i].Value != nil {
+			if typ, ok := cn.conf.types().Lookup(stmt.paramTypes[i]).(BinaryType); ok {
+				if bytes, ok := typ.AppendBinary(wb.Bytes, args[i].Value); ok {
+					wb.Bytes = bytes
+					wb.FinishParam()
+					binary.BigEndian.PutUint16(wb.Bytes[formatsPos+2+2*i:], binaryFormat)
+					continue
+				}
+			}
+		}
+
 		bytes, err := appendStmtArg(wb.Bytes, args[i].Value)
 		if err != nil {
 			return err
@@ -826,7 +888,15 @@ func writeBindExecute(ctx context.Context, cn *Conn, name string, args []driver.
 			wb.FinishNullParam()
 		}
 	}
-	wb.WriteInt16(0)
+
+	if stmt.rowDesc != nil && cn.conf.BinaryFormat {
+		wb.WriteInt16(int16(len(stmt.rowDesc.formats)))
+		for _, format := range stmt.rowDesc.formats {
+			wb.WriteInt16(format)
+		}
+	} else {
+		wb.WriteInt16(0)
+	}
 	wb.FinishMessage()
 
 	wb.StartMessage(executeMsg)
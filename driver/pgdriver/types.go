@@ -0,0 +1,434 @@
+package pgdriver
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun/internal"
+)
+
+// Type decodes the column values of a PostgreSQL data type, see TypeRegistry.
+type Type interface {
+	// DecodeText decodes a column value in the text format.
+	// b is only valid until the method returns.
+	DecodeText(b []byte) (any, error)
+}
+
+// BinaryType is a Type which also supports the binary format. The binary format is used
+// for the columns and arguments of prepared statements when WithBinaryFormat is enabled.
+type BinaryType interface {
+	Type
+
+	// DecodeBinary decodes a column value in the binary format.
+	// b is only valid until the method returns.
+	DecodeBinary(b []byte) (any, error)
+	// AppendBinary appends the argument in the binary format and reports whether
+	// the argument can be encoded. Otherwise, the argument is sent in the text format.
+	AppendBinary(b []byte, v driver.Value) ([]byte, bool)
+}
+
+// columnReader is implemented by the types which decode text values
+// without copying them from the reader buffer.
+type columnReader interface {
+	readColumn(rd *reader, n int) (any, error)
+}
+
+// TypeRegistry maps PostgreSQL type OIDs to the types which decode column values.
+// Values of the types which are not registered are returned as []byte.
+//
+//	types := pgdriver.NewTypeRegistry()
+//	types.Register(citextOID, pgdriver.TextType())
+//	connector := pgdriver.NewConnector(pgdriver.WithTypes(types))
+type TypeRegistry struct {
+	types map[int32]Type
+}
+
+// NewTypeRegistry returns a registry with the built-in types: bool, integers, floats,
+// text, bytea, date, timestamp(tz), numeric, uuid, json(b) and the arrays of these types.
+func NewTypeRegistry() *TypeRegistry {
+	r := &TypeRegistry{types: make(map[int32]Type)}
+
+	r.Register(pgBool, boolType{})
+	r.Register(pgInt2, intType{size: 2})
+	r.Register(pgInt4, intType{size: 4})
+	r.Register(pgInt8, intType{size: 8})
+	r.Register(pgFloat4, floatType{size: 4})
+	r.Register(pgFloat8, floatType{size: 8})
+	r.Register(pgText, textType{})
+	r.Register(pgVarchar, textType{})
+	r.Register(pgBytea, byteaType{})
+	r.Register(pgDate, dateType{})
+	r.Register(pgTimestamp, timestampType{})
+	r.Register(pgTimestamptz, timestampType{})
+	r.Register(pgNumeric, numericType{})
+	r.Register(pgUUID, uuidType{})
+	r.Register(pgJSON, jsonType{})
+	r.Register(pgJSONB, jsonType{jsonb: true})
+
+	for oid, elem := range map[int32]int32{
+		pgBoolArray:        pgBool,
+		pgByteaArray:       pgBytea,
+		pgInt2Array:        pgInt2,
+		pgInt4Array:        pgInt4,
+		pgInt8Array:        pgInt8,
+		pgFloat4Array:      pgFloat4,
+		pgFloat8Array:      pgFloat8,
+		pgTextArray:        pgText,
+		pgVarcharArray:     pgVarchar,
+		pgDateArray:        pgDate,
+		pgTimestampArray:   pgTimestamp,
+		pgTimestamptzArray: pgTimestamptz,
+		pgNumericArray:     pgNumeric,
+		pgUUIDArray:        pgUUID,
+		pgJSONBArray:       pgJSONB,
+	} {
+		r.Register(oid, ArrayType(elem, r.types[elem].(BinaryType)))
+	}
+
+	return r
+}
+
+// Register registers the type with the OID, replacing the type registered before.
+// It must not be called concurrently with the queries which use the registry.
+func (r *TypeRegistry) Register(oid int32, typ Type) {
+	r.types[oid] = typ
+}
+
+// Lookup returns the type with the OID or nil.
+func (r *TypeRegistry) Lookup(oid int32) Type {
+	return r.types[oid]
+}
+
+var defaultTypes = NewTypeRegistry()
+
+//------------------------------------------------------------------------------
+
+// TextType returns the type of text and varchar, which decodes values as strings.
+// It can be registered for other textual types, e.g. citext.
+func TextType() BinaryType {
+	return textType{}
+}
+
+// RawType returns the type which decodes values as []byte in the text representation,
+// like the types which are not registered.
+func RawType() Type {
+	return rawType{}
+}
+
+// ArrayType returns the type of the arrays of elem, which decodes values as []byte
+// in the text representation, e.g. {1,2,3}. Values in the binary format are converted
+// to the text representation.
+func ArrayType(elemOID int32, elem BinaryType) BinaryType {
+	return arrayType{elemOID: elemOID, elem: elem}
+}
+
+type rawType struct{}
+
+func (rawType) readColumn(rd *reader, n int) (any, error) {
+	return readRawCol(rd, n)
+}
+
+func (rawType) DecodeText(b []byte) (any, error) {
+	return append([]byte(nil), b...), nil
+}
+
+type boolType struct{}
+
+func (boolType) DecodeText(b []byte) (any, error) {
+	return len(b) == 1 && (b[0] == 't' || b[0] == '1'), nil
+}
+
+func (boolType) DecodeBinary(b []byte) (any, error) {
+	if len(b) != 1 {
+		return nil, fmt.Errorf("pgdriver: invalid bool length: %d", len(b))
+	}
+	return b[0] != 0, nil
+}
+
+func (boolType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	if v, ok := v.(bool); ok {
+		if v {
+			return append(b, 1), true
+		}
+		return append(b, 0), true
+	}
+	return b, false
+}
+
+type intType struct {
+	size int
+}
+
+func (t intType) DecodeText(b []byte) (any, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(internal.String(b), 10, 8*t.size)
+}
+
+func (t intType) DecodeBinary(b []byte) (any, error) {
+	if len(b) != t.size {
+		return nil, fmt.Errorf("pgdriver: invalid int%d length: %d", t.size, len(b))
+	}
+	switch t.size {
+	case 2:
+		return int64(int16(binary.BigEndian.Uint16(b))), nil
+	case 4:
+		return int64(int32(binary.BigEndian.Uint32(b))), nil
+	default:
+		return int64(binary.BigEndian.Uint64(b)), nil
+	}
+}
+
+func (t intType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	n, ok := v.(int64)
+	if !ok {
+		return b, false
+	}
+	switch t.size {
+	case 2:
+		if n < math.MinInt16 || n > math.MaxInt16 {
+			return b, false
+		}
+		return binary.BigEndian.AppendUint16(b, uint16(n)), true
+	case 4:
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return b, false
+		}
+		return binary.BigEndian.AppendUint32(b, uint32(n)), true
+	default:
+		return binary.BigEndian.AppendUint64(b, uint64(n)), true
+	}
+}
+
+type floatType struct {
+	size int
+}
+
+func (t floatType) DecodeText(b []byte) (any, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseFloat(internal.String(b), 8*t.size)
+}
+
+func (t floatType) DecodeBinary(b []byte) (any, error) {
+	if len(b) != t.size {
+		return nil, fmt.Errorf("pgdriver: invalid float%d length: %d", t.size, len(b))
+	}
+	if t.size == 4 {
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+}
+
+func (t floatType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return b, false
+	}
+	if t.size == 4 {
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(float32(f))), true
+	}
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f)), true
+}
+
+type textType struct{}
+
+func (textType) readColumn(rd *reader, n int) (any, error) {
+	if n <= 0 {
+		return "", nil
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rd, b); err != nil {
+		return nil, err
+	}
+	return internal.String(b), nil
+}
+
+func (textType) DecodeText(b []byte) (any, error) {
+	return string(b), nil
+}
+
+func (textType) DecodeBinary(b []byte) (any, error) {
+	return string(b), nil
+}
+
+func (textType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	// The text format drops NUL characters, which are not allowed in text values.
+	if s, ok := v.(string); ok && strings.IndexByte(s, 0) == -1 {
+		return append(b, s...), true
+	}
+	return b, false
+}
+
+type byteaType struct{}
+
+func (byteaType) DecodeText(b []byte) (any, error) {
+	if len(b) == 0 {
+		return []byte{}, nil
+	}
+	if len(b) < 2 || b[0] != '\\' || b[1] != 'x' {
+		return nil, fmt.Errorf("pgdriver: can't parse bytea: %q", b)
+	}
+	b = b[2:] // Cut off "\x".
+
+	dst := make([]byte, hex.DecodedLen(len(b)))
+	if _, err := hex.Decode(dst, b); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func (byteaType) DecodeBinary(b []byte) (any, error) {
+	return append([]byte{}, b...), nil
+}
+
+func (byteaType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	if v, ok := v.([]byte); ok && v != nil {
+		return append(b, v...), true
+	}
+	return b, false
+}
+
+// pgEpoch is the Unix time of 2000-01-01, which the binary format of dates and timestamps is based on.
+const pgEpoch = 946684800
+
+type dateType struct{}
+
+// DecodeText returns a string and lets the scanner convert the string to time.Time if necessary.
+func (dateType) DecodeText(b []byte) (any, error) {
+	return string(b), nil
+}
+
+func (dateType) DecodeBinary(b []byte) (any, error) {
+	if len(b) != 4 {
+		return nil, fmt.Errorf("pgdriver: invalid date length: %d", len(b))
+	}
+	switch days := int32(binary.BigEndian.Uint32(b)); days {
+	case math.MaxInt32:
+		return "infinity", nil
+	case math.MinInt32:
+		return "-infinity", nil
+	default:
+		return time.Unix(pgEpoch+int64(days)*86400, 0).UTC().Format(dateFormat), nil
+	}
+}
+
+func (dateType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	tm, ok := v.(time.Time)
+	if !ok || tm.IsZero() {
+		return b, false
+	}
+	y, m, d := tm.UTC().Date()
+	days := (time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() - pgEpoch) / 86400
+	return binary.BigEndian.AppendUint32(b, uint32(int32(days))), true
+}
+
+type timestampType struct{}
+
+func (timestampType) DecodeText(b []byte) (any, error) {
+	if len(b) == 0 {
+		return time.Time{}, nil
+	}
+	return ParseTime(internal.String(b))
+}
+
+func (timestampType) DecodeBinary(b []byte) (any, error) {
+	if len(b) != 8 {
+		return nil, fmt.Errorf("pgdriver: invalid timestamp length: %d", len(b))
+	}
+	us := int64(binary.BigEndian.Uint64(b))
+	if us == math.MaxInt64 || us == math.MinInt64 {
+		return nil, errors.New("pgdriver: can't decode infinite timestamp")
+	}
+	return time.UnixMicro(us + pgEpoch*1e6).UTC(), nil
+}
+
+func (timestampType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	// Zero time is sent as NULL in the text format.
+	tm, ok := v.(time.Time)
+	if !ok || tm.IsZero() {
+		return b, false
+	}
+	return binary.BigEndian.AppendUint64(b, uint64(tm.UnixMicro()-pgEpoch*1e6)), true
+}
+
+type uuidType struct {
+	rawType
+}
+
+func (uuidType) DecodeBinary(b []byte) (any, error) {
+	if len(b) != 16 {
+		return nil, fmt.Errorf("pgdriver: invalid uuid length: %d", len(b))
+	}
+	dst := make([]byte, 36)
+	hex.Encode(dst[0:8], b[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], b[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], b[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], b[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:], b[10:])
+	return dst, nil
+}
+
+func (uuidType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	s, ok := v.(string)
+	if !ok || len(s) != 36 {
+		return b, false
+	}
+	s = strings.ReplaceAll(s, "-", "")
+	if len(s) != 32 {
+		return b, false
+	}
+
+	i := len(b)
+	b = append(b, make([]byte, 16)...)
+	if _, err := hex.Decode(b[i:], []byte(s)); err != nil {
+		return b[:i], false
+	}
+	return b, true
+}
+
+type jsonType struct {
+	rawType
+	jsonb bool
+}
+
+func (t jsonType) DecodeBinary(b []byte) (any, error) {
+	if t.jsonb {
+		if len(b) == 0 || b[0] != 1 {
+			return nil, errors.New("pgdriver: unsupported jsonb version")
+		}
+		b = b[1:]
+	}
+	return append([]byte{}, b...), nil
+}
+
+func (t jsonType) AppendBinary(b []byte, v driver.Value) ([]byte, bool) {
+	var data []byte
+	switch v := v.(type) {
+	case string:
+		data = internal.Bytes(v)
+	case []byte:
+		data = v
+	default:
+		return b, false
+	}
+	if t.jsonb {
+		b = append(b, 1)
+	}
+	return append(b, data...), true
+}
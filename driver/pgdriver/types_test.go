@@ -0,0 +1,136 @@
+package pgdriver
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryTypes(t *testing.T) {
+	type Test struct {
+		oid    int32
+		value  driver.Value
+		wanted any
+	}
+
+	tm := time.Date(2021, 2, 3, 4, 5, 6, 789000000, time.UTC)
+
+	tests := []Test{
+		{oid: pgBool, value: true, wanted: true},
+		{oid: pgInt2, value: int64(-123), wanted: int64(-123)},
+		{oid: pgInt4, value: int64(123456), wanted: int64(123456)},
+		{oid: pgInt8, value: int64(-1234567890123), wanted: int64(-1234567890123)},
+		{oid: pgFloat4, value: 1.5, wanted: 1.5},
+		{oid: pgFloat8, value: -0.1, wanted: -0.1},
+		{oid: pgText, value: "hello", wanted: "hello"},
+		{oid: pgBytea, value: []byte{0, 1, 2}, wanted: []byte{0, 1, 2}},
+		{oid: pgDate, value: tm, wanted: "2021-02-03"},
+		{oid: pgDate, value: time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), wanted: "1999-12-31"},
+		{oid: pgTimestamptz, value: tm, wanted: tm},
+		{oid: pgTimestamp, value: time.Date(1970, 1, 1, 0, 0, 0, 1000, time.UTC), wanted: time.Unix(0, 1000).UTC()},
+		{oid: pgNumeric, value: "0", wanted: []byte("0")},
+		{oid: pgNumeric, value: "-12345.6789", wanted: []byte("-12345.6789")},
+		{oid: pgNumeric, value: "10000", wanted: []byte("10000")},
+		{oid: pgNumeric, value: "0.00001234", wanted: []byte("0.00001234")},
+		{oid: pgNumeric, value: "1.50", wanted: []byte("1.50")},
+		{oid: pgNumeric, value: int64(-42), wanted: []byte("-42")},
+		{oid: pgNumeric, value: 0.25, wanted: []byte("0.25")},
+		{oid: pgUUID, value: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", wanted: []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")},
+		{oid: pgJSONB, value: `{"a":1}`, wanted: []byte(`{"a":1}`)},
+	}
+
+	for _, test := range tests {
+		typ := defaultTypes.Lookup(test.oid).(BinaryType)
+
+		b, ok := typ.AppendBinary(nil, test.value)
+		require.True(t, ok, "oid=%d value=%v", test.oid, test.value)
+
+		got, err := typ.DecodeBinary(b)
+		require.NoError(t, err)
+		require.Equal(t, test.wanted, got, "oid=%d value=%v", test.oid, test.value)
+	}
+}
+
+func TestBinaryTypesTextFallback(t *testing.T) {
+	tests := []struct {
+		oid   int32
+		value driver.Value
+	}{
+		{oid: pgInt2, value: int64(1 << 20)},
+		{oid: pgInt4, value: "123"},
+		{oid: pgText, value: "hell\000o"},
+		{oid: pgTimestamptz, value: time.Time{}},
+		{oid: pgNumeric, value: "1e10"},
+		{oid: pgUUID, value: "not-a-uuid"},
+		{oid: pgInt4Array, value: "{1,2,3}"},
+	}
+
+	for _, test := range tests {
+		typ := defaultTypes.Lookup(test.oid).(BinaryType)
+		b, ok := typ.AppendBinary(nil, test.value)
+		require.False(t, ok, "oid=%d value=%v", test.oid, test.value)
+		require.Empty(t, b)
+	}
+}
+
+func TestBinaryArray(t *testing.T) {
+	array := func(elem int32, dims []int, elems ...[]byte) []byte {
+		b := binary.BigEndian.AppendUint32(nil, uint32(len(dims)))
+		b = binary.BigEndian.AppendUint32(b, 1)
+		b = binary.BigEndian.AppendUint32(b, uint32(elem))
+		for _, dim := range dims {
+			b = binary.BigEndian.AppendUint32(b, uint32(dim))
+			b = binary.BigEndian.AppendUint32(b, 1)
+		}
+		for _, elem := range elems {
+			if elem == nil {
+				b = binary.BigEndian.AppendUint32(b, 0xffffffff)
+				continue
+			}
+			b = binary.BigEndian.AppendUint32(b, uint32(len(elem)))
+			b = append(b, elem...)
+		}
+		return b
+	}
+	int4 := func(n int32) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(n))
+	}
+
+	tests := []struct {
+		oid    int32
+		b      []byte
+		wanted string
+	}{
+		{oid: pgInt4Array, b: array(pgInt4, nil), wanted: "{}"},
+		{oid: pgInt4Array, b: array(pgInt4, []int{3}, int4(1), nil, int4(-3)), wanted: "{1,NULL,-3}"},
+		{
+			oid:    pgInt4Array,
+			b:      array(pgInt4, []int{2, 2}, int4(1), int4(2), int4(3), int4(4)),
+			wanted: "{{1,2},{3,4}}",
+		},
+		{oid: pgTextArray, b: array(pgText, []int{2}, []byte("a b"), []byte(`"\`)), wanted: `{"a b","\"\\"}`},
+		{oid: pgBoolArray, b: array(pgBool, []int{2}, []byte{1}, []byte{0}), wanted: "{t,f}"},
+		{oid: pgByteaArray, b: array(pgBytea, []int{1}, []byte{0xde, 0xad}), wanted: `{"\\xdead"}`},
+	}
+
+	for _, test := range tests {
+		got, err := defaultTypes.Lookup(test.oid).(BinaryType).DecodeBinary(test.b)
+		require.NoError(t, err)
+		require.Equal(t, test.wanted, string(got.([]byte)))
+	}
+}
+
+func TestTypeRegistry(t *testing.T) {
+	types := NewTypeRegistry()
+	require.Nil(t, types.Lookup(12345))
+
+	types.Register(12345, TextType())
+	require.Equal(t, TextType(), types.Lookup(12345))
+
+	got, err := types.Lookup(12345).DecodeText([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", got)
+}
@@ -10,11 +10,14 @@ replace github.com/uptrace/bun/dialect/sqlitedialect => ../../dialect/sqlitedial
 
 replace github.com/uptrace/bun/driver/sqliteshim => ../../driver/sqliteshim
 
+replace github.com/uptrace/bun/migrate/migratecli => ../../migrate/migratecli
+
 require (
 	github.com/uptrace/bun v1.2.15
 	github.com/uptrace/bun/dialect/sqlitedialect v1.2.15
 	github.com/uptrace/bun/driver/sqliteshim v1.2.15
 	github.com/uptrace/bun/extra/bundebug v1.2.15
+	github.com/uptrace/bun/migrate/migratecli v1.2.15
 	github.com/urfave/cli/v2 v2.27.7
 )
 
@@ -295,9 +295,17 @@ func TestDB(t *testing.T) {
 		{testModelNonPointer},
 		{testBinaryData},
 		{testUpsert},
+		{testBulkUpsert},
+		{testInsertChunks},
 		{testMultiUpdate},
 		{testUpdateWithSkipupdateTag},
 		{testScanAndCount},
+		{testKeysetPagination},
+		{testTypedSelect},
+		{testGenerateColumns},
+		{testWindowFunctions},
+		{testCursor},
+		{testBatch},
 		{testEmbedModelValue},
 		{testEmbedModelPointer},
 		{testJSONMarshaler},
@@ -1416,6 +1424,155 @@ func testUpsert(t *testing.T, db *bun.DB) {
 	require.Equal(t, "world", model.Str)
 }
 
+func testBulkUpsert(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID      int64  `bun:",pk,autoincrement"`
+		Email   string `bun:",unique"`
+		Name    string
+		Version int
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	src := []Model{
+		{Email: "alice@example.com", Name: "alice", Version: 1},
+		{Email: "bob@example.com", Name: "bob", Version: 1},
+	}
+	_, err := db.NewInsert().Model(&src).Exec(ctx)
+	require.NoError(t, err)
+
+	names := func(t *testing.T) []string {
+		var models []Model
+		err := db.NewSelect().Model(&models).OrderExpr("email ASC").Scan(ctx)
+		require.NoError(t, err)
+
+		names := make([]string, len(models))
+		for i := range models {
+			names[i] = models[i].Name
+		}
+		return names
+	}
+
+	models := []Model{
+		{Email: "bob@example.com", Name: "Bob", Version: 2},
+		{Email: "carol@example.com", Name: "Carol", Version: 1},
+	}
+	_, err = db.NewInsert().Model(&models).
+		Upsert(bun.NewUpsert("email").Set("name", "version")).
+		Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "Bob", "Carol"}, names(t))
+	if db.HasFeature(feature.InsertReturning) {
+		require.Equal(t, int64(2), models[0].ID)
+		require.Greater(t, models[1].ID, int64(2))
+	}
+
+	models = []Model{
+		{Email: "alice@example.com", Name: "Alice", Version: 1},
+		{Email: "dave@example.com", Name: "Dave", Version: 1},
+	}
+	_, err = db.NewInsert().Model(&models).
+		Upsert(bun.NewUpsert("email").DoNothing()).
+		Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "Bob", "Carol", "Dave"}, names(t))
+
+	if db.HasFeature(feature.InsertOnDuplicateKey) {
+		return
+	}
+
+	models = []Model{
+		{Email: "bob@example.com", Name: "Robert", Version: 1},
+		{Email: "carol@example.com", Name: "Caroline", Version: 2},
+	}
+	_, err = db.NewInsert().Model(&models).
+		Upsert(bun.NewUpsert("email").
+			Set("name", "version").
+			Where("?TableAlias.version < ?", bun.Excluded("version"))).
+		Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "Bob", "Caroline", "Dave"}, names(t))
+}
+
+func testInsertChunks(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64 `bun:",pk,autoincrement"`
+		Str string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	models := make([]Model, 5)
+	for i := range models {
+		models[i].Str = fmt.Sprint(i)
+	}
+	res, err := db.NewInsert().Model(&models).Chunk(2).Exec(ctx)
+	require.NoError(t, err)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+	for i := range models {
+		require.NotZero(t, models[i].ID)
+		require.Equal(t, fmt.Sprint(i), models[i].Str)
+	}
+
+	// The chunks inserted before the failed one are rolled back.
+	dups := []Model{{ID: 10}, {ID: 11}, {ID: 10}}
+	_, err = db.NewInsert().Model(&dups).Chunk(2).Exec(ctx)
+	require.Error(t, err)
+
+	count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+
+	ids := make([]int64, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+
+	// Limit IN lists to 2 values to check that they are split.
+	chunkDB := db
+	if db.Dialect().Name() == dialect.SQLite {
+		chunkDB = bun.NewDB(db.DB, sqlitedialect.New(sqlitedialect.WithMaxValues(2)))
+	}
+
+	var chunks int
+	res, err = bun.ExecInChunks(ctx, chunkDB, ids,
+		func(ctx context.Context, tx bun.Tx, in schema.QueryAppender) (sql.Result, error) {
+			chunks++
+			return tx.NewDelete().Model((*Model)(nil)).Where("id IN (?)", in).Exec(ctx)
+		})
+	require.NoError(t, err)
+	if db.Dialect().Name() == dialect.SQLite {
+		require.Equal(t, 3, chunks)
+	}
+
+	n, err = res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+
+	count, err = db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	if !db.HasFeature(feature.InsertReturning) {
+		return
+	}
+
+	// The values returned for each chunk are appended to dest.
+	models = make([]Model, 5)
+	var returned []int64
+	err = db.NewInsert().Model(&models).Chunk(2).Returning("id").Scan(ctx, &returned)
+	require.NoError(t, err)
+	require.Len(t, returned, 5)
+	for i := 1; i < len(returned); i++ {
+		require.Greater(t, returned[i], returned[i-1])
+	}
+}
+
 func testMultiUpdate(t *testing.T, db *bun.DB) {
 	if !db.Dialect().Features().Has(feature.CTE) {
 		t.Skip()
@@ -1533,6 +1690,478 @@ func testScanAndCount(t *testing.T, db *bun.DB) {
 	})
 }
 
+func testKeysetPagination(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int64 `bun:",pk,autoincrement"`
+		Score *int
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	score := func(n int) *int { return &n }
+	src := []Model{
+		{Score: score(10)},
+		{Score: nil},
+		{Score: score(20)},
+		{Score: score(10)},
+		{Score: nil},
+		{Score: score(30)},
+		{Score: score(20)},
+	}
+	_, err := db.NewInsert().Model(&src).Exec(ctx)
+	require.NoError(t, err)
+
+	// scan walks the pages in the direction of the cursor returned by next.
+	scan := func(t *testing.T, keyset bun.Keyset, next func(*bun.KeysetPage) string) [][]int64 {
+		var pages [][]int64
+		for {
+			var models []Model
+			page, err := db.NewSelect().Model(&models).ScanKeyset(ctx, keyset)
+			require.NoError(t, err)
+
+			ids := make([]int64, len(models))
+			for i := range models {
+				ids[i] = models[i].ID
+			}
+			pages = append(pages, ids)
+
+			if keyset.Cursor = next(page); keyset.Cursor == "" {
+				return pages
+			}
+		}
+	}
+	next := func(page *bun.KeysetPage) string { return page.Next }
+	prev := func(page *bun.KeysetPage) string { return page.Prev }
+
+	t.Run("primary key", func(t *testing.T) {
+		keyset := bun.Keyset{
+			Columns: []bun.KeysetColumn{{Column: "id"}},
+			Limit:   3,
+		}
+		require.Equal(t, [][]int64{{1, 2, 3}, {4, 5, 6}, {7}}, scan(t, keyset, next))
+
+		keyset.Columns[0].Desc = true
+		require.Equal(t, [][]int64{{7, 6, 5}, {4, 3, 2}, {1}}, scan(t, keyset, next))
+	})
+
+	t.Run("nullable and mixed order", func(t *testing.T) {
+		keyset := bun.Keyset{
+			Columns: []bun.KeysetColumn{
+				{Column: "score", Desc: true, Nullable: true},
+				{Column: "id"},
+			},
+			Limit: 2,
+		}
+		pages := scan(t, keyset, next)
+		require.Equal(t, [][]int64{{6, 3}, {7, 1}, {4, 2}, {5}}, pages)
+
+		// Walk back from the last page.
+		for {
+			var models []Model
+			page, err := db.NewSelect().Model(&models).ScanKeyset(ctx, keyset)
+			require.NoError(t, err)
+			if page.Next == "" {
+				require.NotEmpty(t, page.Prev)
+				keyset.Cursor = page.Prev
+				break
+			}
+			keyset.Cursor = page.Next
+		}
+		require.Equal(t, [][]int64{{7, 1}, {6, 3}}, scan(t, keyset, prev)[1:])
+	})
+
+	t.Run("nullzero key", func(t *testing.T) {
+		type RankModel struct {
+			bun.BaseModel `bun:"table:keyset_ranks"`
+
+			ID   int64 `bun:",pk,autoincrement"`
+			Rank int   `bun:",nullzero,notnull,default:0"`
+		}
+		mustResetModel(t, ctx, db, (*RankModel)(nil))
+
+		// Zero ranks are stored as 0, e.g. by the column default, and scanned as Go zero values.
+		for _, rank := range []int{0, 0, 1, 0, 1} {
+			_, err := db.NewRaw("INSERT INTO ? (?) VALUES (?)",
+				bun.Ident("keyset_ranks"), bun.Ident("rank"), rank).Exec(ctx)
+			require.NoError(t, err)
+		}
+
+		keyset := bun.Keyset{
+			Columns: []bun.KeysetColumn{{Column: "rank"}, {Column: "id"}},
+			Limit:   2,
+		}
+		var pages [][]int64
+		for {
+			var models []RankModel
+			page, err := db.NewSelect().Model(&models).ScanKeyset(ctx, keyset)
+			require.NoError(t, err)
+
+			ids := make([]int64, len(models))
+			for i := range models {
+				ids[i] = models[i].ID
+			}
+			pages = append(pages, ids)
+
+			if keyset.Cursor = page.Next; keyset.Cursor == "" {
+				break
+			}
+		}
+		require.Equal(t, [][]int64{{1, 2}, {4, 3}, {5}}, pages)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		var models []Model
+		_, err := db.NewSelect().Model(&models).ScanKeyset(ctx, bun.Keyset{
+			Columns: []bun.KeysetColumn{{Column: "id"}},
+			Cursor:  "invalid",
+			Limit:   3,
+		})
+		require.ErrorIs(t, err, bun.ErrInvalidCursor)
+	})
+}
+
+type TypedUser struct {
+	ID        int64 `bun:",pk,autoincrement"`
+	Email     string
+	Name      *string
+	Tags      []string
+	CreatedAt time.Time
+}
+
+var typedUserColumns = struct {
+	ID        bun.Column[TypedUser, int64]
+	Email     bun.Column[TypedUser, string]
+	Name      bun.Column[TypedUser, *string]
+	CreatedAt bun.Column[TypedUser, time.Time]
+}{
+	ID:        bun.NewColumn[TypedUser, int64]("id"),
+	Email:     bun.NewColumn[TypedUser, string]("email"),
+	Name:      bun.NewColumn[TypedUser, *string]("name"),
+	CreatedAt: bun.NewColumn[TypedUser, time.Time]("created_at"),
+}
+
+func testTypedSelect(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*TypedUser)(nil))
+
+	name := "Bob"
+	src := []TypedUser{
+		{Email: "alice@example.com", CreatedAt: time.Unix(1, 0)},
+		{Email: "bob@example.com", Name: &name, CreatedAt: time.Unix(2, 0)},
+		{Email: "carol@example.com", CreatedAt: time.Unix(3, 0)},
+	}
+	_, err := db.NewInsert().Model(&src).Exec(ctx)
+	require.NoError(t, err)
+
+	c := typedUserColumns
+
+	users, err := bun.Select[TypedUser](db).
+		Where(c.Email.NotEq("bob@example.com")).
+		Order(c.CreatedAt.Desc()).
+		All(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, "carol@example.com", users[0].Email)
+	require.Equal(t, "alice@example.com", users[1].Email)
+
+	user, err := bun.Select[TypedUser](db).
+		Where(c.Name.IsNotNull()).
+		One(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "bob@example.com", user.Email)
+
+	users, err = bun.Select[TypedUser](db).
+		Where(c.ID.In(1, 3).Or(c.Email.Eq("bob@example.com"))).
+		Order(c.ID.Asc()).
+		All(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+
+	count, err := bun.Select[TypedUser](db).
+		Where(c.CreatedAt.Gt(time.Unix(1, 0)), c.ID.Lte(2)).
+		Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	count, err = bun.Select[TypedUser](db).
+		Where(c.ID.In()).
+		Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	count, err = bun.Select[TypedUser](db).
+		Where(c.ID.In().Or(c.ID.Eq(2))).
+		Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	_, err = bun.Select[TypedUser](db).
+		Where(c.Email.Eq("dave@example.com")).
+		One(ctx)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testGenerateColumns(t *testing.T, db *bun.DB) {
+	var buf strings.Builder
+	err := bun.GenerateColumns(&buf, db, "dbtest_test", (*TypedUser)(nil))
+	require.NoError(t, err)
+
+	src := buf.String()
+	require.Contains(t, src, "// Code generated by bun.GenerateColumns. DO NOT EDIT.")
+	require.Contains(t, src, "package dbtest_test")
+	require.Contains(t, src, `"time"`)
+	require.Contains(t, src, "Email     bun.Column[TypedUser, string]")
+	require.Contains(t, src, "Name      bun.Column[TypedUser, *string]")
+	require.Contains(t, src, "Tags      bun.Column[TypedUser, []string]")
+	require.Contains(t, src, `CreatedAt: bun.NewColumn[TypedUser, time.Time]("created_at"),`)
+
+	err = bun.GenerateColumns(&buf, db, "dbtest_test", (*TypedUser)(nil), map[string]any{})
+	require.Error(t, err)
+
+	buf.Reset()
+	err = bun.GenerateColumns(&buf, db, "dbtest_test", (*TypedCustomer)(nil))
+	require.NoError(t, err)
+
+	src = buf.String()
+	require.Contains(t, src, "HomeStreet bun.Column[TypedCustomer, string]")
+	require.Contains(t, src, "WorkStreet bun.Column[TypedCustomer, string]")
+	require.Contains(t, src, `HomeStreet: bun.NewColumn[TypedCustomer, string]("home_street"),`)
+	require.Contains(t, src, `WorkStreet: bun.NewColumn[TypedCustomer, string]("work_street"),`)
+}
+
+type TypedAddress struct {
+	Street string
+}
+
+type TypedCustomer struct {
+	ID   int64        `bun:",pk,autoincrement"`
+	Home TypedAddress `bun:"embed:home_"`
+	Work TypedAddress `bun:"embed:work_"`
+}
+
+func testWindowFunctions(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID       int64 `bun:",pk,autoincrement"`
+		Category string
+		Score    int64
+	}
+	type Row struct {
+		ID    int64
+		Total int64
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	src := []Model{
+		{Category: "a", Score: 1},
+		{Category: "b", Score: 10},
+		{Category: "a", Score: 2},
+		{Category: "a", Score: 3},
+		{Category: "b", Score: 20},
+	}
+	_, err := db.NewInsert().Model(&src).Exec(ctx)
+	require.NoError(t, err)
+
+	scan := func(q *bun.SelectQuery) ([]int64, error) {
+		var rows []Row
+		if err := q.Column("id").OrderExpr("id ASC").Scan(ctx, &rows); err != nil {
+			return nil, err
+		}
+		totals := make([]int64, len(rows))
+		for i, row := range rows {
+			totals[i] = row.Total
+		}
+		return totals, nil
+	}
+
+	t.Run("running total", func(t *testing.T) {
+		w := bun.NewWindow().PartitionBy("category").Order("id").
+			Rows(bun.UnboundedPreceding, bun.CurrentRow)
+		totals, err := scan(db.NewSelect().Model((*Model)(nil)).
+			ColumnExpr("sum(score) OVER ? AS total", w))
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 10, 3, 6, 30}, totals)
+	})
+
+	t.Run("sliding frame", func(t *testing.T) {
+		w := bun.NewWindow().Order("id").Rows(bun.Preceding(1), bun.Following(1))
+		totals, err := scan(db.NewSelect().Model((*Model)(nil)).
+			ColumnExpr("sum(score) OVER ? AS total", w))
+		require.NoError(t, err)
+		require.Equal(t, []int64{11, 13, 15, 25, 23}, totals)
+	})
+
+	t.Run("named window", func(t *testing.T) {
+		q := db.NewSelect().Model((*Model)(nil)).
+			Window("w", bun.NewWindow().PartitionBy("category")).
+			ColumnExpr("sum(score) OVER ? AS total", bun.NewWindow().Base("w").Order("id"))
+		totals, err := scan(q)
+		if !db.HasFeature(feature.NamedWindow) {
+			require.ErrorAs(t, err, new(*feature.NotSupportError))
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 10, 3, 6, 30}, totals)
+	})
+
+	t.Run("groups and exclude", func(t *testing.T) {
+		w := bun.NewWindow().Order("category").
+			Groups(bun.CurrentRow, bun.UnboundedFollowing).
+			Exclude(bun.ExcludeCurrentRow)
+		totals, err := scan(db.NewSelect().Model((*Model)(nil)).
+			ColumnExpr("sum(score) OVER ? AS total", w))
+		if !db.HasFeature(feature.WindowGroups) || !db.HasFeature(feature.WindowExclude) {
+			require.ErrorAs(t, err, new(*feature.NotSupportError))
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, []int64{35, 20, 34, 33, 10}, totals)
+	})
+
+	t.Run("exclude without frame", func(t *testing.T) {
+		w := bun.NewWindow().Order("id").Exclude(bun.ExcludeTies)
+		_, err := w.AppendQuery(db.QueryGen(), nil)
+		require.EqualError(t, err, "bun: window exclusion requires a frame, see Window.Rows")
+	})
+}
+
+type CursorProfile struct {
+	ID   int64 `bun:",pk,autoincrement"`
+	Lang string
+}
+
+type CursorUser struct {
+	ID        int64 `bun:",pk,autoincrement"`
+	Name      string
+	ProfileID int64
+	Profile   *CursorProfile `bun:"rel:belongs-to,join:profile_id=id"`
+
+	Scanned bool `bun:"-"`
+}
+
+var _ bun.AfterScanRowHook = (*CursorUser)(nil)
+
+func (u *CursorUser) AfterScanRow(ctx context.Context) error {
+	u.Scanned = true
+	return nil
+}
+
+func testCursor(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*CursorUser)(nil), (*CursorProfile)(nil))
+
+	profiles := []CursorProfile{{Lang: "en"}, {Lang: "ru"}}
+	_, err := db.NewInsert().Model(&profiles).Exec(ctx)
+	require.NoError(t, err)
+
+	users := []CursorUser{
+		{Name: "alice", ProfileID: 1},
+		{Name: "bob", ProfileID: 2},
+		{Name: "carol", ProfileID: 1},
+	}
+	_, err = db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	t.Run("cursor", func(t *testing.T) {
+		cursor, err := db.NewSelect().
+			Model((*CursorUser)(nil)).
+			Relation("Profile").
+			OrderExpr("?TableAlias.id ASC").
+			Cursor(ctx)
+		require.NoError(t, err)
+		defer cursor.Close()
+
+		var names, langs []string
+		for cursor.Next() {
+			user := new(CursorUser)
+			require.NoError(t, cursor.Scan(user))
+			require.True(t, user.Scanned)
+			require.NotNil(t, user.Profile)
+			names = append(names, user.Name)
+			langs = append(langs, user.Profile.Lang)
+		}
+		require.NoError(t, cursor.Err())
+		require.Equal(t, []string{"alice", "bob", "carol"}, names)
+		require.Equal(t, []string{"en", "ru", "en"}, langs)
+	})
+
+	t.Run("invalid dest", func(t *testing.T) {
+		cursor, err := db.NewSelect().Model((*CursorUser)(nil)).Cursor(ctx)
+		require.NoError(t, err)
+		defer cursor.Close()
+
+		require.True(t, cursor.Next())
+		require.Error(t, cursor.Scan(new(CursorProfile)))
+	})
+
+	t.Run("iter", func(t *testing.T) {
+		var names []string
+		for user, err := range bun.Select[CursorUser](db).
+			Where(bun.NewColumn[CursorUser, string]("name").NotEq("bob")).
+			Apply(func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Relation("Profile").OrderExpr("?TableAlias.id ASC")
+			}).
+			Iter(ctx) {
+			require.NoError(t, err)
+			require.Equal(t, "en", user.Profile.Lang)
+			names = append(names, user.Name)
+		}
+		require.Equal(t, []string{"alice", "carol"}, names)
+
+		names = nil
+		for user, err := range bun.Select[CursorUser](db).Iter(ctx) {
+			require.NoError(t, err)
+			names = append(names, user.Name)
+			break
+		}
+		require.Len(t, names, 1)
+	})
+}
+
+func testBatch(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64 `bun:",pk"`
+		Str string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	res, err := db.NewBatch().
+		Queue(db.NewInsert().Model(&[]Model{{ID: 1, Str: "a"}, {ID: 2, Str: "b"}})).
+		Queue(db.NewUpdate().Model(&Model{ID: 1, Str: "c"}).WherePK()).
+		Queue(db.NewDelete().Model(&Model{ID: 2}).WherePK()).
+		Exec(ctx)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	for i, wanted := range []int64{2, 1, 1} {
+		n, err := res[i].RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, wanted, n)
+	}
+
+	var models []Model
+	err = db.NewSelect().Model(&models).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{{ID: 1, Str: "c"}}, models)
+
+	_, err = db.NewBatch().
+		Queue(db.NewInsert().Model(&Model{ID: 3, Str: "d"})).
+		Queue(db.NewInsert().Model(&Model{ID: 1, Str: "e"})).
+		Exec(ctx)
+	require.Error(t, err)
+
+	count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	_, err = db.NewBatch().Queue(db.NewSelect().Model((*Model)(nil))).Exec(ctx)
+	require.Error(t, err)
+}
+
 func testEmbedModelValue(t *testing.T, db *bun.DB) {
 	type DoubleEmbed struct {
 		A string
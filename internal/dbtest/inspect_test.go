@@ -3,12 +3,14 @@ package dbtest_test
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/migrate/sqlschema"
 	"github.com/uptrace/bun/schema"
@@ -73,9 +75,16 @@ type Journalist struct {
 
 func TestDatabaseInspector_Inspect(t *testing.T) {
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
+		switch db.Dialect().Name() {
+		case dialect.MySQL:
+			t.Skip("mysql: identity and gen_random_uuid() are not supported")
+		case dialect.MSSQL:
+			t.Skip("mssql: GENERATED AS IDENTITY and gen_random_uuid() are not supported")
+		}
+
 		defaultSchema := db.Dialect().DefaultSchema()
 
-		for _, tt := range []struct {
+		tests := []struct {
 			name       string
 			schemaName string
 			wantTables []sqlschema.Table
@@ -275,9 +284,104 @@ func TestDatabaseInspector_Inspect(t *testing.T) {
 					},
 				},
 			},
-		} {
+		}
+
+		// Junction table of the m2m relation between publishers and journalists.
+		var junction any = (*PublisherToJournalist)(nil)
+
+		// Order of creation matters:
+		models := []any{
+			(*Journalist)(nil),            // does not reference other tables
+			(*Publisher)(nil),             // does not reference other tables
+			(*Office)(nil),                // references Publisher
+			(*PublisherToJournalist)(nil), // references Journalist and Publisher
+			(*Article)(nil),               // references Journalist and Publisher
+		}
+
+		// SQLite has no identity columns, does not allow AUTOINCREMENT outside of INTEGER PRIMARY KEY
+		// and has no gen_random_uuid(), so it gets its own models with the closest equivalents.
+		if db.Dialect().Name() == dialect.SQLite {
+			type JournalistSQLite struct {
+				bun.BaseModel `bun:"table:authors"`
+				ID            int    `bun:"author_id,pk"`
+				FirstName     string `bun:"first_name,notnull,unique:full_name"`
+				LastName      string `bun:"last_name,notnull,unique:full_name"`
+				Email         string `bun:"email,notnull,unique"`
+			}
+
+			type PublisherSQLite struct {
+				bun.BaseModel `bun:"table:publishers"`
+				ID            string    `bun:"publisher_id,pk,default:(lower(hex(randomblob(16)))),unique:office_fk"`
+				Name          string    `bun:"publisher_name,notnull,unique:office_fk"`
+				CreatedAt     time.Time `bun:"created_at,default:current_timestamp"`
+
+				Writers []JournalistSQLite `bun:"m2m:publisher_to_journalists,join:Publisher=Author"`
+			}
+
+			type OfficeSQLite struct {
+				bun.BaseModel `bun:"table:admin.offices"`
+				Name          string `bun:"office_name,pk"`
+				TennantID     string `bun:"publisher_id"`
+				TennantName   string `bun:"publisher_name"`
+
+				Tennant *PublisherSQLite `bun:"rel:has-one,join:publisher_id=publisher_id,join:publisher_name=publisher_name"`
+			}
+
+			type PublisherToJournalistSQLite struct {
+				bun.BaseModel `bun:"table:publisher_to_journalists"`
+				PublisherID   string `bun:"publisher_id,pk"`
+				AuthorID      int    `bun:"author_id,pk"`
+
+				Publisher *PublisherSQLite  `bun:"rel:belongs-to,join:publisher_id=publisher_id"`
+				Author    *JournalistSQLite `bun:"rel:belongs-to,join:author_id=author_id"`
+			}
+
+			type ArticleSQLite struct {
+				bun.BaseModel `bun:"table:articles"`
+				ISBN          int    `bun:",pk"`
+				Editor        string `bun:",notnull,unique:title_author,default:'john doe'"`
+				Title         string `bun:",notnull,unique:title_author"`
+				Locale        string `bun:",type:varchar(5),default:'en-GB'"`
+				Pages         int8   `bun:"page_count,notnull,default:1"`
+				Count         int32  `bun:"book_count,notnull"`
+				PublisherID   string `bun:"publisher_id,notnull"`
+				AuthorID      int    `bun:"author_id,notnull"`
+
+				Publisher *PublisherSQLite  `bun:"rel:belongs-to,join:publisher_id=publisher_id"`
+				Author    *JournalistSQLite `bun:"rel:belongs-to,join:author_id=author_id"`
+			}
+
+			junction = (*PublisherToJournalistSQLite)(nil)
+			models = []any{
+				(*JournalistSQLite)(nil),
+				(*PublisherSQLite)(nil),
+				(*OfficeSQLite)(nil),
+				(*PublisherToJournalistSQLite)(nil),
+				(*ArticleSQLite)(nil),
+			}
+
+			column := func(table, name string) *sqlschema.BaseColumn {
+				for _, t := range tests[0].wantTables {
+					if t.GetName() != table {
+						continue
+					}
+					for _, c := range t.(*sqlschema.BaseTable).Columns {
+						if c.GetName() == name {
+							return c.(*sqlschema.BaseColumn)
+						}
+					}
+				}
+				panic(fmt.Sprintf("no column %s.%s", table, name))
+			}
+			column("articles", "isbn").IsIdentity = false
+			column("articles", "book_count").IsAutoIncrement = false
+			column("authors", "author_id").IsIdentity = false
+			column("publishers", "publisher_id").DefaultValue = "lower(hex(randomblob(16)))"
+		}
+
+		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				db.RegisterModel((*PublisherToJournalist)(nil))
+				db.RegisterModel(junction)
 
 				dbInspector, err := sqlschema.NewInspector(db, sqlschema.WithSchemaName(tt.schemaName), sqlschema.WithExcludeTables(migrationsTable, migrationLocksTable))
 				if err != nil {
@@ -288,14 +392,7 @@ func TestDatabaseInspector_Inspect(t *testing.T) {
 
 				// Always create admin schema to test filtration is done correctly.
 				mustCreateSchema(t, ctx, db, "admin")
-				mustCreateTableWithFKs(t, ctx, db,
-					// Order of creation matters:
-					(*Journalist)(nil),            // does not reference other tables
-					(*Publisher)(nil),             // does not reference other tables
-					(*Office)(nil),                // references Publisher
-					(*PublisherToJournalist)(nil), // references Journalist and Publisher
-					(*Article)(nil),               // references Journalist and Publisher
-				)
+				mustCreateTableWithFKs(t, ctx, db, models...)
 
 				got, err := dbInspector.Inspect(ctx)
 				require.NoError(t, err)
@@ -327,6 +424,11 @@ func mustCreateTableWithFKs(tb testing.TB, ctx context.Context, db *bun.DB, mode
 
 func mustCreateSchema(tb testing.TB, ctx context.Context, db *bun.DB, schema string) {
 	tb.Helper()
+	if db.Dialect().Name() == dialect.SQLite {
+		mustAttachDatabase(tb, ctx, db, schema)
+		return
+	}
+
 	_, err := db.NewRaw("CREATE SCHEMA IF NOT EXISTS ?", bun.Ident(schema)).Exec(ctx)
 	require.NoError(tb, err, "create schema %q:", schema)
 
@@ -335,6 +437,20 @@ func mustCreateSchema(tb testing.TB, ctx context.Context, db *bun.DB, schema str
 	})
 }
 
+// mustAttachDatabase attaches a new database file under the schema name.
+// ATTACH only affects the current connection, so the pool is limited to a single one.
+func mustAttachDatabase(tb testing.TB, ctx context.Context, db *bun.DB, schema string) {
+	tb.Helper()
+	db.SetMaxOpenConns(1)
+	_, err := db.NewRaw("ATTACH DATABASE ? AS ?", filepath.Join(tb.TempDir(), schema+".db"), bun.Ident(schema)).Exec(ctx)
+	require.NoError(tb, err, "attach database %q:", schema)
+
+	tb.Cleanup(func() {
+		db.NewRaw("DETACH DATABASE ?", bun.Ident(schema)).Exec(ctx)
+		db.SetMaxOpenConns(0)
+	})
+}
+
 // cmpTables compares table schemas using dialect-specific equivalence checks for column types
 // and reports the differences as t.Error().
 func cmpTables(
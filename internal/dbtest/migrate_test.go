@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/migrate"
 	"github.com/uptrace/bun/migrate/sqlschema"
@@ -55,6 +61,14 @@ func TestMigrate(t *testing.T) {
 	tests := []Test{
 		{run: testMigrateUpAndDown},
 		{run: testMigrateUpError},
+		{run: testMigrateDrift},
+		{run: testMigrateUpgradesTable},
+		{run: testMigrateToRollbackTo},
+		{run: testMigrateRunLocked},
+		{run: testMigrateLeaseLocker},
+		{run: testMigrateAdvisoryLocker},
+		{run: testMigrateSquash},
+		{run: testMigrateBackfill},
 	}
 
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
@@ -180,6 +194,538 @@ func testMigrateUpError(t *testing.T, db *bun.DB) {
 	require.Equal(t, []string{"down2", "down1"}, history)
 }
 
+func testMigrateToRollbackTo(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	var history []string
+
+	migrations := migrate.NewMigrations()
+	for i, name := range []string{"20060102150405", "20060102160405", "20060102170405"} {
+		n := strconv.Itoa(i + 1)
+		migrations.Add(migrate.Migration{
+			Name:    name,
+			Comment: "step" + n,
+			Up: func(ctx context.Context, migrator *migrate.Migrator, migration *migrate.Migration) error {
+				history = append(history, "up"+n)
+				return nil
+			},
+			Down: func(ctx context.Context, migrator *migrate.Migrator, migration *migrate.Migration) error {
+				history = append(history, "down"+n)
+				return nil
+			},
+		})
+	}
+
+	m := migrate.NewMigrator(db, migrations,
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+	)
+	err := m.Reset(ctx)
+	require.NoError(t, err)
+
+	group, err := m.MigrateTo(ctx, "20060102150405")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), group.ID)
+	require.Len(t, group.Migrations, 1)
+
+	group, err = m.MigrateTo(ctx, "20060102170405_step3")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), group.ID)
+	require.Len(t, group.Migrations, 2)
+	require.Equal(t, []string{"up1", "up2", "up3"}, history)
+
+	history = nil
+	group, err = m.Redo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), group.ID)
+	require.Equal(t, []string{"down3", "down2", "up2", "up3"}, history)
+
+	// Rolls back across groups, leaving the target applied.
+	history = nil
+	rolledBack, err := m.RollbackTo(ctx, "20060102150405")
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 2)
+	require.Equal(t, []string{"down3", "down2"}, history)
+
+	applied, err := m.AppliedMigrations(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	require.Equal(t, "20060102150405", applied[0].Name)
+
+	_, err = m.MigrateTo(ctx, "20060102180405")
+	require.Error(t, err, "unknown migration")
+
+	// Nothing is applied if the target has already been applied, even the older migrations.
+	require.NoError(t, m.MarkApplied(ctx, &migrate.Migration{Name: "20060102170405", GroupID: 2}))
+	history = nil
+	group, err = m.MigrateTo(ctx, "20060102170405")
+	require.NoError(t, err)
+	require.True(t, group.IsZero())
+	require.Empty(t, history)
+}
+
+func testMigrateRunLocked(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	newMigrator := func(opts ...migrate.MigratorOption) *migrate.Migrator {
+		opts = append(opts,
+			migrate.WithTableName(migrationsTable),
+			migrate.WithLocksTableName(migrationLocksTable),
+		)
+		return migrate.NewMigrator(db, migrate.NewMigrations(), opts...)
+	}
+
+	m1 := newMigrator()
+	require.NoError(t, m1.Reset(ctx))
+
+	err := m1.RunLocked(ctx, func(ctx context.Context) error {
+		err := newMigrator().RunLocked(ctx, func(ctx context.Context) error {
+			return errors.New("must not run")
+		})
+		require.ErrorIs(t, err, migrate.ErrLocked)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The lock is released even if fn fails.
+	err = m1.RunLocked(ctx, func(ctx context.Context) error {
+		return errors.New("fn failed")
+	})
+	require.EqualError(t, err, "fn failed")
+
+	// Waits for the lock to be released.
+	require.NoError(t, m1.Lock(ctx))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = m1.Unlock(ctx)
+	}()
+
+	var ran bool
+	err = newMigrator(migrate.WithLockTimeout(10*time.Second)).RunLocked(ctx, func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	// Errors other than a held lock are not retried.
+	m2 := migrate.NewMigrator(db, migrate.NewMigrations(),
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName("missing_migration_locks"),
+		migrate.WithLockTimeout(10*time.Second),
+	)
+	err = m2.RunLocked(ctx, func(ctx context.Context) error {
+		return errors.New("must not run")
+	})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, migrate.ErrLocked)
+}
+
+func testMigrateLeaseLocker(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	const leasesTable = "test_migration_leases"
+	newLocker := func(ttl time.Duration) *migrate.LeaseLocker {
+		return migrate.NewLeaseLocker(db, migrationsTable, ttl, migrate.WithLeasesTableName(leasesTable))
+	}
+
+	locker := newLocker(2 * time.Second)
+	m := migrate.NewMigrator(db, migrate.NewMigrations(),
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+		migrate.WithLocker(locker),
+	)
+	require.NoError(t, m.Reset(ctx))
+	t.Cleanup(func() {
+		_, err := db.NewDropTable().Table(leasesTable).IfExists().Exec(ctx)
+		require.NoError(t, err)
+	})
+
+	expiresAt := func() time.Time {
+		var expiresAt time.Time
+		err := db.NewSelect().
+			Table(leasesTable).
+			Column("expires_at").
+			Where("? = ?", bun.Ident("table_name"), migrationsTable).
+			Scan(ctx, &expiresAt)
+		require.NoError(t, err)
+		return expiresAt
+	}
+
+	err := m.RunLocked(ctx, func(ctx context.Context) error {
+		require.ErrorIs(t, newLocker(time.Minute).Lock(ctx), migrate.ErrLocked)
+
+		// The lease is renewed every third of its TTL.
+		before := expiresAt()
+		time.Sleep(1500 * time.Millisecond)
+		require.True(t, expiresAt().After(before), "lease is not renewed")
+		return nil
+	})
+	require.NoError(t, err)
+
+	n, err := db.NewSelect().Table(leasesTable).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, n, "lease is not released")
+
+	// A lost lease fails RunLocked even if fn succeeds.
+	err = m.RunLocked(ctx, func(ctx context.Context) error {
+		_, err := db.NewUpdate().
+			Table(leasesTable).
+			Set("? = ?", bun.Ident("owner"), "someone-else").
+			Where("? = ?", bun.Ident("table_name"), migrationsTable).
+			Exec(ctx)
+		require.NoError(t, err)
+
+		<-ctx.Done()
+		return nil
+	})
+	require.ErrorIs(t, err, migrate.ErrLeaseLost)
+	_, err = db.NewDelete().Table(leasesTable).Where("1 = 1").Exec(ctx)
+	require.NoError(t, err)
+
+	// An expired lease is taken over by another owner.
+	expired := newLocker(time.Millisecond)
+	require.NoError(t, expired.Lock(ctx))
+	time.Sleep(10 * time.Millisecond)
+
+	other := newLocker(time.Minute)
+	require.NoError(t, other.Lock(ctx))
+	require.ErrorIs(t, expired.Renew(ctx), migrate.ErrLeaseLost)
+	require.NoError(t, expired.Unlock(ctx))
+	require.ErrorIs(t, newLocker(time.Minute).Lock(ctx), migrate.ErrLocked)
+	require.NoError(t, other.Unlock(ctx))
+}
+
+func testMigrateAdvisoryLocker(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.MySQL:
+	default:
+		t.Skip("advisory locks are not supported")
+	}
+
+	newMigrator := func() *migrate.Migrator {
+		return migrate.NewMigrator(db, migrate.NewMigrations(),
+			migrate.WithTableName(migrationsTable),
+			migrate.WithLocksTableName(migrationLocksTable),
+			migrate.WithLocker(migrate.NewAdvisoryLocker(db, migrationsTable)),
+		)
+	}
+
+	err := newMigrator().RunLocked(ctx, func(ctx context.Context) error {
+		require.ErrorIs(t, newMigrator().Lock(ctx), migrate.ErrLocked)
+		return nil
+	})
+	require.NoError(t, err)
+
+	m := newMigrator()
+	require.NoError(t, m.Lock(ctx))
+	require.NoError(t, m.Unlock(ctx))
+}
+
+func testMigrateSquash(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"20060102150405_create_users.up.sql":   "CREATE TABLE squash_users (id INTEGER PRIMARY KEY, name VARCHAR(100) NOT NULL, locale VARCHAR(5) DEFAULT 'en-GB')",
+		"20060102150405_create_users.down.sql": "DROP TABLE squash_users",
+		"20060102160405_create_posts.up.sql": "" +
+			"CREATE TABLE squash_posts (id INTEGER PRIMARY KEY, user_id INTEGER, " +
+			"FOREIGN KEY (user_id) REFERENCES squash_users (id))\n" +
+			"--bun:split\n" +
+			"CREATE INDEX squash_posts_user_id_idx ON squash_posts (user_id)",
+		"20060102160405_create_posts.down.sql": "DROP TABLE squash_posts",
+		"20060102170405_create_tags.up.sql":    "CREATE TABLE squash_tags (id INTEGER PRIMARY KEY)",
+		"20060102170405_create_tags.down.sql":  "DROP TABLE squash_tags",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	t.Cleanup(func() {
+		for _, table := range []string{"squash_tags", "squash_posts", "squash_users"} {
+			_, err := db.NewDropTable().Table(table).IfExists().Exec(ctx)
+			require.NoError(t, err)
+		}
+	})
+
+	newMigrator := func() *migrate.Migrator {
+		migrations := migrate.NewMigrations(migrate.WithMigrationsDirectory(dir))
+		require.NoError(t, migrations.Discover(os.DirFS(dir)))
+		return migrate.NewMigrator(db, migrations,
+			migrate.WithTableName(migrationsTable),
+			migrate.WithLocksTableName(migrationLocksTable),
+		)
+	}
+
+	m := newMigrator()
+	require.NoError(t, m.Reset(ctx))
+	_, err := m.MigrateTo(ctx, "20060102160405")
+	require.NoError(t, err)
+
+	baseline, err := m.Squash(ctx, "20060102160405")
+	require.NoError(t, err)
+	require.Len(t, baseline, 2)
+	require.True(t, strings.HasPrefix(baseline[0].Content, "--bun:squashed 20060102150405 20060102160405\n"))
+	require.Contains(t, baseline[0].Content, "'en-GB'", "string default must stay quoted")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.Equal(t, []string{
+		"20060102160405_baseline.down.sql",
+		"20060102160405_baseline.up.sql",
+		"20060102170405_create_tags.down.sql",
+		"20060102170405_create_tags.up.sql",
+	}, names)
+
+	// The database which has applied the squashed migrations keeps their history.
+	m = newMigrator()
+	drift, err := m.Validate(ctx)
+	require.NoError(t, err)
+	require.True(t, drift.IsZero(), drift.String())
+
+	group, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	require.Len(t, group.Migrations, 1)
+	require.Equal(t, "20060102170405", group.Migrations[0].Name)
+
+	// A new database starts from the baseline.
+	for _, table := range []string{"squash_tags", "squash_posts", "squash_users"} {
+		_, err := db.NewDropTable().Table(table).Exec(ctx)
+		require.NoError(t, err)
+	}
+	require.NoError(t, m.Reset(ctx))
+
+	group, err = m.Migrate(ctx)
+	require.NoError(t, err)
+	require.Len(t, group.Migrations, 2)
+	require.Equal(t, "20060102160405", group.Migrations[0].Name)
+
+	_, err = db.ExecContext(ctx, "INSERT INTO squash_users (id, name) VALUES (1, 'john')")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO squash_posts (id, user_id) VALUES (1, 1)")
+	require.NoError(t, err)
+
+	var locale string
+	require.NoError(t, db.NewSelect().Table("squash_users").Column("locale").Where("id = 1").Scan(ctx, &locale))
+	require.Equal(t, "en-GB", locale)
+
+	drift, err = m.Validate(ctx)
+	require.NoError(t, err)
+	require.True(t, drift.IsZero(), drift.String())
+
+	_, err = m.Rollback(ctx)
+	require.NoError(t, err)
+}
+
+func testMigrateBackfill(t *testing.T, db *bun.DB) {
+	type BackfillUser struct {
+		bun.BaseModel `bun:"table:backfill_users"`
+
+		ID    int64 `bun:",pk,autoincrement"`
+		Name  string
+		Upper string
+	}
+
+	ctx := context.Background()
+
+	mustResetModel(t, ctx, db, (*BackfillUser)(nil))
+	users := make([]BackfillUser, 25)
+	for i := range users {
+		users[i].Name = fmt.Sprintf("user%02d", i)
+	}
+	_, err := db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	processed := make(map[string]int)
+	var progress []migrate.BackfillProgress
+	var fail bool
+	backfill := &migrate.Backfill[BackfillUser]{
+		BatchSize: 10,
+		Process: func(ctx context.Context, tx bun.Tx, users []BackfillUser) error {
+			if fail && len(progress) == 1 {
+				return errors.New("crash")
+			}
+			for i := range users {
+				users[i].Upper = strings.ToUpper(users[i].Name)
+				if _, err := tx.NewUpdate().Model(&users[i]).Column("upper").WherePK().Exec(ctx); err != nil {
+					return err
+				}
+				processed[users[i].Name]++
+			}
+			return nil
+		},
+		Progress: func(ctx context.Context, p migrate.BackfillProgress) {
+			progress = append(progress, p)
+		},
+	}
+
+	migrations := migrate.NewMigrations()
+	migrations.Add(migrate.Migration{
+		Name: "20060102150405",
+		Up:   backfill.Up,
+		Down: backfill.Down,
+	})
+	m := migrate.NewMigrator(db, migrations,
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+		migrate.WithMarkAppliedOnSuccess(true),
+	)
+	require.NoError(t, m.Reset(ctx))
+
+	// The first batch is committed before the crash.
+	fail = true
+	_, err = m.Migrate(ctx)
+	require.ErrorContains(t, err, "crash")
+	require.Len(t, progress, 1)
+	require.Equal(t, int64(10), progress[0].Processed)
+
+	// The backfill resumes after the last committed batch.
+	fail = false
+	group, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	require.Len(t, group.Migrations, 1)
+	require.Len(t, processed, 25)
+	for name, n := range processed {
+		require.Equal(t, 1, n, name)
+	}
+
+	last := progress[len(progress)-1]
+	require.True(t, last.Done)
+	require.Equal(t, int64(25), last.Processed)
+	require.Equal(t, 5, last.Rows)
+
+	var upper []string
+	err = db.NewSelect().Model((*BackfillUser)(nil)).Column("upper").Order("id").Scan(ctx, &upper)
+	require.NoError(t, err)
+	require.Equal(t, "USER00", upper[0])
+	require.Equal(t, "USER24", upper[24])
+
+	// Rollback resets the progress.
+	_, err = m.Rollback(ctx)
+	require.NoError(t, err)
+	_, err = m.Migrate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, processed["user00"])
+}
+
+func testMigrateDrift(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"20060102150405_first.up.sql":    {Data: []byte("SELECT 1")},
+		"20060102150405_first.down.sql":  {Data: []byte("SELECT 1")},
+		"20060102160405_second.up.sql":   {Data: []byte("SELECT 2")},
+		"20060102160405_second.down.sql": {Data: []byte("SELECT 2")},
+	}
+	newMigrator := func() *migrate.Migrator {
+		migrations := migrate.NewMigrations()
+		require.NoError(t, migrations.Discover(fsys))
+		return migrate.NewMigrator(db, migrations,
+			migrate.WithTableName(migrationsTable),
+			migrate.WithLocksTableName(migrationLocksTable),
+			migrate.WithDriftCheck(true),
+		)
+	}
+
+	m := newMigrator()
+	require.NoError(t, m.Reset(ctx))
+	_, err := m.Migrate(ctx)
+	require.NoError(t, err)
+
+	drift, err := m.Validate(ctx)
+	require.NoError(t, err)
+	require.True(t, drift.IsZero(), "unexpected drift: %s", drift)
+
+	// Edit an applied migration, delete another one and add a migration older than both.
+	fsys["20060102150405_first.up.sql"] = &fstest.MapFile{Data: []byte("SELECT 10")}
+	delete(fsys, "20060102160405_second.up.sql")
+	delete(fsys, "20060102160405_second.down.sql")
+	fsys["20060102140405_early.up.sql"] = &fstest.MapFile{Data: []byte("SELECT 0")}
+
+	m = newMigrator()
+	drift, err = m.Validate(ctx)
+	require.NoError(t, err)
+
+	names := func(ms migrate.MigrationSlice) (names []string) {
+		for _, m := range ms {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+	require.Equal(t, []string{"20060102150405"}, names(drift.Modified), "modified")
+	require.Equal(t, []string{"20060102160405"}, names(drift.Missing), "missing")
+	require.Equal(t, []string{"20060102140405"}, names(drift.OutOfOrder), "out of order")
+
+	group, err := m.Migrate(ctx)
+	var driftErr *migrate.DriftError
+	require.ErrorAs(t, err, &driftErr)
+	require.Nil(t, group)
+}
+
+func testMigrateUpgradesTable(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	// The migrations table created before the checksum and squashed_into columns were added.
+	type LegacyMigration struct {
+		bun.BaseModel
+
+		ID         int64 `bun:",pk,autoincrement"`
+		Name       string
+		GroupID    int64
+		MigratedAt time.Time `bun:",notnull,nullzero,default:current_timestamp"`
+	}
+
+	fsys := fstest.MapFS{
+		"20060102150405_first.up.sql":    {Data: []byte("SELECT 1")},
+		"20060102150405_first.down.sql":  {Data: []byte("SELECT 1")},
+		"20060102160405_second.up.sql":   {Data: []byte("SELECT 2")},
+		"20060102160405_second.down.sql": {Data: []byte("SELECT 2")},
+	}
+	migrations := migrate.NewMigrations()
+	require.NoError(t, migrations.Discover(fsys))
+
+	m := migrate.NewMigrator(db, migrations,
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+	)
+	require.NoError(t, m.Reset(ctx))
+
+	_, err := db.NewDropTable().Table(migrationsTable).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*LegacyMigration)(nil)).ModelTableExpr(migrationsTable).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().
+		Model(&LegacyMigration{Name: "20060102150405", GroupID: 1}).
+		ModelTableExpr(migrationsTable).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	// A new migrator records the checksum without running Init first.
+	m = migrate.NewMigrator(db, migrations,
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+	)
+	group, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	require.Len(t, group.Migrations, 1)
+	require.Equal(t, "20060102160405", group.Migrations[0].Name)
+
+	applied, err := m.AppliedMigrations(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	for _, migration := range applied {
+		if migration.Name == "20060102160405" {
+			require.NotEmpty(t, migration.Checksum)
+		}
+	}
+}
+
 // newAutoMigratorOrSkip creates an AutoMigrator configured to use test migratins/locks
 // tables and dedicated migrations directory. If an AutoMigrator cannob be created because
 // the dialect doesn't support either schema inspections or migrations, the test will be *skipped*
@@ -272,12 +818,75 @@ func TestAutoMigrator_CreateSQLMigrations(t *testing.T) {
 
 			require.Len(t, migrations, 2, "expected up/down migration pair")
 			require.DirExists(t, migrationsDir)
-			checkMigrationFileContains(t, "_auto.tx.up.sql", "CREATE TABLE", "SET statement_timeout = 0")
-			checkMigrationFileContains(t, "_auto.tx.down.sql", "DROP TABLE", "SET statement_timeout = 0")
+			checkMigrationFileContains(t, "_auto.tx.up.sql", "CREATE TABLE")
+			checkMigrationFileContains(t, "_auto.tx.down.sql", "DROP TABLE")
+			if db.Dialect().Name() == dialect.PG {
+				checkMigrationFileContains(t, "_auto.tx.up.sql", "SET statement_timeout = 0")
+				checkMigrationFileContains(t, "_auto.tx.down.sql", "SET statement_timeout = 0")
+			} else {
+				checkMigrationFileContainsTimes(t, "_auto.tx.up.sql", "statement_timeout", 0)
+				checkMigrationFileContainsTimes(t, "_auto.tx.down.sql", "statement_timeout", 0)
+			}
 		})
 	})
 }
 
+func TestAutoMigrator_Snapshot(t *testing.T) {
+	type PizzaBefore struct {
+		bun.BaseModel `bun:"table:snapshot_pizzas"`
+		Name          string `bun:",pk"`
+	}
+	type PizzaAfter struct {
+		bun.BaseModel `bun:"table:snapshot_pizzas"`
+		Name          string `bun:",pk"`
+		Crust         string
+	}
+
+	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
+		ctx := context.Background()
+		snapshot := filepath.Join(t.TempDir(), "schema.json")
+
+		before := newAutoMigratorOrSkip(t, db,
+			migrate.WithModel((*PizzaBefore)(nil)),
+			migrate.WithSnapshot(snapshot))
+
+		files, err := before.CreateSQLMigrations(ctx)
+		require.NoError(t, err)
+		require.Len(t, files, 2, "expected up/down migration pair")
+		require.Contains(t, files[0].Content, "CREATE TABLE")
+		require.FileExists(t, snapshot)
+
+		b, err := os.ReadFile(snapshot)
+		require.NoError(t, err)
+		state, err := sqlschema.ReadSnapshot(bytes.NewReader(b))
+		require.NoError(t, err)
+		require.Len(t, state.GetTables(), 1)
+		require.Equal(t, "snapshot_pizzas", state.GetTables()[0].GetName())
+
+		var buf bytes.Buffer
+		require.NoError(t, sqlschema.WriteSnapshot(&buf, state))
+		require.Equal(t, string(b), buf.String(), "snapshot must be stable")
+
+		files, err = before.CreateSQLMigrations(ctx)
+		require.NoError(t, err)
+		require.Empty(t, files, "models have not changed since the snapshot")
+
+		after := newAutoMigratorOrSkip(t, db,
+			migrate.WithModel((*PizzaAfter)(nil)),
+			migrate.WithSnapshot(snapshot))
+
+		files, err = after.CreateSQLMigrations(ctx)
+		require.NoError(t, err)
+		require.Len(t, files, 2, "expected up/down migration pair")
+		require.NotContains(t, files[0].Content, "CREATE TABLE")
+		require.Contains(t, files[0].Content, "crust")
+
+		// Migrations were created without inspecting or changing the database.
+		_, err = db.NewSelect().Table("snapshot_pizzas").Count(ctx)
+		require.Error(t, err, "table must not exist")
+	})
+}
+
 // checkMigrationFileContains expected SQL snippets.
 func checkMigrationFileContains(t *testing.T, fileSuffix string, snippets ...string) {
 	t.Helper()
@@ -363,12 +972,20 @@ func TestAutoMigrator_Migrate(t *testing.T) {
 		{testChangeColumnType_AutoCast},
 		{testIdentity},
 		{testAddDropColumn},
+		{testAlterTableKeepsData},
+		{testRebuildReferencedTable},
+		{testRebuildKeepsPrimaryKeyOrder},
 		{testUnique},
 		{testUniqueRenamedTable},
+		{testIndexes},
+		{testChecksEnumsComments},
 		{testUpdatePrimaryKeys},
 		{testNothingToMigrate},
+		{testSafetyPolicy},
+		{testOnlineMigrations},
 		{testExcludeForeignKeys},
 		{testExcludeTableLike},
+		{testExcludeMigratorTables},
 	}
 
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
@@ -414,6 +1031,13 @@ func testRenameTable(t *testing.T, db *bun.DB) {
 }
 
 func testCreateDropTable(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.MySQL:
+		t.Skip("mysql: identity and gen_random_uuid() are not supported")
+	case dialect.MSSQL:
+		t.Skip("mssql: GENERATED AS IDENTITY and gen_random_uuid() are not supported")
+	}
+
 	type DropMe struct {
 		bun.BaseModel `bun:"table:dropme"`
 		Foo           int `bun:"foo,identity"`
@@ -425,12 +1049,30 @@ func testCreateDropTable(t *testing.T, db *bun.DB) {
 		Baz           time.Time
 	}
 
+	var dropMe, createMe any = (*DropMe)(nil), (*CreateMe)(nil)
+
+	// SQLite has no identity columns and no gen_random_uuid() function.
+	if db.Dialect().Name() == dialect.SQLite {
+		type DropMeSQLite struct {
+			bun.BaseModel `bun:"table:dropme"`
+			Foo           int `bun:"foo,pk,autoincrement"`
+		}
+
+		type CreateMeSQLite struct {
+			bun.BaseModel `bun:"table:createme"`
+			Bar           string `bun:",pk,default:(lower(hex(randomblob(16))))"`
+			Baz           time.Time
+		}
+
+		dropMe, createMe = (*DropMeSQLite)(nil), (*CreateMeSQLite)(nil)
+	}
+
 	// Arrange
 	ctx := context.Background()
 	inspect := inspectDbOrSkip(t, db)
-	mustResetModel(t, ctx, db, (*DropMe)(nil))
-	mustDropTableOnCleanup(t, ctx, db, (*CreateMe)(nil))
-	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*CreateMe)(nil)))
+	mustResetModel(t, ctx, db, dropMe)
+	mustDropTableOnCleanup(t, ctx, db, createMe)
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel(createMe))
 
 	// Act
 	runMigrations(t, m)
@@ -591,6 +1233,13 @@ func checkHasColumn(t *testing.T, table sqlschema.Table, name string) {
 // testChangeColumnType_AutoCast checks type changes which can be type-casted automatically,
 // i.e. do not require supplying a USING clause (pgdialect).
 func testChangeColumnType_AutoCast(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.MySQL:
+		t.Skip("mysql: identity and gen_random_uuid() are not supported")
+	case dialect.MSSQL:
+		t.Skip("mssql: GENERATED AS IDENTITY and gen_random_uuid() are not supported")
+	}
+
 	type TableBefore struct {
 		bun.BaseModel `bun:"table:change_me_own_type"`
 
@@ -671,10 +1320,45 @@ func testChangeColumnType_AutoCast(t *testing.T, db *bun.DB) {
 		},
 	}
 
+	var before, after any = (*TableBefore)(nil), (*TableAfter)(nil)
+
+	// SQLite has no identity columns and no gen_random_uuid() function, autoincrement
+	// is only supported for INTEGER PRIMARY KEY and expressions in the DEFAULT clause
+	// must be enclosed in parentheses.
+	if db.Dialect().Name() == dialect.SQLite {
+		type TableBeforeSQLite struct {
+			bun.BaseModel `bun:"table:change_me_own_type"`
+
+			SmallInt     int32     `bun:"bigger_int,pk"`
+			Timestamp    time.Time `bun:"ts"`
+			DefaultExpr  string    `bun:"default_expr,default:(lower(hex(randomblob(16))))"`
+			EmptyDefault string    `bun:"empty_default"`
+			Nullable     string    `bun:"not_null"`
+			TypeOverride string    `bun:"type:varchar(100)"`
+		}
+
+		type TableAfterSQLite struct {
+			bun.BaseModel `bun:"table:change_me_own_type"`
+
+			BigInt       int64     `bun:"bigger_int,pk"`
+			Timestamp    time.Time `bun:"ts,default:current_timestamp"`
+			DefaultExpr  string    `bun:"default_expr,default:(random())"`
+			EmptyDefault string    `bun:"empty_default,default:''"`
+			NotNullable  string    `bun:"not_null,notnull"`
+			TypeOverride string    `bun:"type:varchar(200)"`
+		}
+
+		before, after = (*TableBeforeSQLite)(nil), (*TableAfterSQLite)(nil)
+
+		table := wantTables[0].(*sqlschema.BaseTable)
+		table.Columns[0].(*sqlschema.BaseColumn).IsIdentity = false
+		table.Columns = table.Columns[:len(table.Columns)-1] // no "incremented" column
+	}
+
 	ctx := context.Background()
 	inspect := inspectDbOrSkip(t, db)
-	mustResetModel(t, ctx, db, (*TableBefore)(nil))
-	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*TableAfter)(nil)))
+	mustResetModel(t, ctx, db, before)
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel(after))
 
 	// Act
 	runMigrations(t, m)
@@ -685,6 +1369,13 @@ func testChangeColumnType_AutoCast(t *testing.T, db *bun.DB) {
 }
 
 func testIdentity(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.MySQL:
+		t.Skip("mysql: identity is not supported")
+	case dialect.MSSQL:
+		t.Skip("mssql: GENERATED AS IDENTITY is not supported")
+	}
+
 	type TableBefore struct {
 		bun.BaseModel `bun:"table:bourne_identity"`
 		A             int64 `bun:",notnull,identity"`
@@ -716,10 +1407,49 @@ func testIdentity(t *testing.T, db *bun.DB) {
 		},
 	}
 
+	var before, after any = (*TableBefore)(nil), (*TableAfter)(nil)
+
+	// SQLite has no identity columns, the closest equivalent is INTEGER PRIMARY KEY AUTOINCREMENT.
+	if db.Dialect().Name() == dialect.SQLite {
+		type TableBeforeSQLite struct {
+			bun.BaseModel `bun:"table:bourne_identity"`
+			A             int64 `bun:",pk,autoincrement"`
+			B             int64
+		}
+
+		type TableAfterSQLite struct {
+			bun.BaseModel `bun:"table:bourne_identity"`
+			A             int64 `bun:",notnull"`
+			B             int64 `bun:",pk,autoincrement"`
+		}
+
+		before, after = (*TableBeforeSQLite)(nil), (*TableAfterSQLite)(nil)
+
+		wantTables = []sqlschema.Table{
+			&sqlschema.BaseTable{
+				Schema: db.Dialect().DefaultSchema(),
+				Name:   "bourne_identity",
+				Columns: []sqlschema.Column{
+					&sqlschema.BaseColumn{
+						Name:            "a",
+						SQLType:         sqltype.BigInt,
+						IsAutoIncrement: false, // <- drop AUTOINCREMENT
+					},
+					&sqlschema.BaseColumn{
+						Name:            "b",
+						SQLType:         sqltype.BigInt,
+						IsAutoIncrement: true, // <- add AUTOINCREMENT
+					},
+				},
+				PrimaryKey: &sqlschema.PrimaryKey{Columns: sqlschema.NewColumns("b")},
+			},
+		}
+	}
+
 	ctx := context.Background()
 	inspect := inspectDbOrSkip(t, db)
-	mustResetModel(t, ctx, db, (*TableBefore)(nil))
-	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*TableAfter)(nil)))
+	mustResetModel(t, ctx, db, before)
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel(after))
 
 	// Act
 	runMigrations(t, m)
@@ -730,6 +1460,10 @@ func testIdentity(t *testing.T, db *bun.DB) {
 }
 
 func testAddDropColumn(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL {
+		t.Skip("mysql: autoincrement is only supported for key columns")
+	}
+
 	type TableBefore struct {
 		bun.BaseModel `bun:"table:column_madness"`
 		DoNotTouch    string `bun:"do_not_touch"`
@@ -762,10 +1496,26 @@ func testAddDropColumn(t *testing.T, db *bun.DB) {
 		},
 	}
 
+	var after any = (*TableAfter)(nil)
+
+	// SQLite only supports autoincrement for INTEGER PRIMARY KEY.
+	if db.Dialect().Name() == dialect.SQLite {
+		type TableAfterSQLite struct {
+			bun.BaseModel `bun:"table:column_madness"`
+			DoNotTouch    string `bun:"do_not_touch"`
+			AddMe         int64  `bun:"addme,notnull"`
+		}
+
+		after = (*TableAfterSQLite)(nil)
+
+		table := wantTables[0].(*sqlschema.BaseTable)
+		table.Columns[1].(*sqlschema.BaseColumn).IsAutoIncrement = false
+	}
+
 	ctx := context.Background()
 	inspect := inspectDbOrSkip(t, db)
 	mustResetModel(t, ctx, db, (*TableBefore)(nil))
-	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*TableAfter)(nil)))
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel(after))
 
 	// Act
 	runMigrations(t, m)
@@ -775,6 +1525,200 @@ func testAddDropColumn(t *testing.T, db *bun.DB) {
 	cmpTables(t, db.Dialect().(sqlschema.InspectorDialect), wantTables, state.GetTables())
 }
 
+// testAlterTableKeepsData checks that existing rows survive changes to columns and constraints.
+// This is of special concern for SQLite, which re-creates the table to apply most of them.
+func testAlterTableKeepsData(t *testing.T, db *bun.DB) {
+	type Parent struct {
+		bun.BaseModel `bun:"table:keep_parents"`
+		ID            int64 `bun:",pk"`
+	}
+
+	type TableBefore struct {
+		bun.BaseModel `bun:"table:keep_my_data"`
+		ID            int64  `bun:",pk"`
+		Name          string `bun:"name"`
+		Count         int64  `bun:"count"`
+		ParentID      int64  `bun:"parent_id"`
+		DropMe        string `bun:"drop_me"`
+	}
+
+	type TableAfter struct {
+		bun.BaseModel `bun:"table:keep_my_data"`
+		ID            int64  `bun:",pk"`
+		Name          string `bun:"name,unique"`    // add UNIQUE
+		Count         int64  `bun:"count,notnull"`  // set NOT NULL
+		ParentID      int64  `bun:"parent_id"`      // add FOREIGN KEY
+		Rank          int64  `bun:"rank,default:0"` // new column
+
+		Parent *Parent `bun:"rel:belongs-to,join:parent_id=id"`
+	}
+
+	ctx := context.Background()
+	inspect := inspectDbOrSkip(t, db)
+	mustResetModel(t, ctx, db, (*TableBefore)(nil), (*Parent)(nil))
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*TableAfter)(nil), (*Parent)(nil)))
+
+	_, err := db.NewInsert().Model(&Parent{ID: 1}).Exec(ctx)
+	require.NoError(t, err, "arrange: insert parent")
+	_, err = db.NewInsert().Model(&TableBefore{ID: 1, Name: "one", Count: 42, ParentID: 1, DropMe: "x"}).Exec(ctx)
+	require.NoError(t, err, "arrange: insert row")
+
+	// Act
+	runMigrations(t, m)
+
+	// Assert
+	var got TableAfter
+	err = db.NewSelect().Model(&got).Where("id = 1").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "one", got.Name)
+	require.EqualValues(t, 42, got.Count)
+	require.EqualValues(t, 1, got.ParentID)
+	require.EqualValues(t, 0, got.Rank)
+
+	state := inspect(ctx)
+	require.Contains(t, state.ForeignKeys, sqlschema.ForeignKey{
+		From: sqlschema.NewColumnReference("keep_my_data", "parent_id"),
+		To:   sqlschema.NewColumnReference("keep_parents", "id"),
+	}, "expected new FK constraint keep_my_data.parent_id -> keep_parents.id")
+
+	var table sqlschema.Table
+	for _, tbl := range state.GetTables() {
+		if tbl.GetName() == "keep_my_data" {
+			table = tbl
+		}
+	}
+	require.NotNil(t, table, "table keep_my_data is missing")
+	require.Len(t, table.GetColumns(), 5)
+	checkHasColumn(t, table, "rank")
+	for _, col := range table.GetColumns() {
+		require.NotEqual(t, "drop_me", col.GetName(), "column drop_me should have been dropped")
+		if col.GetName() == "count" {
+			require.False(t, col.GetIsNullable(), "column count should be NOT NULL")
+		}
+	}
+	require.Equal(t, []sqlschema.Unique{{Columns: sqlschema.NewColumns("name")}},
+		stripUniqueNames(table.GetUniqueConstraints()))
+}
+
+// SQLite re-creates the table to change a column type. Foreign keys cannot be disabled
+// in a transaction, so the rebuild must not be written to a transactional migration,
+// otherwise dropping the old table deletes the rows which reference it.
+func testRebuildReferencedTable(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() != dialect.SQLite {
+		t.Skip("table rebuilds are specific to SQLite")
+	}
+
+	type Parent struct {
+		bun.BaseModel `bun:"table:rebuild_parents"`
+		ID            int64   `bun:",pk"`
+		Rank          float64 `bun:"rank"` // INTEGER -> REAL
+	}
+
+	type Child struct {
+		bun.BaseModel `bun:"table:rebuild_children"`
+		ID            int64   `bun:",pk"`
+		ParentID      int64   `bun:"parent_id"`
+		Parent        *Parent `bun:"rel:belongs-to,join:parent_id=id,on_delete:CASCADE"`
+	}
+
+	ctx := context.Background()
+
+	// PRAGMA foreign_keys is set per connection.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_, err := db.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+		require.NoError(t, err)
+		db.SetMaxOpenConns(0)
+	})
+	_, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	require.NoError(t, err, "arrange: enable foreign keys")
+
+	_, err = db.ExecContext(ctx, "CREATE TABLE rebuild_parents (id INTEGER PRIMARY KEY, rank INTEGER)")
+	require.NoError(t, err, "arrange: create parents")
+	_, err = db.ExecContext(ctx, `CREATE TABLE rebuild_children (
+		id INTEGER PRIMARY KEY,
+		parent_id INTEGER REFERENCES rebuild_parents (id) ON DELETE CASCADE
+	)`)
+	require.NoError(t, err, "arrange: create children")
+	mustDropTableOnCleanup(t, ctx, db, (*Child)(nil), (*Parent)(nil))
+
+	_, err = db.ExecContext(ctx, "INSERT INTO rebuild_parents (id, rank) VALUES (1, 1)")
+	require.NoError(t, err, "arrange: insert parent")
+	_, err = db.ExecContext(ctx, "INSERT INTO rebuild_children (id, parent_id) VALUES (1, 1)")
+	require.NoError(t, err, "arrange: insert child")
+
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*Parent)(nil), (*Child)(nil)))
+
+	// Act
+	files, err := m.CreateTxSQLMigrations(ctx)
+	require.NoError(t, err, "create sql migrations")
+	runMigrations(t, m)
+
+	// Assert
+	var rebuilds int
+	for _, f := range files {
+		if strings.Contains(f.Content, "PRAGMA foreign_keys = OFF") {
+			require.NotContains(t, f.Name, ".tx.", "%s: table rebuild inside a transaction", f.Name)
+			require.Contains(t, f.Content, "PRAGMA foreign_key_check", "%s: foreign keys are not checked", f.Name)
+			rebuilds++
+		}
+	}
+	require.NotZero(t, rebuilds, "no tables were re-created")
+
+	n, err := db.NewSelect().Model((*Child)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "child rows were deleted")
+
+	var typ string
+	err = db.NewRaw("SELECT type FROM pragma_table_info('rebuild_parents') WHERE name = 'rank'").Scan(ctx, &typ)
+	require.NoError(t, err)
+	require.Equal(t, "DOUBLE PRECISION", strings.ToUpper(typ))
+}
+
+// Re-created SQLite table must keep the order of the columns in a composite primary key.
+func testRebuildKeepsPrimaryKeyOrder(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() != dialect.SQLite {
+		t.Skip("table rebuilds are specific to SQLite")
+	}
+
+	type Score struct {
+		bun.BaseModel `bun:"table:pk_order_scores"`
+		A             int64   `bun:"a,pk"`
+		B             int64   `bun:"b,pk"`
+		Value         float64 `bun:"value"` // INTEGER -> REAL
+	}
+
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx, `CREATE TABLE pk_order_scores (
+		a INTEGER NOT NULL,
+		b INTEGER NOT NULL,
+		value INTEGER,
+		PRIMARY KEY (b, a)
+	)`)
+	require.NoError(t, err, "arrange: create table")
+	mustDropTableOnCleanup(t, ctx, db, (*Score)(nil))
+
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*Score)(nil)))
+
+	// Act
+	runMigrations(t, m)
+
+	// Assert
+	var pk []string
+	err = db.NewRaw("SELECT name FROM pragma_table_info('pk_order_scores') WHERE pk > 0 ORDER BY pk").Scan(ctx, &pk)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, pk)
+}
+
+// stripUniqueNames resets constraint names, which are dialect-specific.
+func stripUniqueNames(uniques []sqlschema.Unique) []sqlschema.Unique {
+	out := make([]sqlschema.Unique, 0, len(uniques))
+	for _, u := range uniques {
+		out = append(out, sqlschema.Unique{Columns: u.Columns})
+	}
+	return out
+}
+
 func testUnique(t *testing.T, db *bun.DB) {
 	type TableBefore struct {
 		bun.BaseModel `bun:"table:uniqlo_stores"`
@@ -862,6 +1806,10 @@ func testUnique(t *testing.T, db *bun.DB) {
 }
 
 func testUniqueRenamedTable(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL {
+		t.Skip("mysql: schemas are separate databases")
+	}
+
 	type TableBefore struct {
 		bun.BaseModel `bun:"table:automigrate.before"`
 		FirstName     string `bun:"first_name,unique:full_name"`
@@ -940,7 +1888,170 @@ func testUniqueRenamedTable(t *testing.T, db *bun.DB) {
 	cmpTables(t, db.Dialect().(sqlschema.InspectorDialect), wantTables, state.GetTables())
 }
 
+type indexedPost struct {
+	bun.BaseModel `bun:"table:indexed_posts"`
+	ID            int64     `bun:",pk"`
+	TenantID      int64     `bun:"tenant_id,index:indexed_posts_tenant_slug_idx"`
+	Slug          string    `bun:"slug,index:indexed_posts_tenant_slug_idx"`
+	Title         string    `bun:"title"`
+	AuthorID      int64     `bun:"author_id,index"`
+	DeletedAt     time.Time `bun:"deleted_at,nullzero"`
+}
+
+var _ bun.ModelIndexer = (*indexedPost)(nil)
+
+func (*indexedPost) ModelIndexes() []bun.Index {
+	return []bun.Index{
+		{Name: "indexed_posts_title_lower_idx", Expr: "lower(title)"},
+		{Name: "indexed_posts_live_idx", Columns: []string{"author_id"}, Where: "deleted_at IS NULL"},
+	}
+}
+
+func testIndexes(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.MySQL:
+		t.Skip("MySQL does not support partial indexes")
+	case dialect.MSSQL:
+		t.Skip("SQL Server does not support expression indexes")
+	}
+
+	type TableBefore struct {
+		bun.BaseModel `bun:"table:indexed_posts"`
+		ID            int64     `bun:",pk"`
+		TenantID      int64     `bun:"tenant_id"`
+		Slug          string    `bun:"slug"`
+		Title         string    `bun:"title"`
+		DeletedAt     time.Time `bun:"deleted_at,nullzero"`
+	}
+
+	ctx := context.Background()
+	inspect := inspectDbOrSkip(t, db)
+	mustResetModel(t, ctx, db, (*TableBefore)(nil))
+	_, err := db.NewCreateIndex().Model((*TableBefore)(nil)).
+		Index("indexed_posts_title_idx").Column("title").Exec(ctx)
+	require.NoError(t, err, "arrange: create index")
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*indexedPost)(nil)))
+
+	// Act
+	runMigrations(t, m)
+
+	// Assert
+	state := inspect(ctx)
+	var table sqlschema.Table
+	for _, tbl := range state.GetTables() {
+		if tbl.GetName() == "indexed_posts" {
+			table = tbl
+		}
+	}
+	require.NotNil(t, table, "table indexed_posts is missing")
+
+	want := []sqlschema.Index{
+		{Name: "indexed_posts_tenant_slug_idx", Columns: []string{"tenant_id", "slug"}},
+		{Name: "indexed_posts_author_id_idx", Columns: []string{"author_id"}},
+		{Name: "indexed_posts_title_lower_idx", Expr: "lower(title)"},
+		{Name: "indexed_posts_live_idx", Columns: []string{"author_id"}, Where: "deleted_at IS NULL"},
+	}
+	got := table.GetIndexes()
+	require.Len(t, got, len(want), "got indexes: %+v", got)
+	for _, wantIdx := range want {
+		i := slices.IndexFunc(got, func(gotIdx sqlschema.Index) bool {
+			return gotIdx.Name == wantIdx.Name && gotIdx.Equals(wantIdx)
+		})
+		require.NotEqualf(t, -1, i, "missing index %+v, got: %+v", wantIdx, got)
+	}
+
+	group, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	require.True(t, group.IsZero(), "indexes should not be re-created")
+}
+
+func testChecksEnumsComments(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() != dialect.PG {
+		t.Skip("enum types and column comments are only migrated in Postgres")
+	}
+
+	type TicketBefore struct {
+		bun.BaseModel `bun:"table:tickets"`
+		ID            int64   `bun:",pk"`
+		Status        string  `bun:"status,type:ticket_status"`
+		Price         float64 `bun:"price,check:(price > 0)"`
+		Assignee      string  `bun:"assignee"`
+	}
+
+	type Ticket struct {
+		bun.BaseModel `bun:"table:tickets"`
+		ID            int64   `bun:",pk"`
+		Status        string  `bun:"status,type:ticket_status,enum:(open,pending,closed)"`
+		Price         float64 `bun:"price,check:(price >= 0)"`
+		Assignee      string  `bun:"assignee,comment:Login of the support engineer"`
+	}
+
+	type TicketLabel struct {
+		bun.BaseModel `bun:"table:ticket_labels"`
+		ID            int64  `bun:",pk"`
+		Priority      string `bun:"priority,type:ticket_priority,enum:(low,high)"`
+	}
+
+	ctx := context.Background()
+	inspect := inspectDbOrSkip(t, db)
+
+	dropTypes := func() {
+		_, err := db.NewRaw("DROP TYPE IF EXISTS ticket_status, ticket_priority").Exec(ctx)
+		require.NoError(t, err, "drop enum types")
+	}
+	dropTypes()
+	t.Cleanup(dropTypes)
+
+	_, err := db.NewRaw("CREATE TYPE ticket_status AS ENUM ('open', 'closed')").Exec(ctx)
+	require.NoError(t, err, "arrange: create enum type")
+	mustResetModel(t, ctx, db, (*TicketBefore)(nil))
+	mustDropTableOnCleanup(t, ctx, db, (*TicketLabel)(nil))
+	_, err = db.NewRaw("COMMENT ON COLUMN tickets.assignee IS 'Login'").Exec(ctx)
+	require.NoError(t, err, "arrange: comment on column")
+
+	m := newAutoMigratorOrSkip(t, db, migrate.WithModel((*Ticket)(nil), (*TicketLabel)(nil)))
+
+	// Act
+	runMigrations(t, m)
+
+	// Assert
+	state := inspect(ctx)
+	require.ElementsMatch(t, []sqlschema.Enum{
+		{Name: "ticket_priority", Values: []string{"low", "high"}},
+		{Name: "ticket_status", Values: []string{"open", "pending", "closed"}},
+	}, state.GetEnums())
+
+	var table sqlschema.Table
+	for _, tbl := range state.GetTables() {
+		if tbl.GetName() == "tickets" {
+			table = tbl
+		}
+	}
+	require.NotNil(t, table, "table tickets is missing")
+
+	checks := table.GetChecks()
+	require.Len(t, checks, 1, "got checks: %+v", checks)
+	require.True(t, checks[0].Equals(sqlschema.Check{Expr: "price >= 0"}), "got check: %+v", checks[0])
+
+	for _, col := range table.GetColumns() {
+		if col.GetName() == "assignee" {
+			require.Equal(t, "Login of the support engineer", col.GetComment())
+		}
+	}
+
+	group, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	require.True(t, group.IsZero(), "checks, enums and comments should not be re-created")
+}
+
 func testUpdatePrimaryKeys(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.MySQL:
+		t.Skip("mysql: identity is not supported")
+	case dialect.MSSQL:
+		t.Skip("mssql: GENERATED AS IDENTITY is not supported")
+	}
+
 	// Has a composite primary key.
 	type DropPKBefore struct {
 		bun.BaseModel `bun:"table:drop_your_pks"`
@@ -1046,16 +2157,41 @@ func testUpdatePrimaryKeys(t *testing.T, db *bun.DB) {
 		},
 	}
 
+	var changePKBefore, addNewPKAfter any = (*ChangePKBefore)(nil), (*AddNewPKAfter)(nil)
+
+	// SQLite has no identity columns, the closest equivalent is INTEGER PRIMARY KEY AUTOINCREMENT.
+	if db.Dialect().Name() == dialect.SQLite {
+		type ChangePKBeforeSQLite struct {
+			bun.BaseModel `bun:"table:change_pk"`
+			ID            int64  `bun:"deprecated,pk,autoincrement"`
+			FirstName     string `bun:"first_name"`
+			LastName      string `bun:"last_name"`
+		}
+
+		type AddNewPKAfterSQLite struct {
+			bun.BaseModel `bun:"table:add_new_pk"`
+			ID            int64  `bun:"new_id,pk,autoincrement"`
+			FirstName     string `bun:"first_name"`
+			LastName      string `bun:"last_name"`
+		}
+
+		changePKBefore, addNewPKAfter = (*ChangePKBeforeSQLite)(nil), (*AddNewPKAfterSQLite)(nil)
+
+		newID := wantTables[1].(*sqlschema.BaseTable).Columns[0].(*sqlschema.BaseColumn)
+		newID.IsIdentity = false
+		newID.IsAutoIncrement = true
+	}
+
 	ctx := context.Background()
 	inspect := inspectDbOrSkip(t, db)
 	mustResetModel(t, ctx, db,
 		(*DropPKBefore)(nil),
 		(*AddNewPKBefore)(nil),
-		(*ChangePKBefore)(nil),
+		changePKBefore,
 	)
 	m := newAutoMigratorOrSkip(t, db, migrate.WithModel(
 		(*DropPKAfter)(nil),
-		(*AddNewPKAfter)(nil),
+		addNewPKAfter,
 		(*ChangePKAfter)(nil)),
 	)
 
@@ -1091,6 +2227,127 @@ func testNothingToMigrate(t *testing.T, db *bun.DB) {
 	require.Empty(t, applied, "nothing to migrate, AppliedMigrations not empty")
 }
 
+func testSafetyPolicy(t *testing.T, db *bun.DB) {
+	type ArticleBefore struct {
+		bun.BaseModel `bun:"table:articles"`
+		ID            int64  `bun:",pk"`
+		Title         string `bun:"title"`
+		Draft         string `bun:"draft"`
+	}
+
+	type Article struct {
+		bun.BaseModel `bun:"table:articles"`
+		ID            int64  `bun:",pk"`
+		Title         string `bun:"title"`
+		WordCount     int64  `bun:"word_count"`
+	}
+
+	ctx := context.Background()
+	inspect := inspectDbOrSkip(t, db)
+	mustResetModel(t, ctx, db, (*ArticleBefore)(nil))
+	m := newAutoMigratorOrSkip(t, db,
+		migrate.WithModel((*Article)(nil)),
+		migrate.WithSafetyPolicy(migrate.LossyChange),
+	)
+
+	// Act
+	plan, err := m.Plan(ctx)
+	require.NoError(t, err, "plan")
+	_, migrateErr := m.Migrate(ctx)
+
+	// Assert
+	require.Equal(t, migrate.DestructiveChange, plan.Safety())
+	unsafe := plan.Unsafe(migrate.LossyChange)
+	require.Len(t, unsafe, 1, "got unsafe operations: %+v", unsafe)
+	require.IsType(t, (*migrate.DropColumnOp)(nil), unsafe[0].Operation)
+
+	var unsafeErr *migrate.UnsafeChangesError
+	require.ErrorAs(t, migrateErr, &unsafeErr)
+	require.Len(t, unsafeErr.Operations, 1)
+
+	state := inspect(ctx)
+	for _, table := range state.GetTables() {
+		if table.GetName() != "articles" {
+			continue
+		}
+		var columns []string
+		for _, col := range table.GetColumns() {
+			columns = append(columns, col.GetName())
+		}
+		require.ElementsMatch(t, []string{"id", "title", "draft"}, columns, "no changes should be applied")
+	}
+}
+
+func testOnlineMigrations(t *testing.T, db *bun.DB) {
+	if !db.Dialect().Features().Has(feature.OnlineSchemaChange) {
+		t.Skip("online migrations are only supported in Postgres")
+	}
+
+	type Publisher struct {
+		bun.BaseModel `bun:"table:publishers"`
+		ID            int64 `bun:",pk"`
+	}
+
+	type BookBefore struct {
+		bun.BaseModel `bun:"table:books"`
+		ID            int64  `bun:",pk"`
+		PublisherID   int64  `bun:"publisher_id"`
+		ISBN          string `bun:"isbn"`
+		Title         string `bun:"title"`
+	}
+
+	type Book struct {
+		bun.BaseModel `bun:"table:books"`
+		ID            int64      `bun:",pk"`
+		PublisherID   int64      `bun:"publisher_id"`
+		Publisher     *Publisher `bun:"rel:belongs-to,join:publisher_id=id"`
+		ISBN          string     `bun:"isbn,unique"`
+		Title         string     `bun:"title,index"`
+	}
+
+	ctx := context.Background()
+	inspect := inspectDbOrSkip(t, db)
+	mustResetModel(t, ctx, db, (*Publisher)(nil), (*BookBefore)(nil))
+	m := newAutoMigratorOrSkip(t, db,
+		migrate.WithModel((*Publisher)(nil), (*Book)(nil)),
+		migrate.WithOnlineMigrations(5*time.Second),
+	)
+
+	// Act
+	files, err := m.CreateTxSQLMigrations(ctx)
+	require.NoError(t, err, "create sql migrations")
+	runMigrations(t, m)
+
+	// Assert
+	var concurrent int
+	for _, f := range files {
+		require.Contains(t, f.Content, "SET lock_timeout = '5000ms'", "%s: lock timeout", f.Name)
+		if strings.Contains(f.Content, "CONCURRENTLY") {
+			require.NotContains(t, f.Name, ".tx.", "%s: CONCURRENTLY inside a transaction", f.Name)
+			concurrent++
+		}
+		if strings.Contains(f.Content, "NOT VALID") {
+			require.Contains(t, f.Content, "VALIDATE CONSTRAINT", "%s: constraint is not validated", f.Name)
+		}
+	}
+	require.NotZero(t, concurrent, "no statements use CONCURRENTLY")
+
+	state := inspect(ctx)
+	require.Contains(t, state.GetForeignKeys(), sqlschema.ForeignKey{
+		From: sqlschema.NewColumnReference("books", "publisher_id"),
+		To:   sqlschema.NewColumnReference("publishers", "id"),
+	})
+	for _, table := range state.GetTables() {
+		if table.GetName() != "books" {
+			continue
+		}
+		require.Len(t, table.GetUniqueConstraints(), 1)
+		require.Equal(t, sqlschema.NewColumns("isbn"), table.GetUniqueConstraints()[0].Columns)
+		require.Len(t, table.GetIndexes(), 1)
+		require.Equal(t, []string{"title"}, table.GetIndexes()[0].Columns)
+	}
+}
+
 // Foreign keys can be excluded from migration scope.
 // Useful in cases when foreign keys are created via ForeignKey()
 // method on CreateTableQuery, and not defined in the struct tag.
@@ -1160,6 +2417,38 @@ func testExcludeTableLike(t *testing.T, db *bun.DB) {
 	checkHasTable(t, tables, "exclude_me")
 }
 
+func testExcludeMigratorTables(t *testing.T, db *bun.DB) {
+	type CustomCursors struct {
+		bun.BaseModel `bun:"table:custom_cursors"`
+		Dummy         string `bun:",pk"`
+	}
+
+	type CustomLeases struct {
+		bun.BaseModel `bun:"table:custom_leases"`
+		Dummy         string `bun:",pk"`
+	}
+
+	// Arrange
+	ctx := context.Background()
+	inspect := inspectDbOrSkip(t, db)
+	mustResetModel(t, ctx, db, (*CustomCursors)(nil), (*CustomLeases)(nil))
+	m := newAutoMigratorOrSkip(t, db,
+		migrate.WithCursorsTableNameAuto("custom_cursors"),
+		migrate.WithLockerAuto(migrate.NewLeaseLocker(db, migrationsTable, time.Minute,
+			migrate.WithLeasesTableName("custom_leases"))),
+	)
+
+	// Act
+	_, err := m.Migrate(ctx)
+	require.NoError(t, err, "auto migration failed")
+
+	// Assert
+	state := inspect(ctx)
+	tables := state.GetTables()
+	checkHasTable(t, tables, "custom_cursors")
+	checkHasTable(t, tables, "custom_leases")
+}
+
 func checkHasTable(t *testing.T, tables []sqlschema.Table, name string) {
 	t.Helper()
 	for i := range tables {
@@ -105,6 +105,21 @@ func testModelHook(t *testing.T, dbName string, db *bun.DB) {
 		}, events.Flush())
 	})
 
+	t.Run("insertSliceChunks", func(t *testing.T) {
+		hooks := []ModelHookTest{{ID: 5}, {ID: 6}, {ID: 7}}
+		_, err := db.NewInsert().Model(&hooks).Chunk(2).Exec(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"BeforeInsert",
+			"BeforeAppendModel",
+			"BeforeAppendModel",
+			"AfterInsert",
+			"BeforeInsert",
+			"BeforeAppendModel",
+			"AfterInsert",
+		}, events.Flush())
+	})
+
 	t.Run("insertSliceOfPtr", func(t *testing.T) {
 		hooks := []*ModelHookTest{{ID: 3}, {ID: 4}}
 		_, err := db.NewInsert().Model(&hooks).Exec(ctx)
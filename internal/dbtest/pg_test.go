@@ -682,6 +682,116 @@ func TestPostgresCopyFromCopyTo(t *testing.T) {
 	})
 }
 
+func TestPostgresCopyModels(t *testing.T) {
+	type CopyModel struct {
+		ID        int64 `bun:",pk,autoincrement"`
+		Name      string
+		Count     *int64
+		Data      map[string]any `bun:"type:jsonb"`
+		Bytes     []byte
+		CreatedAt time.Time
+	}
+
+	ctx := context.Background()
+
+	db := pg(t)
+	t.Cleanup(func() { db.Close() })
+
+	count := int64(42)
+	tm := time.Unix(1e9, 123000).UTC()
+
+	for _, isBinary := range []bool{false, true} {
+		t.Run(fmt.Sprintf("binary=%t", isBinary), func(t *testing.T) {
+			mustResetModel(t, ctx, db, (*CopyModel)(nil))
+
+			conn, err := db.Conn(ctx)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			models := []*CopyModel{
+				{Name: "it's\ta \"test\"\n", Count: &count, Data: map[string]any{"a": "b"}, CreatedAt: tm},
+				{Name: "", Bytes: []byte{0, 1, 2}, CreatedAt: tm},
+			}
+			res, err := pgdriver.CopyFromModels(ctx, conn, models, pgdriver.WithCopyBinary(isBinary))
+			require.NoError(t, err)
+
+			n, err := res.RowsAffected()
+			require.NoError(t, err)
+			require.Equal(t, int64(2), n)
+
+			var got []CopyModel
+			res, err = pgdriver.CopyToModels(ctx, conn, &got, pgdriver.WithCopyBinary(isBinary))
+			require.NoError(t, err)
+
+			n, err = res.RowsAffected()
+			require.NoError(t, err)
+			require.Equal(t, int64(2), n)
+
+			require.Len(t, got, 2)
+			for i := range got {
+				require.Equal(t, int64(i+1), got[i].ID)
+				require.Equal(t, models[i].Name, got[i].Name)
+				require.Equal(t, models[i].Count, got[i].Count)
+				require.Equal(t, models[i].Data, got[i].Data)
+				require.Equal(t, models[i].Bytes, got[i].Bytes)
+				require.Equal(t, tm, got[i].CreatedAt.UTC())
+			}
+		})
+	}
+
+	t.Run("iter", func(t *testing.T) {
+		mustResetModel(t, ctx, db, (*CopyModel)(nil))
+
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		models := func(yield func(CopyModel) bool) {
+			for i := 0; i < 10000; i++ {
+				if !yield(CopyModel{Name: fmt.Sprint(i), CreatedAt: tm}) {
+					return
+				}
+			}
+		}
+		res, err := pgdriver.CopyFromIter(ctx, conn, models)
+		require.NoError(t, err)
+
+		n, err := res.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(10000), n)
+
+		var got []*CopyModel
+		_, err = pgdriver.CopyToModels(ctx, conn, &got, pgdriver.WithCopyColumns("id", "name"))
+		require.NoError(t, err)
+		require.Len(t, got, 10000)
+		require.Equal(t, "9999", got[9999].Name)
+		require.Nil(t, got[0].Count)
+	})
+
+	t.Run("unsupported binary type", func(t *testing.T) {
+		type Model struct {
+			ID    int64    `bun:",pk,autoincrement"`
+			Array []string `bun:",array"`
+		}
+
+		mustResetModel(t, ctx, db, (*Model)(nil))
+
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = pgdriver.CopyFromModels(ctx, conn, []Model{{Array: []string{"a"}}},
+			pgdriver.WithCopyBinary(true))
+		require.Error(t, err)
+
+		// The connection is still usable after the aborted COPY.
+		var num int
+		err = conn.QueryRowContext(ctx, "SELECT 1").Scan(&num)
+		require.NoError(t, err)
+		require.Equal(t, 1, num)
+	})
+}
+
 func TestPostgresUUID(t *testing.T) {
 	type Model struct {
 		ID uuid.UUID `bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
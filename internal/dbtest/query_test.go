@@ -12,6 +12,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/migrate"
@@ -1870,6 +1872,57 @@ func TestQuery(t *testing.T) {
 					Returning("merge_action(), ?TableAlias.*")
 			},
 		},
+		{
+			id: 194,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Book struct {
+					ID       int64   `bun:",pk"`
+					Price    float64 `bun:",check:(price > 0),check:price < 1000"`
+					Discount float64 `bun:",check:(discount < price)"`
+				}
+				return db.NewCreateTable().Model(new(Book))
+			},
+		},
+		{
+			id: 195,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID      int64 `bun:",pk,autoincrement"`
+					Email   string
+					Name    string
+					Version int
+				}
+				models := []Model{
+					{Email: "a@example.com", Name: "A", Version: 2},
+					{Email: "b@example.com", Name: "B", Version: 3},
+				}
+				return db.NewInsert().Model(&models).
+					Upsert(bun.NewUpsert("email").
+						Set("name", "version").
+						Where("?TableAlias.version < ?", bun.Excluded("version")))
+			},
+		},
+		{
+			id: 196,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID   int64 `bun:",pk"`
+					Name string
+				}
+				models := []Model{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
+				return db.NewInsert().Model(&models).Upsert(bun.NewUpsert())
+			},
+		},
+		{
+			id: 197,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID   int64 `bun:",pk"`
+					Name string
+				}
+				return db.NewInsert().Model(&Model{ID: 1, Name: "A"}).Upsert(bun.NewUpsert().DoNothing())
+			},
+		},
 	}
 
 	timeRE := regexp.MustCompile(`'2\d{3}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?(\+\d{2}:\d{2})?'`)
@@ -1908,11 +1961,26 @@ func TestAlterTable(t *testing.T) {
 	tests := []struct {
 		name      string
 		operation any
+		feature   feature.Feature // required by the operation, if any
 	}{
 		{name: "create table", operation: &migrate.CreateTableOp{
 			TableName: tableName,
 			Model:     (*Movie)(nil),
 		}},
+		{name: "create table from definition", operation: &migrate.CreateTableOp{
+			TableName: "actors",
+			Table: &sqlschema.BaseTable{
+				Schema: schemaName,
+				Name:   "actors",
+				Columns: []sqlschema.Column{
+					&sqlschema.BaseColumn{Name: "id", SQLType: sqltype.BigInt},
+					&sqlschema.BaseColumn{Name: "language", SQLType: sqltype.VarChar, VarcharLen: 20, DefaultValue: "en-GB"},
+					&sqlschema.BaseColumn{Name: "rating", SQLType: "integer", DefaultValue: "0", IsNullable: true},
+					&sqlschema.BaseColumn{Name: "created_at", SQLType: sqltype.Timestamp, DefaultValue: "current_timestamp"},
+				},
+				PrimaryKey: &sqlschema.PrimaryKey{Columns: sqlschema.NewColumns("id")},
+			},
+		}},
 		{name: "drop table", operation: &migrate.DropTableOp{
 			TableName: tableName,
 		}},
@@ -1966,53 +2034,68 @@ func TestAlterTable(t *testing.T) {
 				Columns: sqlschema.NewColumns("genre", "director"),
 			},
 		}},
+		{name: "create index", operation: &migrate.CreateIndexOp{
+			TableName: tableName,
+			Index: sqlschema.Index{
+				Name:    "movies_director_genre_idx",
+				Columns: []string{"director", "genre"},
+			},
+		}},
+		{name: "drop index", operation: &migrate.DropIndexOp{
+			TableName: tableName,
+			Index: sqlschema.Index{
+				Name:    "movies_director_genre_idx",
+				Columns: []string{"director", "genre"},
+			},
+		}},
+		// Column definitions are complete, because some dialects re-define the entire column.
 		{name: "change column type int to bigint", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "budget",
-			From:      &sqlschema.BaseColumn{SQLType: sqltype.Integer},
-			To:        &sqlschema.BaseColumn{SQLType: sqltype.BigInt},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.BigInt, IsNullable: true},
 		}},
 		{name: "add default", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "budget",
-			From:      &sqlschema.BaseColumn{DefaultValue: ""},
-			To:        &sqlschema.BaseColumn{DefaultValue: "100"},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true, DefaultValue: ""},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true, DefaultValue: "100"},
 		}},
 		{name: "drop default", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "budget",
-			From:      &sqlschema.BaseColumn{DefaultValue: "100"},
-			To:        &sqlschema.BaseColumn{DefaultValue: ""},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true, DefaultValue: "100"},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true, DefaultValue: ""},
 		}},
 		{name: "make nullable", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "director",
-			From:      &sqlschema.BaseColumn{IsNullable: false},
-			To:        &sqlschema.BaseColumn{IsNullable: true},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.VarChar, VarcharLen: 255, IsNullable: false},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.VarChar, VarcharLen: 255, IsNullable: true},
 		}},
 		{name: "add notnull", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "budget",
-			From:      &sqlschema.BaseColumn{IsNullable: true},
-			To:        &sqlschema.BaseColumn{IsNullable: false},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: false},
 		}},
 		{name: "increase varchar length", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "language",
-			From:      &sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 20},
-			To:        &sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 255},
+			From:      &sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 20, IsNullable: true},
+			To:        &sqlschema.BaseColumn{SQLType: "varchar", VarcharLen: 255, IsNullable: true},
 		}},
 		{name: "add identity", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "id",
-			From:      &sqlschema.BaseColumn{IsIdentity: false},
-			To:        &sqlschema.BaseColumn{IsIdentity: true},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.BigInt, IsIdentity: false},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.BigInt, IsIdentity: true},
 		}},
 		{name: "drop identity", operation: &migrate.ChangeColumnTypeOp{
 			TableName: tableName,
 			Column:    "id",
-			From:      &sqlschema.BaseColumn{IsIdentity: true},
-			To:        &sqlschema.BaseColumn{IsIdentity: false},
+			From:      &sqlschema.BaseColumn{SQLType: sqltype.BigInt, IsIdentity: true},
+			To:        &sqlschema.BaseColumn{SQLType: sqltype.BigInt, IsIdentity: false},
 		}},
 		{name: "add primary key", operation: &migrate.AddPrimaryKeyOp{
 			TableName: tableName,
@@ -2053,19 +2136,144 @@ func TestAlterTable(t *testing.T) {
 				To:   sqlschema.NewColumnReference("film_genres", "id"),
 			},
 		}},
+		{name: "add check", feature: feature.CheckConstraint, operation: &migrate.AddCheckOp{
+			TableName: tableName,
+			Check:     sqlschema.Check{Name: "movies_budget_check", Expr: "budget > 0"},
+		}},
+		{name: "drop check", feature: feature.CheckConstraint, operation: &migrate.DropCheckOp{
+			TableName: tableName,
+			Check:     sqlschema.Check{Name: "movies_budget_check", Expr: "budget > 0"},
+		}},
+		{name: "create enum", feature: feature.EnumType, operation: &migrate.CreateEnumOp{
+			Enum: sqlschema.Enum{Name: "rating", Values: []string{"G", "PG", "R"}},
+		}},
+		{name: "drop enum", feature: feature.EnumType, operation: &migrate.DropEnumOp{
+			Enum: sqlschema.Enum{Name: "rating", Values: []string{"G", "PG", "R"}},
+		}},
+		{name: "add enum value", feature: feature.EnumType, operation: &migrate.AlterEnumAddValueOp{
+			EnumName: "rating",
+			Value:    "PG-13",
+			After:    "PG",
+		}},
+		{name: "set comment", feature: feature.CommentOnColumn, operation: &migrate.SetCommentOp{
+			TableName:  tableName,
+			ColumnName: "genre",
+			Comment:    "Director's favourite genre",
+		}},
+		{name: "drop comment", feature: feature.CommentOnColumn, operation: &migrate.SetCommentOp{
+			TableName:  tableName,
+			ColumnName: "genre",
+			OldComment: "Director's favourite genre",
+		}},
+		{name: "add foreign key not valid", feature: feature.OnlineSchemaChange, operation: &migrate.AddForeignKeyOp{
+			ConstraintName: "genre_description",
+			ForeignKey: sqlschema.ForeignKey{
+				From: sqlschema.NewColumnReference("movies", "genre"),
+				To:   sqlschema.NewColumnReference("film_genres", "id"),
+			},
+			NotValid: true,
+		}},
+		{name: "add check not valid", feature: feature.OnlineSchemaChange, operation: &migrate.AddCheckOp{
+			TableName: tableName,
+			Check:     sqlschema.Check{Name: "movies_budget_check", Expr: "budget > 0"},
+			NotValid:  true,
+		}},
+		{name: "validate constraint", feature: feature.OnlineSchemaChange, operation: &migrate.ValidateConstraintOp{
+			TableName:      tableName,
+			ConstraintName: "genre_description",
+		}},
+		{name: "create index concurrently", feature: feature.OnlineSchemaChange, operation: &migrate.CreateIndexOp{
+			TableName: tableName,
+			Index: sqlschema.Index{
+				Name:    "one_genre_per_director",
+				Unique:  true,
+				Columns: []string{"director", "genre"},
+			},
+			Concurrently: true,
+		}},
+		{name: "drop index concurrently", feature: feature.OnlineSchemaChange, operation: &migrate.DropIndexOp{
+			TableName: tableName,
+			Index: sqlschema.Index{
+				Name:    "movies_director_genre_idx",
+				Columns: []string{"director", "genre"},
+			},
+			Concurrently: true,
+		}},
+		{name: "add unique constraint using index", feature: feature.OnlineSchemaChange, operation: &migrate.AddUniqueConstraintOp{
+			TableName: tableName,
+			Unique: sqlschema.Unique{
+				Name:    "one_genre_per_director",
+				Columns: sqlschema.NewColumns("genre", "director"),
+			},
+			UsingIndex: true,
+		}},
+		{name: "set lock timeout", feature: feature.OnlineSchemaChange, operation: &migrate.SetLockTimeoutOp{
+			Timeout: 5 * time.Second,
+		}},
+		{name: "reset lock timeout", feature: feature.OnlineSchemaChange, operation: &migrate.SetLockTimeoutOp{}},
+	}
+
+	// The tables do not exist in this test, so the migrators which inspect the tables
+	// they alter (SQLite) must track their definitions instead.
+	state := func() sqlschema.Database {
+		return sqlschema.BaseDatabase{
+			Tables: []sqlschema.Table{
+				&sqlschema.BaseTable{
+					Schema: schemaName,
+					Name:   tableName,
+					Columns: []sqlschema.Column{
+						&sqlschema.BaseColumn{Name: "id", SQLType: sqltype.BigInt},
+						&sqlschema.BaseColumn{Name: "director", SQLType: sqltype.VarChar},
+						&sqlschema.BaseColumn{Name: "budget", SQLType: sqltype.Integer, IsNullable: true},
+						&sqlschema.BaseColumn{Name: "release_date", SQLType: sqltype.Timestamp, IsNullable: true},
+						&sqlschema.BaseColumn{Name: "has_oscar", SQLType: sqltype.Boolean, IsNullable: true},
+						&sqlschema.BaseColumn{Name: "genre", SQLType: sqltype.VarChar, IsNullable: true},
+						&sqlschema.BaseColumn{Name: "language", SQLType: sqltype.VarChar, VarcharLen: 20, IsNullable: true},
+					},
+					PrimaryKey: &sqlschema.PrimaryKey{Name: "old_pk", Columns: sqlschema.NewColumns("id")},
+					UniqueConstraints: []sqlschema.Unique{
+						{Name: "one_genre_per_director", Columns: sqlschema.NewColumns("genre", "director")},
+					},
+					Indexes: []sqlschema.Index{
+						{Name: "movies_director_genre_idx", Columns: []string{"director", "genre"}},
+					},
+				},
+				&sqlschema.BaseTable{
+					Schema: schemaName,
+					Name:   "film_genres",
+					Columns: []sqlschema.Column{
+						&sqlschema.BaseColumn{Name: "id", SQLType: sqltype.BigInt},
+					},
+					PrimaryKey: &sqlschema.PrimaryKey{Columns: sqlschema.NewColumns("id")},
+				},
+			},
+		}
 	}
 
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
-		migrator, err := sqlschema.NewMigrator(db, schemaName)
-		if err != nil {
+		if _, err := sqlschema.NewMigrator(db, schemaName); err != nil {
 			t.Skip(err)
 		}
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
+				migrator, err := sqlschema.NewMigrator(db, schemaName)
+				require.NoError(t, err)
+				if tracker, ok := migrator.(sqlschema.DatabaseTracker); ok {
+					tracker.TrackDatabase(state())
+				}
+
+				if tt.feature != 0 && !db.Dialect().Features().Has(tt.feature) {
+					t.Skipf("%s does not support %T", dbName, tt.operation)
+				}
+				if change, ok := tt.operation.(*migrate.ChangeColumnTypeOp); ok && db.Dialect().Name() == dialect.MSSQL &&
+					change.From.GetIsIdentity() != change.To.GetIsIdentity() {
+					t.Skip("SQL Server cannot change IDENTITY property of an existing column")
+				}
+
 				b := internal.MakeQueryBytes()
 
-				b, err := migrator.AppendSQL(b, tt.operation)
+				b, err = migrator.AppendSQL(b, tt.operation)
 				require.NoError(t, err, "append sql")
 
 				if err == nil {
@@ -2077,3 +2285,27 @@ func TestAlterTable(t *testing.T) {
 		}
 	})
 }
+
+func TestAlterTable_ChangeColumnWithoutType(t *testing.T) {
+	op := &migrate.ChangeColumnTypeOp{
+		TableName: "movies",
+		Column:    "budget",
+		From:      &sqlschema.BaseColumn{SQLType: sqltype.Integer, IsNullable: true},
+		To:        &sqlschema.BaseColumn{IsNullable: false},
+	}
+
+	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
+		// These dialects re-define the entire column and cannot do so without its data type.
+		switch db.Dialect().Name() {
+		case dialect.MySQL, dialect.MSSQL, dialect.SQLite:
+		default:
+			t.Skipf("%s alters column attributes separately", dbName)
+		}
+
+		migrator, err := sqlschema.NewMigrator(db, db.Dialect().DefaultSchema())
+		require.NoError(t, err)
+
+		_, err = migrator.AppendSQL(nil, op)
+		require.ErrorContains(t, err, "data type is not specified")
+	})
+}
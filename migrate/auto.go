@@ -8,8 +8,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/migrate/sqlschema"
 	"github.com/uptrace/bun/schema"
@@ -59,7 +62,6 @@ func WithSchemaName(schemaName string) AutoMigratorOption {
 // WithTableNameAuto overrides default migrations table name.
 func WithTableNameAuto(table string) AutoMigratorOption {
 	return func(m *AutoMigrator) {
-		m.table = table
 		m.migratorOpts = append(m.migratorOpts, WithTableName(table))
 	}
 }
@@ -67,11 +69,24 @@ func WithTableNameAuto(table string) AutoMigratorOption {
 // WithLocksTableNameAuto overrides default migration locks table name.
 func WithLocksTableNameAuto(table string) AutoMigratorOption {
 	return func(m *AutoMigrator) {
-		m.locksTable = table
 		m.migratorOpts = append(m.migratorOpts, WithLocksTableName(table))
 	}
 }
 
+// WithCursorsTableNameAuto overrides default table name for the cursors of data migrations.
+func WithCursorsTableNameAuto(table string) AutoMigratorOption {
+	return func(m *AutoMigrator) {
+		m.migratorOpts = append(m.migratorOpts, WithCursorsTableName(table))
+	}
+}
+
+// WithLockerAuto sets the strategy used to lock the migrations table, see WithLocker.
+func WithLockerAuto(locker Locker) AutoMigratorOption {
+	return func(m *AutoMigrator) {
+		m.migratorOpts = append(m.migratorOpts, WithLocker(locker))
+	}
+}
+
 // WithMarkAppliedOnSuccessAuto sets the migrator to only mark migrations as applied/unapplied
 // when their up/down is successful.
 func WithMarkAppliedOnSuccessAuto(enabled bool) AutoMigratorOption {
@@ -87,6 +102,59 @@ func WithMigrationsDirectoryAuto(directory string) AutoMigratorOption {
 	}
 }
 
+// WithSafetyPolicy makes AutoMigrator refuse to create or apply migrations which include
+// operations classified above the allowed level, see ClassifyOperation.
+// For example, WithSafetyPolicy(LossyChange) rejects migrations that drop tables or columns
+// and WithSafetyPolicy(DestructiveChange) explicitly allows them.
+//
+// By default, all operations are allowed.
+func WithSafetyPolicy(allowed Safety) AutoMigratorOption {
+	return func(m *AutoMigrator) {
+		m.allowedSafety = allowed
+	}
+}
+
+// WithOnlineMigrations makes AutoMigrator avoid the statements which block reads and writes
+// to a table while the database scans it:
+//   - FOREIGN KEY and CHECK constraints are added as NOT VALID and validated in a separate statement.
+//   - Indexes are created and dropped CONCURRENTLY.
+//   - UNIQUE constraints are added USING a unique index which is created CONCURRENTLY beforehand.
+//
+// Each statement is executed separately. CreateTxSQLMigrations puts the statements which
+// cannot run inside a transaction into separate migrations without the ".tx" suffix.
+//
+// Changes which still require an exclusive lock, e.g. changing the column's data type, are not
+// rewritten. A non-zero lockTimeout makes them fail instead of queueing behind long-running
+// queries and blocking all the queries that arrive after them. Zero keeps the database default.
+//
+// Online migrations are only supported by dialects with feature.OnlineSchemaChange (Postgres).
+func WithOnlineMigrations(lockTimeout time.Duration) AutoMigratorOption {
+	return func(m *AutoMigrator) {
+		m.online = true
+		m.lockTimeout = lockTimeout
+	}
+}
+
+// WithSnapshot makes AutoMigrator detect the changes to the models by comparing them with
+// the schema snapshot saved in the file instead of inspecting the database. After creating
+// the migration files, AutoMigrator updates the snapshot to reflect the current models.
+// A missing file is treated as an empty schema, so the first migration creates all tables.
+//
+// Commit the snapshot together with the migrations: this allows creating migrations without
+// a connection to the database, e.g. in CI. bun.DB is still required to generate
+// the dialect-specific SQL, but it is not queried, so its connection string may point
+// nowhere. Keep in mind that the snapshot only reflects the migrations created from it;
+// changes made to the database by other means are not detected.
+//
+// SQLite queries the database to re-create a table, which it does to apply the changes
+// ALTER TABLE does not support, e.g. changing the column type; create such migrations
+// with a reachable database.
+func WithSnapshot(path string) AutoMigratorOption {
+	return func(m *AutoMigrator) {
+		m.snapshotPath = path
+	}
+}
+
 // AutoMigrator performs automated schema migrations.
 //
 // It is designed to be a drop-in replacement for some Migrator functionality and supports all existing
@@ -126,11 +194,8 @@ type AutoMigrator struct {
 	// modelInspector creates the desired state based on the model definitions.
 	modelInspector sqlschema.Inspector
 
-	// dbMigrator executes ALTER TABLE queries.
-	dbMigrator sqlschema.Migrator
-
-	table      string // Migrations table (excluded from database inspection)
-	locksTable string // Migration locks table (excluded from database inspection)
+	// snapshotPath is the file which replaces the database as the current state, see WithSnapshot.
+	snapshotPath string
 
 	// schemaName is the database schema considered for migration.
 	schemaName string
@@ -144,6 +209,12 @@ type AutoMigrator struct {
 	// diffOpts are passed to detector constructor.
 	diffOpts []diffOption
 
+	// allowedSafety is the most dangerous class of operations that migrations may include.
+	allowedSafety Safety
+
+	online      bool          // online enables lock-friendly migrations, see WithOnlineMigrations.
+	lockTimeout time.Duration // lockTimeout is set for the session which applies online migrations.
+
 	// migratorOpts are passed to Migrator constructor.
 	migratorOpts []MigratorOption
 
@@ -154,15 +225,20 @@ type AutoMigrator struct {
 func NewAutoMigrator(db *bun.DB, opts ...AutoMigratorOption) (*AutoMigrator, error) {
 	am := &AutoMigrator{
 		db:         db,
-		table:      defaultTable,
-		locksTable: defaultLocksTable,
 		schemaName: db.Dialect().DefaultSchema(),
+
+		allowedSafety: DestructiveChange,
 	}
 
 	for _, opt := range opts {
 		opt(am)
 	}
-	am.excludeTables = append(am.excludeTables, am.table, am.locksTable)
+	// Exclude the migrations, locks, cursors and leases tables under their configured names.
+	am.excludeTables = append(am.excludeTables, NewMigrator(db, NewMigrations(), am.migratorOpts...).ownTables()...)
+
+	if am.online && !db.Dialect().Features().Has(feature.OnlineSchemaChange) {
+		return nil, fmt.Errorf("%q dialect does not support online migrations", db.Dialect().Name())
+	}
 
 	dbInspector, err := sqlschema.NewInspector(db,
 		sqlschema.WithSchemaName(am.schemaName),
@@ -173,13 +249,15 @@ func NewAutoMigrator(db *bun.DB, opts ...AutoMigratorOption) (*AutoMigrator, err
 		return nil, err
 	}
 	am.dbInspector = dbInspector
-	am.diffOpts = append(am.diffOpts, withCompareTypeFunc(db.Dialect().(sqlschema.InspectorDialect).CompareType))
+	am.diffOpts = append(am.diffOpts,
+		withCompareTypeFunc(db.Dialect().(sqlschema.InspectorDialect).CompareType),
+		withFeatures(db.Dialect().Features()),
+	)
 
-	dbMigrator, err := sqlschema.NewMigrator(db, am.schemaName)
-	if err != nil {
+	// Check that the dialect can generate ALTER TABLE queries, see newDBMigrator.
+	if _, err := sqlschema.NewMigrator(db, am.schemaName); err != nil {
 		return nil, err
 	}
-	am.dbMigrator = dbMigrator
 
 	tables := schema.NewTables(db.Dialect())
 	tables.Register(am.includeModels...)
@@ -188,24 +266,94 @@ func NewAutoMigrator(db *bun.DB, opts ...AutoMigratorOption) (*AutoMigrator, err
 	return am, nil
 }
 
-func (am *AutoMigrator) plan(ctx context.Context) (*changeset, error) {
-	var err error
+// newDBMigrator creates a migrator that generates ALTER TABLE queries.
+// Dialects may keep track of the operations their migrator has processed
+// (e.g. SQLite needs to know the full table definition to alter it),
+// so each sequence of operations should use a new instance.
+//
+// A non-nil state provides the table definitions in place of the database, see WithSnapshot.
+func (am *AutoMigrator) newDBMigrator(state sqlschema.Database) sqlschema.Migrator {
+	m, err := sqlschema.NewMigrator(am.db, am.schemaName)
+	if err != nil {
+		// NewAutoMigrator has already checked that the dialect implements sqlschema.MigratorDialect.
+		panic(err)
+	}
+	if t, ok := m.(sqlschema.DatabaseTracker); ok && state != nil {
+		t.TrackDatabase(state)
+	}
+	return m
+}
 
-	got, err := am.dbInspector.Inspect(ctx)
+// plan returns the changes which bring the current state to the one defined by the models,
+// together with both states.
+func (am *AutoMigrator) plan(ctx context.Context) (_ *changeset, got, want sqlschema.Database, err error) {
+	got, err = am.currentState(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	want, err := am.modelInspector.Inspect(ctx)
+	want, err = am.modelInspector.Inspect(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	changes := diff(got, want, am.diffOpts...)
 	if err := changes.ResolveDependencies(); err != nil {
-		return nil, fmt.Errorf("plan migrations: %w", err)
+		return nil, nil, nil, fmt.Errorf("plan migrations: %w", err)
+	}
+	return changes, got, want, nil
+}
+
+// currentState inspects the database or reads the snapshot if one is configured.
+func (am *AutoMigrator) currentState(ctx context.Context) (sqlschema.Database, error) {
+	if am.snapshotPath == "" {
+		return am.dbInspector.Inspect(ctx)
+	}
+
+	f, err := os.Open(am.snapshotPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return sqlschema.BaseDatabase{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return sqlschema.ReadSnapshot(f)
+}
+
+// WriteSnapshot saves the schema defined by the models to the file, see WithSnapshot.
+// Use it to create the initial snapshot for the models whose tables already exist.
+func (am *AutoMigrator) WriteSnapshot(ctx context.Context, path string) error {
+	state, err := am.modelInspector.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+	return writeSnapshotFile(path, state)
+}
+
+func writeSnapshotFile(path string, state sqlschema.Database) error {
+	var buf bytes.Buffer
+	if err := sqlschema.WriteSnapshot(&buf, state); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Plan detects the changes between the models and the database schema and returns them
+// without creating migration files or applying them. Use it for a dry run, e.g. to fail
+// a CI job if the changes include destructive operations.
+//
+// Plan does not enforce the safety policy.
+func (am *AutoMigrator) Plan(ctx context.Context) (*Plan, error) {
+	changes, _, _, err := am.plan(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return changes, nil
+	if am.online {
+		changes = onlineChanges(changes, newTables(changes))
+	}
+	return newPlan(changes), nil
 }
 
 // Migrate writes required changes to a new migration file and runs the migration.
@@ -257,53 +405,140 @@ func (am *AutoMigrator) CreateTxSQLMigrations(ctx context.Context) ([]*Migration
 var errNothingToMigrate = errors.New("nothing to migrate")
 
 func (am *AutoMigrator) createSQLMigrations(ctx context.Context, transactional bool) (*Migrations, []*MigrationFile, error) {
-	changes, err := am.plan(ctx)
+	changes, got, want, err := am.plan(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create sql migrations: %w", err)
 	}
 
+	// The database is not inspected when the changes are detected from the snapshot.
+	var state sqlschema.Database
+	if am.snapshotPath != "" {
+		state = got
+	}
+
 	if changes.Len() == 0 {
 		return nil, nil, errNothingToMigrate
 	}
 
-	name, _ := genMigrationName(am.schemaName + "_auto")
+	if unsafe := newPlan(changes).Unsafe(am.allowedSafety); len(unsafe) > 0 {
+		return nil, nil, &UnsafeChangesError{Allowed: am.allowedSafety, Operations: unsafe}
+	}
+
+	// Tables created or dropped by the migration are altered as usual in online mode.
+	skip := newTables(changes)
+
+	groups := []*changeset{changes}
+	if am.online && transactional {
+		groups = splitTransactional(changes, skip)
+	}
+
+	now := time.Now()
 	migrations := NewMigrations(am.migrationsOpts...)
-	migrations.Add(Migration{
-		Name:    name,
-		Up:      wrapGoMigrationFunc(changes.Up(am.dbMigrator)),
-		Down:    wrapGoMigrationFunc(changes.Down(am.dbMigrator)),
-		Comment: "Changes detected by bun.AutoMigrator",
-	})
+	var files []*MigrationFile
+	for i, group := range groups {
+		// Migrations are applied in the order of their versions,
+		// so each group of changes gets the next one.
+		name, _ := genMigrationNameAt(am.schemaName+"_auto", now.Add(time.Duration(i)*time.Second))
+		migrations.Add(Migration{
+			Name:    name,
+			Up:      wrapGoMigrationFunc(am.migrationFunc(group, skip, am.newDBMigrator(nil))),
+			Down:    wrapGoMigrationFunc(am.migrationFunc(group.GetReverse(), skip, am.newDBMigrator(nil))),
+			Comment: "Changes detected by bun.AutoMigrator",
+		})
+
+		up, down := group, group.GetReverse()
+		if am.online {
+			up, down = onlineChanges(up, skip), onlineChanges(down, skip)
+		}
 
-	// Append .tx.up.sql or .up.sql to migration name, depending if it should be transactional.
-	fname := func(direction string) string {
-		return name + map[bool]string{true: ".tx.", false: "."}[transactional] + direction + ".sql"
+		// Down migration reverts the changes applied by the up migration,
+		// so the same migrator should be used to generate both of them.
+		dbMigrator := am.newDBMigrator(state)
+
+		upFile, err := am.createSQL(ctx, migrations, name, "up", up, dbMigrator, transactional)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create sql migration up: %w", err)
+		}
+
+		downFile, err := am.createSQL(ctx, migrations, name, "down", down, dbMigrator, transactional)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create sql migration down: %w", err)
+		}
+		files = append(files, upFile, downFile)
 	}
 
-	up, err := am.createSQL(ctx, migrations, fname("up"), changes, transactional)
-	if err != nil {
-		return nil, nil, fmt.Errorf("create sql migration up: %w", err)
+	if am.snapshotPath != "" {
+		if err := writeSnapshotFile(am.snapshotPath, want); err != nil {
+			return nil, nil, fmt.Errorf("write snapshot: %w", err)
+		}
 	}
+	return migrations, files, nil
+}
 
-	down, err := am.createSQL(ctx, migrations, fname("down"), changes.GetReverse(), transactional)
-	if err != nil {
-		return nil, nil, fmt.Errorf("create sql migration down: %w", err)
+// migrationFunc creates a MigrationFunc that applies the changes.
+// In online mode, the changes are applied on a single connection with the lock timeout set.
+func (am *AutoMigrator) migrationFunc(changes *changeset, skip map[string]bool, m sqlschema.Migrator) MigrationFunc {
+	if !am.online {
+		return changes.Func(m)
+	}
+
+	changes = am.withLockTimeout(onlineChanges(changes, skip))
+	return func(ctx context.Context, db *bun.DB) error {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if err := changes.apply(ctx, conn, m); err != nil {
+			if am.lockTimeout != 0 {
+				// Do not return the connection to the pool with the lock timeout still set.
+				reset := &changeset{operations: []Operation{&SetLockTimeoutOp{}}}
+				_ = reset.apply(ctx, conn, m)
+			}
+			return err
+		}
+		return nil
 	}
-	return migrations, []*MigrationFile{up, down}, nil
 }
 
-func (am *AutoMigrator) createSQL(_ context.Context, migrations *Migrations, fname string, changes *changeset, transactional bool) (*MigrationFile, error) {
+// withLockTimeout wraps the changes in the statements which set and reset the lock timeout.
+func (am *AutoMigrator) withLockTimeout(changes *changeset) *changeset {
+	if am.lockTimeout == 0 {
+		return changes
+	}
+	var wrapped changeset
+	wrapped.Add(&SetLockTimeoutOp{Timeout: am.lockTimeout})
+	wrapped.Add(changes.operations...)
+	wrapped.Add(&SetLockTimeoutOp{})
+	return &wrapped
+}
+
+func (am *AutoMigrator) createSQL(_ context.Context, migrations *Migrations, name, direction string, changes *changeset, dbMigrator sqlschema.Migrator, transactional bool) (*MigrationFile, error) {
 	var buf bytes.Buffer
 
-	if transactional {
+	// Only PostgreSQL has statement_timeout, which is disabled for the whole transaction.
+	if transactional && am.db.Dialect().Name() == dialect.PG {
 		buf.WriteString("SET statement_timeout = 0;")
 	}
 
-	if err := changes.WriteTo(&buf, am.dbMigrator); err != nil {
+	if am.online {
+		// Multiple statements sent in one query are executed in an implicit transaction,
+		// which would hold the locks acquired by each of them until the last one completes
+		// and fail on CREATE INDEX CONCURRENTLY. Split them into separate queries instead.
+		if err := am.withLockTimeout(changes).writeTo(&buf, dbMigrator, ";\n--bun:split\n"); err != nil {
+			return nil, err
+		}
+	} else if err := changes.WriteTo(&buf, dbMigrator); err != nil {
 		return nil, err
 	}
 	content := buf.Bytes()
 
+	// Append .tx.up.sql or .up.sql to migration name, depending if it should be transactional.
+	// The migrator reports which operations cannot run in a transaction once the SQL is generated.
+	tx := transactional && changes.transactional(dbMigrator)
+	fname := name + map[bool]string{true: ".tx.", false: "."}[tx] + direction + ".sql"
+
 	fpath := filepath.Join(migrations.getDirectory(), fname)
 	if err := os.WriteFile(fpath, content, 0o644); err != nil {
 		return nil, err
@@ -348,7 +583,7 @@ func (c *changeset) Down(m sqlschema.Migrator) MigrationFunc {
 }
 
 // apply generates SQL for each operation and executes it.
-func (c *changeset) apply(ctx context.Context, db *bun.DB, m sqlschema.Migrator) error {
+func (c *changeset) apply(ctx context.Context, db bun.IConn, m sqlschema.Migrator) error {
 	if len(c.operations) == 0 {
 		return nil
 	}
@@ -373,6 +608,11 @@ func (c *changeset) apply(ctx context.Context, db *bun.DB, m sqlschema.Migrator)
 }
 
 func (c *changeset) WriteTo(w io.Writer, m sqlschema.Migrator) error {
+	return c.writeTo(w, m, ";\n")
+}
+
+// writeTo writes the SQL for each operation followed by the separator.
+func (c *changeset) writeTo(w io.Writer, m sqlschema.Migrator, sep string) error {
 	var err error
 
 	b := internal.MakeQueryBytes()
@@ -393,7 +633,7 @@ func (c *changeset) WriteTo(w io.Writer, m sqlschema.Migrator) error {
 			return fmt.Errorf("write changeset: %w", err)
 		}
 		b = append(b, queryBytes...)
-		b = append(b, ";\n"...)
+		b = append(b, sep...)
 	}
 	if _, err := w.Write(b); err != nil {
 		return fmt.Errorf("write changeset: %w", err)
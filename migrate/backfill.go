@@ -0,0 +1,269 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	defaultCursorsTable = "bun_migration_cursors"
+	defaultBatchSize    = 1000
+)
+
+// WithCursorsTableName overrides default table name for the cursors of data migrations, see Backfill.
+func WithCursorsTableName(table string) MigratorOption {
+	return func(m *Migrator) {
+		m.cursorsTable = table
+	}
+}
+
+// DataMigration is a migration which manages its own transactions, e.g. Backfill.
+// Use Migrations.RegisterData to register it.
+type DataMigration interface {
+	Up(ctx context.Context, migrator *Migrator, migration *Migration) error
+	Down(ctx context.Context, migrator *Migrator, migration *Migration) error
+}
+
+// RegisterData registers the data migration with a name derived from the caller's file name,
+// the same way Register does.
+func (m *Migrations) RegisterData(data DataMigration) error {
+	fpath := migrationFile()
+	name, comment, err := extractMigrationName(fpath)
+	if err != nil {
+		return err
+	}
+
+	m.Add(Migration{
+		Name:    name,
+		Comment: comment,
+		Up:      wrapDataMigrationFunc(data.Up),
+		Down:    wrapDataMigrationFunc(data.Down),
+	})
+
+	return nil
+}
+
+func (m *Migrations) MustRegisterData(data DataMigration) {
+	if err := m.RegisterData(data); err != nil {
+		panic(err)
+	}
+}
+
+func wrapDataMigrationFunc(fn internalMigrationFunc) internalMigrationFunc {
+	return func(ctx context.Context, migrator *Migrator, migration *Migration) error {
+		if migrator.beforeMigrationHook != nil {
+			if err := migrator.beforeMigrationHook(ctx, migrator.db, migration); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(ctx, migrator, migration); err != nil {
+			return err
+		}
+
+		if migrator.afterMigrationHook != nil {
+			if err := migrator.afterMigrationHook(ctx, migrator.db, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// BackfillProgress is reported after each batch of a Backfill is committed.
+type BackfillProgress struct {
+	// Migration is the name of the data migration.
+	Migration string
+	// Batch is the number of batches processed by this run.
+	Batch int
+	// Rows is the number of rows in the batch.
+	Rows int
+	// Processed is the total number of rows processed, including the previous runs.
+	Processed int64
+	// Done is true once all rows have been processed.
+	Done bool
+}
+
+type BackfillHook func(ctx context.Context, progress BackfillProgress)
+
+// Backfill is a data migration which processes the rows of the model T in batches,
+// ordered by the Key column (keyset pagination). Each batch is processed and committed
+// in a separate transaction together with the cursor, i.e. the key of the last row
+// processed, so a backfill interrupted by a crash resumes after the last committed batch
+// without processing any rows again.
+//
+// The key column must be unique and not null, e.g. the primary key.
+//
+// Register the backfill with Migrations.MustRegisterData. Use WithMarkAppliedOnSuccess,
+// so that the migration is not marked as applied until all rows are processed
+// and the next Migrate resumes a failed backfill.
+type Backfill[T any] struct {
+	// Query adds conditions to the query which selects the rows to process, e.g. WHERE.
+	// The query is already ordered by the key and limited to the batch size.
+	Query func(q *bun.SelectQuery) *bun.SelectQuery
+
+	// Process processes a batch of rows in the transaction.
+	Process func(ctx context.Context, tx bun.Tx, rows []T) error
+
+	// Key is the column used to paginate the rows, "id" by default.
+	Key string
+
+	// BatchSize is the maximum number of rows in a batch, 1000 by default.
+	BatchSize int
+
+	// Progress is called after each batch is committed.
+	Progress BackfillHook
+}
+
+var _ DataMigration = (*Backfill[struct{}])(nil)
+
+type migrationCursor struct {
+	ID            int64  `bun:",pk,autoincrement"`
+	MigrationName string `bun:",unique"`
+
+	// Cursor is the key of the last processed row formatted as an SQL literal.
+	Cursor    string
+	Processed int64
+	UpdatedAt time.Time `bun:",notnull"`
+}
+
+// Up processes the rows which have not been processed yet.
+func (b *Backfill[T]) Up(ctx context.Context, m *Migrator, migration *Migration) error {
+	if b.Process == nil {
+		return errors.New("migrate: backfill: Process is required")
+	}
+
+	key := b.Key
+	if key == "" {
+		key = "id"
+	}
+	batchSize := b.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	table := m.db.Table(reflect.TypeFor[T]())
+	field, err := table.Field(key)
+	if err != nil {
+		return fmt.Errorf("migrate: backfill: %w", err)
+	}
+
+	if err := m.initCursors(ctx); err != nil {
+		return err
+	}
+
+	cursor := &migrationCursor{MigrationName: migration.Name}
+	if err := m.db.NewSelect().
+		ColumnExpr("*").
+		Model(cursor).
+		ModelTableExpr(m.cursorsTable).
+		Where("? = ?", bun.Ident("migration_name"), migration.Name).
+		Scan(ctx); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	for batch := 1; ; batch++ {
+		var rows []T
+		if err := m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			q := tx.NewSelect().Model(&rows)
+			if b.Query != nil {
+				q = b.Query(q)
+			}
+			if cursor.Cursor != "" {
+				q = q.Where("?TableAlias.? > ?", bun.Ident(key), bun.Safe(cursor.Cursor))
+			}
+			if err := q.OrderExpr("?TableAlias.? ASC", bun.Ident(key)).
+				Limit(batchSize).
+				Scan(ctx); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+
+			if err := b.Process(ctx, tx, rows); err != nil {
+				return err
+			}
+
+			last := reflect.ValueOf(&rows[len(rows)-1]).Elem()
+			next := *cursor
+			next.Cursor = string(field.AppendValue(m.db.QueryGen(), nil, last))
+			next.Processed += int64(len(rows))
+			next.UpdatedAt = time.Now()
+			if err := m.saveCursor(ctx, tx, &next); err != nil {
+				return err
+			}
+			*cursor = next
+			return nil
+		}); err != nil {
+			return fmt.Errorf("migrate: backfill %s: %w", migration.Name, err)
+		}
+
+		done := len(rows) < batchSize
+		if b.Progress != nil && (len(rows) > 0 || done) {
+			b.Progress(ctx, BackfillProgress{
+				Migration: migration.Name,
+				Batch:     batch,
+				Rows:      len(rows),
+				Processed: cursor.Processed,
+				Done:      done,
+			})
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// saveCursor updates the cursor of the data migration or inserts it if it does not exist yet.
+func (m *Migrator) saveCursor(ctx context.Context, tx bun.Tx, cursor *migrationCursor) error {
+	res, err := tx.NewUpdate().
+		Model(cursor).
+		ModelTableExpr(m.cursorsTable).
+		Column("cursor", "processed", "updated_at").
+		Where("? = ?", bun.Ident("migration_name"), cursor.MigrationName).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	_, err = tx.NewInsert().
+		Model(cursor).
+		ModelTableExpr(m.cursorsTable).
+		Exec(ctx)
+	return err
+}
+
+// Down resets the progress of the backfill, so that all rows are processed again
+// when the migration is re-applied. It does not revert the changes made to the rows.
+func (b *Backfill[T]) Down(ctx context.Context, m *Migrator, migration *Migration) error {
+	if err := m.initCursors(ctx); err != nil {
+		return err
+	}
+	_, err := m.db.NewDelete().
+		Model((*migrationCursor)(nil)).
+		ModelTableExpr(m.cursorsTable).
+		Where("? = ?", bun.Ident("migration_name"), migration.Name).
+		Exec(ctx)
+	return err
+}
+
+// initCursors creates the cursors table when a data migration is first run.
+func (m *Migrator) initCursors(ctx context.Context) error {
+	_, err := m.db.NewCreateTable().
+		Model((*migrationCursor)(nil)).
+		ModelTableExpr(m.cursorsTable).
+		IfNotExists().
+		Exec(ctx)
+	return err
+}
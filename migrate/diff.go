@@ -1,6 +1,9 @@
 package migrate
 
 import (
+	"slices"
+
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal/ordered"
 	"github.com/uptrace/bun/migrate/sqlschema"
 )
@@ -26,6 +29,8 @@ func (d *detector) detectChanges() *changeset {
 	currentTables := toOrderedMap(d.current.GetTables())
 	targetTables := toOrderedMap(d.target.GetTables())
 
+	d.detectEnumChanges()
+
 RenameCreate:
 	for _, wantPair := range targetTables.Pairs() {
 		wantName, wantTable := wantPair.Key, wantPair.Value
@@ -35,6 +40,8 @@ RenameCreate:
 		if haveTable, ok := currentTables.Load(wantName); ok {
 			d.detectColumnChanges(haveTable, wantTable, true)
 			d.detectConstraintChanges(haveTable, wantTable)
+			d.detectIndexChanges(haveTable, wantTable)
+			d.detectCheckChanges(haveTable, wantTable)
 			continue
 		}
 
@@ -52,6 +59,8 @@ RenameCreate:
 				// We need not check wantTable any further.
 				d.detectColumnChanges(haveTable, wantTable, false)
 				d.detectConstraintChanges(haveTable, wantTable)
+				d.detectIndexChanges(haveTable, wantTable)
+				d.detectCheckChanges(haveTable, wantTable)
 				currentTables.Delete(haveName)
 				continue RenameCreate
 			}
@@ -64,6 +73,17 @@ RenameCreate:
 			TableName: wantTable.GetName(),
 			Model:     additional.Model,
 		})
+
+		// CREATE TABLE statement does not create indexes or set column comments.
+		for _, index := range wantTable.GetIndexes() {
+			d.changes.Add(&CreateIndexOp{
+				TableName: wantTable.GetName(),
+				Index:     index,
+			})
+		}
+		for _, col := range wantTable.GetColumns() {
+			d.detectCommentChange(wantTable.GetName(), nil, col)
+		}
 	}
 
 	// Drop any remaining "current" tables which do not have a model.
@@ -130,6 +150,7 @@ ChangeRename:
 					To:        d.makeTargetColDef(cCol, tCol),
 				})
 			}
+			d.detectCommentChange(target.GetName(), cCol, tCol)
 			continue
 		}
 
@@ -152,6 +173,13 @@ ChangeRename:
 			// Update primary key definition to avoid superficially recreating the constraint.
 			current.GetPrimaryKey().Columns.Replace(cName, tName)
 
+			// Same goes for the indexes.
+			for _, index := range current.GetIndexes() {
+				replaceColumn(index.Columns, cName, tName)
+				replaceColumn(index.Include, cName, tName)
+			}
+
+			d.detectCommentChange(target.GetName(), cCol, tCol)
 			continue ChangeRename
 		}
 
@@ -160,6 +188,7 @@ ChangeRename:
 			ColumnName: tName,
 			Column:     tCol,
 		})
+		d.detectCommentChange(target.GetName(), nil, tCol)
 	}
 
 	// Drop columns which do not exist in the target schema and were not renamed.
@@ -230,6 +259,144 @@ Drop:
 	}
 }
 
+// detectIndexChanges finds indexes which need to be created or dropped.
+// Changing an index definition requires re-creating it.
+func (d *detector) detectIndexChanges(current, target sqlschema.Table) {
+Create:
+	for _, want := range target.GetIndexes() {
+		for _, got := range current.GetIndexes() {
+			if got.Equals(want) {
+				continue Create
+			}
+		}
+		d.changes.Add(&CreateIndexOp{
+			TableName: target.GetName(),
+			Index:     want,
+		})
+	}
+
+Drop:
+	for _, got := range current.GetIndexes() {
+		for _, want := range target.GetIndexes() {
+			if got.Equals(want) {
+				continue Drop
+			}
+		}
+		d.changes.Add(&DropIndexOp{
+			TableName: target.GetName(),
+			Index:     got,
+		})
+	}
+}
+
+// detectCheckChanges finds CHECK constraints which need to be added or dropped.
+// Changing a constraint's expression requires re-creating it.
+func (d *detector) detectCheckChanges(current, target sqlschema.Table) {
+	if !d.features.Has(feature.CheckConstraint) {
+		return
+	}
+
+Add:
+	for _, want := range target.GetChecks() {
+		for _, got := range current.GetChecks() {
+			if got.Equals(want) {
+				continue Add
+			}
+		}
+		d.changes.Add(&AddCheckOp{
+			TableName: target.GetName(),
+			Check:     want,
+		})
+	}
+
+Drop:
+	for _, got := range current.GetChecks() {
+		for _, want := range target.GetChecks() {
+			if got.Equals(want) {
+				continue Drop
+			}
+		}
+		d.changes.Add(&DropCheckOp{
+			TableName: target.GetName(),
+			Check:     got,
+		})
+	}
+}
+
+// detectCommentChange sets a new comment on the column if it differs from the current one.
+// Pass nil for the current column if the column is being created.
+func (d *detector) detectCommentChange(tableName string, current, target sqlschema.Column) {
+	if !d.features.Has(feature.CommentOnColumn) {
+		return
+	}
+
+	var oldComment string
+	if current != nil {
+		oldComment = current.GetComment()
+	}
+	if target.GetComment() == oldComment {
+		return
+	}
+	d.changes.Add(&SetCommentOp{
+		TableName:  tableName,
+		ColumnName: target.GetName(),
+		Comment:    target.GetComment(),
+		OldComment: oldComment,
+	})
+}
+
+// detectEnumChanges finds enum types which need to be created or dropped and new enum values.
+// Values which were removed from the model are left in the database, because removing them
+// is not supported.
+func (d *detector) detectEnumChanges() {
+	if !d.features.Has(feature.EnumType) {
+		return
+	}
+
+	currentEnums := toOrderedMap(d.current.GetEnums())
+	targetEnums := toOrderedMap(d.target.GetEnums())
+
+	for _, tPair := range targetEnums.Pairs() {
+		name, want := tPair.Key, tPair.Value
+		got, ok := currentEnums.Load(name)
+		if !ok {
+			d.changes.Add(&CreateEnumOp{Enum: want})
+			continue
+		}
+
+		// Place each new value after the one preceding it in the model, or before
+		// the first existing value if no value precedes it.
+		for i, value := range want.Values {
+			if slices.Contains(got.Values, value) {
+				continue
+			}
+			add := &AlterEnumAddValueOp{EnumName: name, Value: value}
+			switch {
+			case i > 0:
+				add.After = want.Values[i-1]
+			case len(got.Values) > 0:
+				add.Before = got.Values[0]
+			}
+			d.changes.Add(add)
+		}
+	}
+
+	for _, cPair := range currentEnums.Pairs() {
+		if _, keep := targetEnums.Load(cPair.Key); !keep {
+			d.changes.Add(&DropEnumOp{Enum: cPair.Value})
+		}
+	}
+}
+
+// replaceColumn renames the column in the list of column names in place.
+func replaceColumn(columns []string, oldColumn, newColumn string) {
+	for i := range columns {
+		if columns[i] == oldColumn {
+			columns[i] = newColumn
+		}
+	}
+}
+
 func newDetector(got, want sqlschema.Database, opts ...diffOption) *detector {
 	cfg := &detectorConfig{
 		cmpType: func(c1, c2 sqlschema.Column) bool {
@@ -241,10 +408,11 @@ func newDetector(got, want sqlschema.Database, opts ...diffOption) *detector {
 	}
 
 	return &detector{
-		current: got,
-		target:  want,
-		refMap:  newRefMap(got.GetForeignKeys()),
-		cmpType: cfg.cmpType,
+		current:  got,
+		target:   want,
+		refMap:   newRefMap(got.GetForeignKeys()),
+		cmpType:  cfg.cmpType,
+		features: cfg.features,
 	}
 }
 
@@ -256,9 +424,18 @@ func withCompareTypeFunc(f CompareTypeFunc) diffOption {
 	}
 }
 
+// withFeatures enables detection of the schema objects supported by the dialect,
+// e.g. CHECK constraints, enum types and column comments.
+func withFeatures(features feature.Feature) diffOption {
+	return func(cfg *detectorConfig) {
+		cfg.features = features
+	}
+}
+
 // detectorConfig controls how differences in the model states are resolved.
 type detectorConfig struct {
-	cmpType CompareTypeFunc
+	cmpType  CompareTypeFunc
+	features feature.Feature
 }
 
 // detector may modify the passed database schemas, so it isn't safe to re-use them.
@@ -277,6 +454,10 @@ type detector struct {
 	// due to the existence of dialect-specific type aliases. The caller
 	// should pass a concrete InspectorDialect.EquivalentType for robust comparison.
 	cmpType CompareTypeFunc
+
+	// features determine which schema objects are compared in addition to tables,
+	// columns, constraints and indexes, which are supported by all dialects.
+	features feature.Feature
 }
 
 // canRename checks if t1 can be renamed to t2.
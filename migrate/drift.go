@@ -0,0 +1,130 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Drift describes the differences between the applied migrations and the registered ones.
+type Drift struct {
+	// Modified are the applied migrations which have been changed since, i.e. their checksum
+	// does not match the one recorded when they were applied.
+	Modified MigrationSlice
+
+	// Missing are the applied migrations which can no longer be found.
+	Missing MigrationSlice
+
+	// OutOfOrder are the migrations which are (or will be) applied after a migration
+	// with a later version: applied migrations which were run after a later one
+	// and unapplied migrations older than the last applied migration.
+	OutOfOrder MigrationSlice
+}
+
+// IsZero returns true if the applied migrations match the registered ones.
+func (d *Drift) IsZero() bool {
+	return d == nil || len(d.Modified) == 0 && len(d.Missing) == 0 && len(d.OutOfOrder) == 0
+}
+
+func (d *Drift) String() string {
+	if d.IsZero() {
+		return "no drift"
+	}
+
+	var parts []string
+	if len(d.Modified) > 0 {
+		parts = append(parts, fmt.Sprintf("modified: %s", d.Modified))
+	}
+	if len(d.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing: %s", d.Missing))
+	}
+	if len(d.OutOfOrder) > 0 {
+		parts = append(parts, fmt.Sprintf("out of order: %s", d.OutOfOrder))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DriftError is returned by Migrate when the migrations have drifted, see WithDriftCheck.
+type DriftError struct {
+	Drift *Drift
+}
+
+func (e *DriftError) Error() string {
+	return fmt.Sprintf("migrate: applied migrations have drifted (%s)", e.Drift)
+}
+
+// Validate compares the applied migrations with the registered ones and reports
+// applied migrations that were modified or can no longer be found, as well as
+// the migrations that are applied out of order.
+//
+// Only migrations with a checksum can be checked for modifications, see Migration.Checksum.
+func (m *Migrator) Validate(ctx context.Context) (*Drift, error) {
+	applied, err := m.AppliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := new(Drift)
+	existing := migrationMap(m.migrations.ms)
+	squashed := make(map[string]bool)
+	for _, migration := range m.migrations.ms {
+		for _, name := range migration.Squashed {
+			squashed[name] = true
+		}
+	}
+	for i := range applied {
+		migration := &applied[i]
+		found, ok := existing[migration.Name]
+		if !ok {
+			// Migrations replaced by a baseline are expected to be removed.
+			if migration.SquashedInto == "" && !squashed[migration.Name] {
+				drift.Missing = append(drift.Missing, *migration)
+			}
+			continue
+		}
+		// A baseline takes the name of the last migration it replaced, whose checksum
+		// has been recorded instead of the baseline's one unless Squash was run on this database.
+		if len(found.Squashed) > 0 && migration.SquashedInto == "" {
+			continue
+		}
+		if migration.Checksum != "" && found.Checksum != "" && migration.Checksum != found.Checksum {
+			drift.Modified = append(drift.Modified, *migration)
+		}
+	}
+	sortAsc(drift.Missing)
+	sortAsc(drift.Modified)
+
+	// Migrations are applied in ascending order, so a migration with a greater ID
+	// than the one of a later migration was applied after it.
+	sortDesc(applied)
+	var minID int64
+	for i := range applied {
+		migration := &applied[i]
+		if i > 0 && migration.ID > minID {
+			drift.OutOfOrder = append(drift.OutOfOrder, *migration)
+			continue
+		}
+		minID = migration.ID
+	}
+
+	// Unapplied migrations older than the last applied one will be applied out of order.
+	if len(applied) > 0 {
+		appliedMap := migrationMap(applied)
+		for _, migration := range m.migrations.Sorted() {
+			if _, ok := appliedMap[migration.Name]; !ok && migrationLess(migration, applied[0]) {
+				drift.OutOfOrder = append(drift.OutOfOrder, migration)
+			}
+		}
+	}
+	sortAsc(drift.OutOfOrder)
+
+	return drift, nil
+}
+
+// checksum returns the hex-encoded SHA-256 hash of the migration file contents.
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,426 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// ErrLocked is returned by Locker.Lock when the lock is held by someone else.
+var ErrLocked = errors.New("migrate: migrations table is already locked")
+
+// ErrLeaseLost is returned by LeaseLocker.Renew when the lease has expired and
+// the lock has been acquired by someone else or released.
+var ErrLeaseLost = errors.New("migrate: migrations lock lease is lost")
+
+// Locker prevents migrations from being applied concurrently.
+//
+// Lockers which need to create tables before they can be used
+// may implement { Init(context.Context) error }, which Migrator.Init will call.
+type Locker interface {
+	// Lock acquires the lock without waiting. It returns an error wrapping ErrLocked
+	// if the lock is held by someone else.
+	Lock(ctx context.Context) error
+
+	// Unlock releases the lock.
+	Unlock(ctx context.Context) error
+}
+
+// LeaseRenewer is implemented by lockers which hold the lock for a limited time.
+// RunLocked renews the lease while the locked function runs.
+type LeaseRenewer interface {
+	Locker
+
+	// Renew extends the lease or returns ErrLeaseLost.
+	Renew(ctx context.Context) error
+
+	// LeaseTTL is the time the lock is held for without renewal.
+	LeaseTTL() time.Duration
+}
+
+// lockRetryInterval is the delay between attempts to acquire a lock held by someone else.
+const lockRetryInterval = 500 * time.Millisecond
+
+// minRenewInterval bounds how often RunLocked renews very short leases.
+const minRenewInterval = time.Millisecond
+
+// RunLocked acquires the migrations lock, runs fn and releases the lock,
+// even if fn returns an error or ctx is canceled.
+//
+// If the lock is held by someone else, RunLocked retries until the lock timeout expires,
+// see WithLockTimeout. Leases (see LeaseRenewer) are renewed while fn runs;
+// if the lease is lost, the context passed to fn is canceled and RunLocked
+// returns the renewal error, whatever fn returned.
+func (m *Migrator) RunLocked(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		// Release the lock even if the context has been canceled.
+		if unlockErr := m.locker.Unlock(context.WithoutCancel(ctx)); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	renewer, ok := m.locker.(LeaseRenewer)
+	if !ok {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	interval := renewer.LeaseTTL() / 3
+	if interval < minRenewInterval {
+		interval = minRenewInterval
+	}
+
+	var renewErr error
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := renewer.Renew(ctx); err != nil {
+					renewErr = err
+					cancel(err)
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(ctx)
+	close(done)
+	<-stopped
+
+	if renewErr != nil {
+		return errors.Join(fmt.Errorf("migrate: renew lock: %w", renewErr), err)
+	}
+	return err
+}
+
+// acquireLock tries to acquire the lock until the lock timeout expires.
+func (m *Migrator) acquireLock(ctx context.Context) error {
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		err := m.locker.Lock(ctx)
+		if err == nil || !errors.Is(err, ErrLocked) || !time.Now().Before(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type migrationLock struct {
+	ID        int64  `bun:",pk,autoincrement"`
+	TableName string `bun:",unique"`
+}
+
+// tableLocker inserts a row into the locks table to acquire the lock.
+// The lock is held until it is released explicitly, even if the process crashes.
+type tableLocker struct {
+	db         *bun.DB
+	name       string
+	locksTable string
+}
+
+func (l *tableLocker) Lock(ctx context.Context) error {
+	lock := &migrationLock{
+		TableName: l.name,
+	}
+	if _, err := l.db.NewInsert().
+		Model(lock).
+		ModelTableExpr(l.locksTable).
+		Exec(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w (%w)", ErrLocked, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether the error is caused by a duplicate key, i.e. the lock is held.
+// The drivers report it differently and migrate does not depend on any of them, so the error message is checked.
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"23505",                    // PostgreSQL
+		"duplicate entry",          // MySQL
+		"unique constraint failed", // SQLite
+		"duplicate key",            // SQL Server
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *tableLocker) Unlock(ctx context.Context) error {
+	_, err := l.db.NewDelete().
+		Model((*migrationLock)(nil)).
+		ModelTableExpr(l.locksTable).
+		Where("? = ?", bun.Ident("table_name"), l.name).
+		Exec(ctx)
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// AdvisoryLocker uses database advisory locks: pg_try_advisory_lock in Postgres
+// and GET_LOCK in MySQL. The lock is held by a dedicated connection, so it is released
+// by the database as soon as the connection is closed, e.g. if the process crashes.
+type AdvisoryLocker struct {
+	db   *bun.DB
+	name string
+
+	mu   sync.Mutex
+	conn *bun.Conn
+}
+
+var _ Locker = (*AdvisoryLocker)(nil)
+
+// NewAdvisoryLocker creates a locker which uses the advisory lock with the name.
+func NewAdvisoryLocker(db *bun.DB, name string) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db, name: name}
+}
+
+func (l *AdvisoryLocker) Lock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		return fmt.Errorf("%w (lock %q is already held by this locker)", ErrLocked, l.name)
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var acquired bool
+	switch name := l.db.Dialect().Name(); name {
+	case dialect.PG:
+		err = conn.NewRaw("SELECT pg_try_advisory_lock(?)", l.key()).Scan(ctx, &acquired)
+	case dialect.MySQL:
+		// GET_LOCK returns NULL on errors, which is scanned as false.
+		var res *int
+		err = conn.NewRaw("SELECT GET_LOCK(?, 0)", l.name).Scan(ctx, &res)
+		acquired = res != nil && *res == 1
+	default:
+		err = fmt.Errorf("migrate: advisory locks are not supported by %s", name)
+	}
+	if err == nil && !acquired {
+		err = fmt.Errorf("%w (advisory lock %q is held by another session)", ErrLocked, l.name)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	l.conn = &conn
+	return nil
+}
+
+func (l *AdvisoryLocker) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.conn = nil
+
+	var err error
+	switch l.db.Dialect().Name() {
+	case dialect.PG:
+		_, err = conn.ExecContext(ctx, "SELECT pg_advisory_unlock(?)", l.key())
+	case dialect.MySQL:
+		_, err = conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", l.name)
+	}
+
+	// Closing the connection releases the lock anyway.
+	if closeErr := conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// key converts the lock name to the bigint key of the Postgres advisory lock.
+func (l *AdvisoryLocker) key() int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(l.name))
+	return int64(h.Sum64())
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	defaultLeasesTable = "bun_migration_leases"
+	defaultLeaseTTL    = time.Minute
+)
+
+type migrationLease struct {
+	ID        int64     `bun:",pk,autoincrement"`
+	TableName string    `bun:",unique"`
+	Owner     string    `bun:",notnull"`
+	Host      string    `bun:",notnull"`
+	ExpiresAt time.Time `bun:",notnull"`
+}
+
+type LeaseLockerOption func(l *LeaseLocker)
+
+// WithLeasesTableName overrides default leases table name.
+// AutoMigrator excludes the table from the migration scope only when the locker
+// is passed to it with WithLockerAuto; otherwise exclude it with WithExcludeTable.
+func WithLeasesTableName(table string) LeaseLockerOption {
+	return func(l *LeaseLocker) {
+		l.table = table
+	}
+}
+
+// LeaseLocker holds the lock for a limited time, recording the owner, the host and
+// the expiry time in the leases table. An expired lease can be taken over by another owner,
+// so a lock left by a crashed process is released automatically.
+//
+// The lease must be renewed before it expires, which RunLocked does automatically.
+// Clocks of the hosts running migrations should be synchronized.
+type LeaseLocker struct {
+	db    *bun.DB
+	name  string
+	ttl   time.Duration
+	table string
+
+	owner string
+	host  string
+}
+
+var _ LeaseRenewer = (*LeaseLocker)(nil)
+
+// NewLeaseLocker creates a locker which holds the lock with the name for ttl without renewal.
+// A non-positive ttl is replaced with the default of one minute.
+func NewLeaseLocker(db *bun.DB, name string, ttl time.Duration, opts ...LeaseLockerOption) *LeaseLocker {
+	if ttl <= 0 {
+		ttl = defaultLeaseTTL
+	}
+
+	host, _ := os.Hostname()
+
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+
+	l := &LeaseLocker{
+		db:    db,
+		name:  name,
+		ttl:   ttl,
+		table: defaultLeasesTable,
+		owner: fmt.Sprintf("%d-%s", os.Getpid(), hex.EncodeToString(id[:])),
+		host:  host,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Init creates the leases table.
+func (l *LeaseLocker) Init(ctx context.Context) error {
+	_, err := l.db.NewCreateTable().
+		Model((*migrationLease)(nil)).
+		ModelTableExpr(l.table).
+		IfNotExists().
+		Exec(ctx)
+	return err
+}
+
+func (l *LeaseLocker) Lock(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	// Take over the expired lease.
+	if _, err := l.db.NewDelete().
+		Model((*migrationLease)(nil)).
+		ModelTableExpr(l.table).
+		Where("? = ?", bun.Ident("table_name"), l.name).
+		Where("? < ?", bun.Ident("expires_at"), now).
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	lease := &migrationLease{
+		TableName: l.name,
+		Owner:     l.owner,
+		Host:      l.host,
+		ExpiresAt: now.Add(l.ttl),
+	}
+	if _, err := l.db.NewInsert().
+		Model(lease).
+		ModelTableExpr(l.table).
+		Exec(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w (%w)", ErrLocked, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (l *LeaseLocker) Renew(ctx context.Context) error {
+	res, err := l.db.NewUpdate().
+		Model((*migrationLease)(nil)).
+		ModelTableExpr(l.table).
+		Set("? = ?", bun.Ident("expires_at"), time.Now().UTC().Add(l.ttl)).
+		Where("? = ?", bun.Ident("table_name"), l.name).
+		Where("? = ?", bun.Ident("owner"), l.owner).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (l *LeaseLocker) Unlock(ctx context.Context) error {
+	_, err := l.db.NewDelete().
+		Model((*migrationLease)(nil)).
+		ModelTableExpr(l.table).
+		Where("? = ?", bun.Ident("table_name"), l.name).
+		Where("? = ?", bun.Ident("owner"), l.owner).
+		Exec(ctx)
+	return err
+}
+
+func (l *LeaseLocker) LeaseTTL() time.Duration {
+	return l.ttl
+}
@@ -0,0 +1,437 @@
+// Package migratecli provides ready-made urfave/cli commands to manage migrations
+// with migrate.Migrator and, optionally, to generate migrations with migrate.AutoMigrator.
+//
+// Usage:
+//
+//	app := &cli.App{
+//		Name: "bun",
+//		Commands: []*cli.Command{
+//			migratecli.NewCommand(migrator, migratecli.WithAutoMigrator(autoMigrator)),
+//		},
+//	}
+package migratecli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+type Option func(c *config)
+
+type config struct {
+	name         string
+	autoMigrator *migrate.AutoMigrator
+}
+
+// WithName overrides the name of the command, "db" by default.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithAutoMigrator adds commands which generate migrations from the models.
+func WithAutoMigrator(m *migrate.AutoMigrator) Option {
+	return func(c *config) {
+		c.autoMigrator = m
+	}
+}
+
+// NewCommand creates a command with subcommands to create, apply and roll back migrations.
+func NewCommand(migrator *migrate.Migrator, opts ...Option) *cli.Command {
+	cfg := &config{
+		name: "db",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	cmd := &cli.Command{
+		Name:  cfg.name,
+		Usage: "database migrations",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "init",
+				Usage: "create migration tables",
+				Action: func(c *cli.Context) error {
+					return migrator.Init(c.Context)
+				},
+			},
+			{
+				Name:  "migrate",
+				Usage: "migrate database",
+				Action: func(c *cli.Context) error {
+					return withLock(c, migrator, func() error {
+						group, err := migrator.Migrate(c.Context)
+						if err != nil {
+							return err
+						}
+						if group.IsZero() {
+							fmt.Fprintf(c.App.Writer, "there are no new migrations to run (database is up to date)\n")
+							return nil
+						}
+						fmt.Fprintf(c.App.Writer, "migrated to %s\n", group)
+						return nil
+					})
+				},
+			},
+			{
+				Name:      "migrate_to",
+				Usage:     "migrate database up to and including the migration",
+				ArgsUsage: "<migration>",
+				Action: func(c *cli.Context) error {
+					target, err := targetArg(c)
+					if err != nil {
+						return err
+					}
+					return withLock(c, migrator, func() error {
+						group, err := migrator.MigrateTo(c.Context, target)
+						if err != nil {
+							return err
+						}
+						if group.IsZero() {
+							fmt.Fprintf(c.App.Writer, "there are no new migrations to run up to %s\n", target)
+							return nil
+						}
+						fmt.Fprintf(c.App.Writer, "migrated to %s\n", group)
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "rollback",
+				Usage: "rollback the last migration group",
+				Action: func(c *cli.Context) error {
+					return withLock(c, migrator, func() error {
+						group, err := migrator.Rollback(c.Context)
+						if err != nil {
+							return err
+						}
+						if group.IsZero() {
+							fmt.Fprintf(c.App.Writer, "there are no groups to roll back\n")
+							return nil
+						}
+						fmt.Fprintf(c.App.Writer, "rolled back %s\n", group)
+						return nil
+					})
+				},
+			},
+			{
+				Name:      "rollback_to",
+				Usage:     "rollback the migrations applied after the migration",
+				ArgsUsage: "<migration>",
+				Action: func(c *cli.Context) error {
+					target, err := targetArg(c)
+					if err != nil {
+						return err
+					}
+					return withLock(c, migrator, func() error {
+						ms, err := migrator.RollbackTo(c.Context, target)
+						if err != nil {
+							return err
+						}
+						if len(ms) == 0 {
+							fmt.Fprintf(c.App.Writer, "there are no migrations to roll back to %s\n", target)
+							return nil
+						}
+						fmt.Fprintf(c.App.Writer, "rolled back %s\n", ms)
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "redo",
+				Usage: "rollback the last migration group and apply it again",
+				Action: func(c *cli.Context) error {
+					return withLock(c, migrator, func() error {
+						group, err := migrator.Redo(c.Context)
+						if err != nil {
+							return err
+						}
+						if group.IsZero() {
+							fmt.Fprintf(c.App.Writer, "there are no groups to redo\n")
+							return nil
+						}
+						fmt.Fprintf(c.App.Writer, "redone %s\n", group)
+						return nil
+					})
+				},
+			},
+			{
+				Name:      "squash",
+				Usage:     "replace the migrations up to and including the migration with a baseline",
+				ArgsUsage: "<migration>",
+				Action: func(c *cli.Context) error {
+					target, err := targetArg(c)
+					if err != nil {
+						return err
+					}
+					return withLock(c, migrator, func() error {
+						files, err := migrator.Squash(c.Context, target)
+						if err != nil {
+							return err
+						}
+						printFiles(c.App.Writer, files)
+						return nil
+					})
+				},
+			},
+			{
+				Name:  "lock",
+				Usage: "lock migrations",
+				Action: func(c *cli.Context) error {
+					return migrator.Lock(c.Context)
+				},
+			},
+			{
+				Name:  "unlock",
+				Usage: "unlock migrations",
+				Action: func(c *cli.Context) error {
+					return migrator.Unlock(c.Context)
+				},
+			},
+			{
+				Name:      "create_go",
+				Usage:     "create Go migration",
+				ArgsUsage: "<name>",
+				Action: func(c *cli.Context) error {
+					name := strings.Join(c.Args().Slice(), "_")
+					mf, err := migrator.CreateGoMigration(c.Context, name)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(c.App.Writer, "created migration %s (%s)\n", mf.Name, mf.Path)
+					return nil
+				},
+			},
+			{
+				Name:      "create_sql",
+				Usage:     "create up and down SQL migrations",
+				ArgsUsage: "<name>",
+				Action: func(c *cli.Context) error {
+					name := strings.Join(c.Args().Slice(), "_")
+					files, err := migrator.CreateSQLMigrations(c.Context, name)
+					if err != nil {
+						return err
+					}
+					printFiles(c.App.Writer, files)
+					return nil
+				},
+			},
+			{
+				Name:      "create_tx_sql",
+				Usage:     "create up and down transactional SQL migrations",
+				ArgsUsage: "<name>",
+				Action: func(c *cli.Context) error {
+					name := strings.Join(c.Args().Slice(), "_")
+					files, err := migrator.CreateTxSQLMigrations(c.Context, name)
+					if err != nil {
+						return err
+					}
+					printFiles(c.App.Writer, files)
+					return nil
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "print migrations status",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "print status as JSON",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					ms, err := migrator.MigrationsWithStatus(c.Context)
+					if err != nil {
+						return err
+					}
+					if c.Bool("json") {
+						return WriteStatusJSON(c.App.Writer, ms)
+					}
+					return WriteStatusTable(c.App.Writer, ms)
+				},
+			},
+			{
+				Name:  "validate",
+				Usage: "check that the applied migrations have not been modified or deleted",
+				Action: func(c *cli.Context) error {
+					drift, err := migrator.Validate(c.Context)
+					if err != nil {
+						return err
+					}
+					if !drift.IsZero() {
+						return &migrate.DriftError{Drift: drift}
+					}
+					fmt.Fprintf(c.App.Writer, "applied migrations are up to date\n")
+					return nil
+				},
+			},
+			{
+				Name:  "mark_applied",
+				Usage: "mark migrations as applied without actually running them",
+				Action: func(c *cli.Context) error {
+					return withLock(c, migrator, func() error {
+						group, err := migrator.Migrate(c.Context, migrate.WithNopMigration())
+						if err != nil {
+							return err
+						}
+						if group.IsZero() {
+							fmt.Fprintf(c.App.Writer, "there are no new migrations to mark as applied\n")
+							return nil
+						}
+						fmt.Fprintf(c.App.Writer, "marked as applied %s\n", group)
+						return nil
+					})
+				},
+			},
+		},
+	}
+
+	if cfg.autoMigrator != nil {
+		cmd.Subcommands = append(cmd.Subcommands, newAutoCommands(cfg.autoMigrator)...)
+	}
+	return cmd
+}
+
+// newAutoCommands creates commands which detect the changes to the models.
+func newAutoCommands(m *migrate.AutoMigrator) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "plan",
+			Usage: "print the changes required to bring the database schema in line with the models",
+			Action: func(c *cli.Context) error {
+				plan, err := m.Plan(c.Context)
+				if err != nil {
+					return err
+				}
+				if plan.IsZero() {
+					fmt.Fprintf(c.App.Writer, "database schema is up to date\n")
+					return nil
+				}
+				for _, op := range plan.Operations {
+					fmt.Fprintf(c.App.Writer, "%-11s %T\n", op.Safety, op.Operation)
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "create_auto_sql",
+			Usage: "create up and down SQL migrations from the changes to the models",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "tx",
+					Usage: "create transactional migrations",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				create := m.CreateSQLMigrations
+				if c.Bool("tx") {
+					create = m.CreateTxSQLMigrations
+				}
+				files, err := create(c.Context)
+				if err != nil {
+					return err
+				}
+				if len(files) == 0 {
+					fmt.Fprintf(c.App.Writer, "database schema is up to date\n")
+					return nil
+				}
+				printFiles(c.App.Writer, files)
+				return nil
+			},
+		},
+		{
+			Name:      "snapshot",
+			Usage:     "write the schema defined by the models to a snapshot file",
+			ArgsUsage: "<file>",
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return fmt.Errorf("%s: expected exactly one file name", c.Command.Name)
+				}
+				path := c.Args().First()
+				if err := m.WriteSnapshot(c.Context, path); err != nil {
+					return err
+				}
+				fmt.Fprintf(c.App.Writer, "created snapshot %s\n", path)
+				return nil
+			},
+		},
+	}
+}
+
+// withLock runs fn while holding the migrations lock, see migrate.Migrator.RunLocked.
+// The command context is replaced with the one which is canceled if the lock is lost.
+func withLock(c *cli.Context, migrator *migrate.Migrator, fn func() error) error {
+	return migrator.RunLocked(c.Context, func(ctx context.Context) error {
+		c.Context = ctx
+		return fn()
+	})
+}
+
+func targetArg(c *cli.Context) (string, error) {
+	if c.NArg() != 1 {
+		return "", fmt.Errorf("%s: expected exactly one migration name", c.Command.Name)
+	}
+	return c.Args().First(), nil
+}
+
+func printFiles(w io.Writer, files []*migrate.MigrationFile) {
+	for _, mf := range files {
+		fmt.Fprintf(w, "created migration %s (%s)\n", mf.Name, mf.Path)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// WriteStatusTable writes the migrations with their status as a table.
+func WriteStatusTable(w io.Writer, ms migrate.MigrationSlice) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCOMMENT\tGROUP\tMIGRATED AT")
+	for _, m := range ms {
+		group, migratedAt := "-", "pending"
+		if m.IsApplied() {
+			group = fmt.Sprint(m.GroupID)
+			migratedAt = m.MigratedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", m.Name, m.Comment, group, migratedAt)
+	}
+	return tw.Flush()
+}
+
+type migrationStatus struct {
+	Name       string     `json:"name"`
+	Comment    string     `json:"comment,omitempty"`
+	Applied    bool       `json:"applied"`
+	GroupID    int64      `json:"group_id,omitempty"`
+	MigratedAt *time.Time `json:"migrated_at,omitempty"`
+}
+
+// WriteStatusJSON writes the migrations with their status as a JSON array.
+func WriteStatusJSON(w io.Writer, ms migrate.MigrationSlice) error {
+	status := make([]migrationStatus, len(ms))
+	for i, m := range ms {
+		status[i] = migrationStatus{
+			Name:    m.Name,
+			Comment: m.Comment,
+			Applied: m.IsApplied(),
+		}
+		if m.IsApplied() {
+			status[i].GroupID = m.GroupID
+			status[i].MigratedAt = &ms[i].MigratedAt
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(status)
+}
@@ -0,0 +1,42 @@
+package migratecli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+func TestWriteStatus(t *testing.T) {
+	ms := migrate.MigrationSlice{
+		{
+			ID:         1,
+			Name:       "20060102150405",
+			Comment:    "create_users",
+			GroupID:    1,
+			MigratedAt: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{Name: "20060102160405", Comment: "add_email"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteStatusTable(&buf, ms))
+		require.Equal(t, ""+
+			"NAME            COMMENT       GROUP  MIGRATED AT\n"+
+			"20060102150405  create_users  1      2006-01-02T15:04:05Z\n"+
+			"20060102160405  add_email     -      pending\n", buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteStatusJSON(&buf, ms))
+		require.JSONEq(t, `[
+			{"name": "20060102150405", "comment": "create_users", "applied": true, "group_id": 1, "migrated_at": "2006-01-02T15:04:05Z"},
+			{"name": "20060102160405", "comment": "add_email", "applied": false}
+		]`, buf.String())
+	})
+}
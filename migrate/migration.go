@@ -25,6 +25,19 @@ type Migration struct {
 	GroupID    int64
 	MigratedAt time.Time `bun:",notnull,nullzero,default:current_timestamp"`
 
+	// Checksum is the SHA-256 hash of the up migration file. It is recorded when the migration
+	// is applied and is used to detect changes to the applied migrations, see Migrator.Validate.
+	// Go migrations have no checksum unless it is set explicitly.
+	Checksum string `bun:",nullzero"`
+
+	// SquashedInto is the name of the baseline migration which replaced the applied migration,
+	// see Migrator.Squash.
+	SquashedInto string `bun:",nullzero"`
+
+	// Squashed are the names of the migrations replaced by the baseline migration.
+	// They are listed in the --bun:squashed directive of the up migration file.
+	Squashed []string `bun:"-"`
+
 	Up   internalMigrationFunc `bun:"-"`
 	Down internalMigrationFunc `bun:"-"`
 }
@@ -99,6 +112,9 @@ func newSQLMigrationFunc(fsys fs.FS, name string) internalMigrationFunc {
 					query = query[:0]
 					continue
 				}
+				if bytes.HasPrefix(b, []byte("squashed ")) {
+					continue
+				}
 				return fmt.Errorf("bun: unknown directive: %q", b)
 			}
 
@@ -257,6 +273,17 @@ func (ms MigrationSlice) Unapplied() MigrationSlice {
 	return unapplied
 }
 
+// find returns the migration with the name, e.g. "20060102150405",
+// or the name with the comment, e.g. "20060102150405_create_users".
+func (ms MigrationSlice) find(name string) (*Migration, error) {
+	for i := range ms {
+		if ms[i].Name == name || ms[i].String() == name {
+			return &ms[i], nil
+		}
+	}
+	return nil, fmt.Errorf("migrate: migration %q not found", name)
+}
+
 // LastGroupID returns the last applied migration group id.
 // The id is 0 when there are no migration groups.
 func (ms MigrationSlice) LastGroupID() int64 {
@@ -334,22 +361,22 @@ func WithNopMigration() MigrationOption {
 
 func sortAsc(ms MigrationSlice) {
 	sort.Slice(ms, func(i, j int) bool {
-		ni, ei := strconv.ParseInt(ms[i].Name, 10, 64)
-		nj, ej := strconv.ParseInt(ms[j].Name, 10, 64)
-		if ei == nil && ej == nil && ni != nj {
-			return ni < nj
-		}
-		return ms[i].Name < ms[j].Name
+		return migrationLess(ms[i], ms[j])
 	})
 }
 
 func sortDesc(ms MigrationSlice) {
 	sort.Slice(ms, func(i, j int) bool {
-		ni, ei := strconv.ParseInt(ms[i].Name, 10, 64)
-		nj, ej := strconv.ParseInt(ms[j].Name, 10, 64)
-		if ei == nil && ej == nil && ni != nj {
-			return ni > nj
-		}
-		return ms[i].Name > ms[j].Name
+		return migrationLess(ms[j], ms[i])
 	})
 }
+
+// migrationLess reports whether the migration a has an earlier version than b.
+func migrationLess(a, b Migration) bool {
+	na, ea := strconv.ParseInt(a.Name, 10, 64)
+	nb, eb := strconv.ParseInt(b.Name, 10, 64)
+	if ea == nil && eb == nil && na != nb {
+		return na < nb
+	}
+	return a.Name < b.Name
+}
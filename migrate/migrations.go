@@ -100,7 +100,13 @@ func (m *Migrations) Discover(fsys fs.FS) error {
 		migrationFunc := newSQLMigrationFunc(fsys, path)
 
 		if strings.HasSuffix(path, ".up.sql") {
+			contents, err := fs.ReadFile(fsys, path)
+			if err != nil {
+				return err
+			}
 			migration.Up = migrationFunc
+			migration.Checksum = checksum(contents)
+			migration.Squashed = parseSquashed(contents)
 			return nil
 		}
 		if strings.HasSuffix(path, ".down.sql") {
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -47,6 +49,30 @@ func WithTemplateData(data any) MigratorOption {
 	}
 }
 
+// WithDriftCheck makes Migrate refuse to apply migrations if Validate reports
+// any drift between the applied migrations and the registered ones.
+func WithDriftCheck(enabled bool) MigratorOption {
+	return func(m *Migrator) {
+		m.driftCheck = enabled
+	}
+}
+
+// WithLocker overrides the lock which prevents migrations from being applied concurrently.
+// By default, a row is inserted into the locks table, see WithLocksTableName.
+func WithLocker(locker Locker) MigratorOption {
+	return func(m *Migrator) {
+		m.locker = locker
+	}
+}
+
+// WithLockTimeout sets how long RunLocked waits for the lock held by someone else.
+// By default, RunLocked fails immediately.
+func WithLockTimeout(timeout time.Duration) MigratorOption {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
+}
+
 type MigrationHook func(ctx context.Context, db bun.IConn, migration *Migration) error
 
 func BeforeMigration(hook MigrationHook) MigratorOption {
@@ -69,7 +95,12 @@ type Migrator struct {
 
 	table                string
 	locksTable           string
+	cursorsTable         string
+	locker               Locker
+	lockTimeout          time.Duration
 	markAppliedOnSuccess bool
+	driftCheck           bool
+	upgraded             bool // the migrations table has all of the columns
 	templateData         any
 
 	beforeMigrationHook MigrationHook
@@ -83,12 +114,20 @@ func NewMigrator(db *bun.DB, migrations *Migrations, opts ...MigratorOption) *Mi
 
 		ms: migrations.ms,
 
-		table:      defaultTable,
-		locksTable: defaultLocksTable,
+		table:        defaultTable,
+		locksTable:   defaultLocksTable,
+		cursorsTable: defaultCursorsTable,
 	}
 	for _, opt := range opts {
 		opt(m)
 	}
+	if m.locker == nil {
+		m.locker = &tableLocker{
+			db:         db,
+			name:       m.formattedTableName(db),
+			locksTable: m.locksTable,
+		}
+	}
 	return m
 }
 
@@ -131,6 +170,9 @@ func (m *Migrator) Init(ctx context.Context) error {
 		Exec(ctx); err != nil {
 		return err
 	}
+	if err := m.upgradeTable(ctx); err != nil {
+		return err
+	}
 	if _, err := m.db.NewCreateTable().
 		Model((*migrationLock)(nil)).
 		ModelTableExpr(m.locksTable).
@@ -138,9 +180,65 @@ func (m *Migrator) Init(ctx context.Context) error {
 		Exec(ctx); err != nil {
 		return err
 	}
+	if locker, ok := m.locker.(interface{ Init(context.Context) error }); ok {
+		if err := locker.Init(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeTable adds the columns which were introduced after the migrations table was first created,
+// so that the tables created by older versions can be written to without running Init again.
+func (m *Migrator) upgradeTable(ctx context.Context) error {
+	if m.upgraded {
+		return nil
+	}
+	if err := m.addColumn(ctx, "checksum", "VARCHAR(64)"); err != nil {
+		return err
+	}
+	if err := m.addColumn(ctx, "squashed_into", "VARCHAR(255)"); err != nil {
+		return err
+	}
+	m.upgraded = true
 	return nil
 }
 
+// addColumn adds the column to the migrations table created without it.
+func (m *Migrator) addColumn(ctx context.Context, column, sqlType string) error {
+	// The column name is not quoted, because SQLite treats unknown quoted identifiers as strings.
+	_, err := m.db.NewRaw("SELECT ? FROM ? WHERE 1 = 0", bun.Safe(column), bun.Safe(m.table)).Exec(ctx)
+	if err == nil {
+		return nil
+	}
+	if !isUndefinedColumn(err) {
+		return err
+	}
+	_, err = m.db.NewAddColumn().
+		Model((*Migration)(nil)).
+		ModelTableExpr(m.table).
+		ColumnExpr("? ?", bun.Ident(column), bun.Safe(sqlType)).
+		Exec(ctx)
+	return err
+}
+
+// isUndefinedColumn reports whether the error is caused by a reference to a column which does not exist.
+// The drivers report it differently and migrate does not depend on any of them, so the error message is checked.
+func isUndefinedColumn(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"42703",               // PostgreSQL
+		"unknown column",      // MySQL
+		"no such column",      // SQLite
+		"invalid column name", // SQL Server
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Migrator) Reset(ctx context.Context) error {
 	if _, err := m.db.NewDropTable().
 		Model((*Migration)(nil)).
@@ -156,6 +254,13 @@ func (m *Migrator) Reset(ctx context.Context) error {
 		Exec(ctx); err != nil {
 		return err
 	}
+	if _, err := m.db.NewDropTable().
+		Model((*migrationCursor)(nil)).
+		ModelTableExpr(m.cursorsTable).
+		IfExists().
+		Exec(ctx); err != nil {
+		return err
+	}
 	return m.Init(ctx)
 }
 
@@ -166,17 +271,67 @@ func (m *Migrator) Migrate(ctx context.Context, opts ...MigrationOption) (*Migra
 	if err := m.validate(); err != nil {
 		return nil, err
 	}
+	if err := m.checkDrift(ctx); err != nil {
+		return nil, err
+	}
+
+	migrations, lastGroupID, err := m.migrationsWithStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.migrate(ctx, cfg, migrations.Unapplied(), lastGroupID)
+}
+
+// MigrateTo runs unapplied migrations up to and including the target migration
+// as a single group. The target is identified by its name, e.g. "20060102150405",
+// or by its name with the comment, e.g. "20060102150405_create_users".
+//
+// Nothing is applied if the target migration has already been applied, including
+// the unapplied migrations older than the target; use Migrate to apply them.
+func (m *Migrator) MigrateTo(ctx context.Context, target string, opts ...MigrationOption) (*MigrationGroup, error) {
+	cfg := newMigrationConfig(opts)
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if err := m.checkDrift(ctx); err != nil {
+		return nil, err
+	}
 
 	migrations, lastGroupID, err := m.migrationsWithStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
-	migrations = migrations.Unapplied()
 
+	targetMigration, err := migrations.find(target)
+	if err != nil {
+		return nil, err
+	}
+	if targetMigration.IsApplied() {
+		return new(MigrationGroup), nil
+	}
+
+	var unapplied MigrationSlice
+	for _, migration := range migrations.Unapplied() {
+		if migrationLess(*targetMigration, migration) {
+			break
+		}
+		unapplied = append(unapplied, migration)
+	}
+	return m.migrate(ctx, cfg, unapplied, lastGroupID)
+}
+
+// migrate applies the migrations in the given order as a new group.
+func (m *Migrator) migrate(
+	ctx context.Context, cfg *migrationConfig, migrations MigrationSlice, lastGroupID int64,
+) (*MigrationGroup, error) {
 	group := new(MigrationGroup)
 	if len(migrations) == 0 {
 		return group, nil
 	}
+	if err := m.upgradeTable(ctx); err != nil {
+		return group, err
+	}
 	group.ID = lastGroupID + 1
 
 	for i := range migrations {
@@ -220,30 +375,108 @@ func (m *Migrator) Rollback(ctx context.Context, opts ...MigrationOption) (*Migr
 	}
 
 	lastGroup := migrations.LastGroup()
+	return lastGroup, m.rollback(ctx, cfg, lastGroup.Migrations)
+}
+
+// RollbackTo rolls back the migrations applied after the target migration, possibly spanning
+// multiple groups, in the reverse order of their application. The target migration
+// remains applied. See MigrateTo for the format of the target.
+//
+// RollbackTo returns the migrations it has rolled back.
+func (m *Migrator) RollbackTo(ctx context.Context, target string, opts ...MigrationOption) (MigrationSlice, error) {
+	cfg := newMigrationConfig(opts)
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	migrations, err := m.MigrationsWithStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetMigration, err := migrations.find(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied MigrationSlice
+	for _, migration := range migrations {
+		if migration.IsApplied() && migrationLess(*targetMigration, migration) {
+			applied = append(applied, migration)
+		}
+	}
+
+	// Migrations from different groups are rolled back in the reverse order of their application.
+	sort.SliceStable(applied, func(i, j int) bool {
+		return applied[i].ID < applied[j].ID
+	})
+	return applied, m.rollback(ctx, cfg, applied)
+}
+
+// Redo rolls back the last migration group and applies the same migrations again.
+func (m *Migrator) Redo(ctx context.Context, opts ...MigrationOption) (*MigrationGroup, error) {
+	cfg := newMigrationConfig(opts)
 
-	for i := len(lastGroup.Migrations) - 1; i >= 0; i-- {
-		migration := &lastGroup.Migrations[i]
+	lastGroup, err := m.Rollback(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, lastGroupID, err := m.migrationsWithStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rolledBack := migrationMap(lastGroup.Migrations)
+	var redo MigrationSlice
+	for _, migration := range migrations.Unapplied() {
+		if _, ok := rolledBack[migration.Name]; ok {
+			redo = append(redo, migration)
+		}
+	}
+	return m.migrate(ctx, cfg, redo, lastGroupID)
+}
+
+// rollback rolls back the migrations in the reverse order.
+func (m *Migrator) rollback(ctx context.Context, cfg *migrationConfig, migrations MigrationSlice) error {
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := &migrations[i]
 
 		if !m.markAppliedOnSuccess {
 			if err := m.MarkUnapplied(ctx, migration); err != nil {
-				return lastGroup, err
+				return err
 			}
 		}
 
 		if !cfg.nop && migration.Down != nil {
 			if err := migration.Down(ctx, m, migration); err != nil {
-				return lastGroup, err
+				return err
 			}
 		}
 
 		if m.markAppliedOnSuccess {
 			if err := m.MarkUnapplied(ctx, migration); err != nil {
-				return lastGroup, err
+				return err
 			}
 		}
 	}
+	return nil
+}
 
-	return lastGroup, nil
+// checkDrift returns DriftError if the drift check is enabled and Validate reports any drift.
+func (m *Migrator) checkDrift(ctx context.Context) error {
+	if !m.driftCheck {
+		return nil
+	}
+	drift, err := m.Validate(ctx)
+	if err != nil {
+		return err
+	}
+	if !drift.IsZero() {
+		return &DriftError{Drift: drift}
+	}
+	return nil
 }
 
 type goMigrationConfig struct {
@@ -361,6 +594,11 @@ func (m *Migrator) createSQL(_ context.Context, fname string, transactional bool
 var nameRE = regexp.MustCompile(`^[0-9a-z_\-]+$`)
 
 func genMigrationName(name string) (string, error) {
+	return genMigrationNameAt(name, time.Now())
+}
+
+// genMigrationNameAt generates a migration name with the version derived from t.
+func genMigrationNameAt(name string, t time.Time) (string, error) {
 	const timeFormat = "20060102150405"
 
 	if name == "" {
@@ -370,12 +608,15 @@ func genMigrationName(name string) (string, error) {
 		return "", fmt.Errorf("migrate: invalid migration name: %q", name)
 	}
 
-	version := time.Now().UTC().Format(timeFormat)
+	version := t.UTC().Format(timeFormat)
 	return fmt.Sprintf("%s_%s", version, name), nil
 }
 
 // MarkApplied marks the migration as applied (completed).
 func (m *Migrator) MarkApplied(ctx context.Context, migration *Migration) error {
+	if err := m.upgradeTable(ctx); err != nil {
+		return err
+	}
 	_, err := m.db.NewInsert().Model(migration).
 		ModelTableExpr(m.table).
 		Exec(ctx)
@@ -431,6 +672,16 @@ func (m *Migrator) AppliedMigrations(ctx context.Context) (MigrationSlice, error
 	return ms, nil
 }
 
+// ownTables returns the names of the tables managed by the migrator,
+// which must not be touched by the migrations it generates.
+func (m *Migrator) ownTables() []string {
+	tables := []string{m.table, m.locksTable, m.cursorsTable}
+	if l, ok := m.locker.(*LeaseLocker); ok {
+		tables = append(tables, l.table)
+	}
+	return tables
+}
+
 func (m *Migrator) formattedTableName(db *bun.DB) string {
 	return db.QueryGen().FormatQuery(m.table)
 }
@@ -468,32 +719,14 @@ func (m *Migrator) exec(
 
 //------------------------------------------------------------------------------
 
-type migrationLock struct {
-	ID        int64  `bun:",pk,autoincrement"`
-	TableName string `bun:",unique"`
-}
-
+// Lock acquires the migrations lock without waiting, see WithLocker.
 func (m *Migrator) Lock(ctx context.Context) error {
-	lock := &migrationLock{
-		TableName: m.formattedTableName(m.db),
-	}
-	if _, err := m.db.NewInsert().
-		Model(lock).
-		ModelTableExpr(m.locksTable).
-		Exec(ctx); err != nil {
-		return fmt.Errorf("migrate: migrations table is already locked (%w)", err)
-	}
-	return nil
+	return m.locker.Lock(ctx)
 }
 
+// Unlock releases the migrations lock.
 func (m *Migrator) Unlock(ctx context.Context) error {
-	tableName := m.formattedTableName(m.db)
-	_, err := m.db.NewDelete().
-		Model((*migrationLock)(nil)).
-		ModelTableExpr(m.locksTable).
-		Where("? = ?", bun.Ident("table_name"), tableName).
-		Exec(ctx)
-	return err
+	return m.locker.Unlock(ctx)
 }
 
 func migrationMap(ms MigrationSlice) map[string]*Migration {
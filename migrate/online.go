@@ -0,0 +1,151 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+// onlineChanges replaces the operations which hold an exclusive lock on the table
+// while they scan or rewrite it with lock-friendly sequences of operations:
+//   - FOREIGN KEY and CHECK constraints are added as NOT VALID and validated separately.
+//   - Indexes are created and dropped CONCURRENTLY.
+//   - UNIQUE constraints are added USING an index which is created CONCURRENTLY beforehand.
+//
+// Tables in skip, i.e. created or dropped in the same migration (see newTables),
+// are altered as usual, because nothing else can be using them yet (or anymore).
+//
+// The changeset must already be sorted; the steps of each operation are kept together.
+func onlineChanges(c *changeset, skip map[string]bool) *changeset {
+	var online changeset
+	for _, op := range c.operations {
+		online.Add(onlineSteps(op, skip)...)
+	}
+	return &online
+}
+
+// onlineSteps returns the lock-friendly sequence of operations equivalent to op.
+func onlineSteps(op Operation, skip map[string]bool) []Operation {
+	switch op := op.(type) {
+	case *AddForeignKeyOp:
+		if skip[op.TableName()] {
+			break
+		}
+		name := op.ConstraintName
+		if name == "" {
+			colRef := op.ForeignKey.From
+			name = defaultConstraintName(colRef.TableName, colRef.Column.Split(), "fkey")
+		}
+		return []Operation{
+			&AddForeignKeyOp{ForeignKey: op.ForeignKey, ConstraintName: name, NotValid: true},
+			&ValidateConstraintOp{TableName: op.TableName(), ConstraintName: name},
+		}
+	case *AddCheckOp:
+		if skip[op.TableName] || op.Check.Name == "" {
+			break
+		}
+		return []Operation{
+			&AddCheckOp{TableName: op.TableName, Check: op.Check, NotValid: true},
+			&ValidateConstraintOp{TableName: op.TableName, ConstraintName: op.Check.Name},
+		}
+	case *AddUniqueConstraintOp:
+		if skip[op.TableName] {
+			break
+		}
+		unique := op.Unique
+		if unique.Name == "" {
+			unique.Name = defaultConstraintName(op.TableName, unique.Columns.Split(), "key")
+		}
+		index := &CreateIndexOp{
+			TableName: op.TableName,
+			Index: sqlschema.Index{
+				Name:    unique.Name,
+				Unique:  true,
+				Columns: unique.Columns.Split(),
+			},
+			Concurrently: true,
+		}
+		return []Operation{
+			index,
+			&AddUniqueConstraintOp{TableName: op.TableName, Unique: unique, UsingIndex: true},
+		}
+	case *CreateIndexOp:
+		if skip[op.TableName] {
+			break
+		}
+		return []Operation{&CreateIndexOp{TableName: op.TableName, Index: op.Index, Concurrently: true}}
+	case *DropIndexOp:
+		if skip[op.TableName] {
+			break
+		}
+		return []Operation{&DropIndexOp{TableName: op.TableName, Index: op.Index, Concurrently: true}}
+	}
+	return []Operation{op}
+}
+
+// newTables collects the names of the tables which are created or dropped in the changeset.
+// Both directions of a migration include the same tables.
+func newTables(c *changeset) map[string]bool {
+	tables := make(map[string]bool)
+	for _, op := range c.operations {
+		switch op := op.(type) {
+		case *CreateTableOp:
+			tables[op.TableName] = true
+		case *DropTableOp:
+			tables[op.TableName] = true
+		}
+	}
+	return tables
+}
+
+// defaultConstraintName generates a constraint name using the same naming scheme as Postgres:
+// <table>_<column>_<suffix>. The name must be known in advance to refer to the constraint in
+// the following steps, e.g. to validate it.
+func defaultConstraintName(tableName string, columns []string, suffix string) string {
+	return fmt.Sprintf("%s_%s_%s", tableName, strings.Join(columns, "_"), suffix)
+}
+
+// isConcurrent checks if the operation cannot be executed inside a transaction.
+func isConcurrent(op Operation) bool {
+	switch op := op.(type) {
+	case *CreateIndexOp:
+		return op.Concurrently
+	case *DropIndexOp:
+		return op.Concurrently
+	}
+	return false
+}
+
+// splitTransactional splits the changeset into consecutive groups of operations
+// which can and cannot be executed inside a transaction in online mode.
+func splitTransactional(c *changeset, skip map[string]bool) []*changeset {
+	var groups []*changeset
+	var last *changeset
+	var lastConcurrent bool
+	for _, op := range c.operations {
+		concurrent := false
+		for _, step := range onlineSteps(op, skip) {
+			concurrent = concurrent || isConcurrent(step)
+		}
+		if last == nil || concurrent != lastConcurrent {
+			last = new(changeset)
+			groups = append(groups, last)
+			lastConcurrent = concurrent
+		}
+		last.Add(op)
+	}
+	return groups
+}
+
+// transactional checks if all operations in the changeset can be executed inside a transaction.
+// The migrator must have already generated the SQL for the changeset.
+func (c *changeset) transactional(m sqlschema.Migrator) bool {
+	txm, _ := m.(sqlschema.TxMigrator)
+	for _, op := range c.operations {
+		if isConcurrent(op) || (txm != nil && !txm.Transactional(op)) {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,8 @@ package migrate
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/uptrace/bun/migrate/sqlschema"
 )
@@ -27,12 +29,17 @@ type Operation interface {
 
 // CreateTableOp creates a new table in the schema.
 //
-// It does not report dependency on any other migration and may be executed first.
+// It only depends on CreateEnumOp, since the table's columns may use the new enum types,
+// and may otherwise be executed first.
 // Make sure the dialect does not include FOREIGN KEY constraints in the CREATE TABLE
 // statement, as those may potentially reference not-yet-existing columns/tables.
 type CreateTableOp struct {
 	TableName string
 	Model     any
+
+	// Table is the inspected definition of the table, which is used when Model is nil,
+	// e.g. to re-create the tables in a baseline migration, see Migrator.Squash.
+	Table sqlschema.Table
 }
 
 var _ Operation = (*CreateTableOp)(nil)
@@ -41,6 +48,11 @@ func (op *CreateTableOp) GetReverse() Operation {
 	return &DropTableOp{TableName: op.TableName}
 }
 
+func (op *CreateTableOp) DependsOn(another Operation) bool {
+	_, ok := another.(*CreateEnumOp)
+	return ok
+}
+
 // DropTableOp drops a database table. This operation is not reversible.
 type DropTableOp struct {
 	TableName string
@@ -115,12 +127,16 @@ func (op *AddColumnOp) GetReverse() Operation {
 	}
 }
 
+func (op *AddColumnOp) DependsOn(another Operation) bool {
+	return dependsOnEnum(op.Column, another)
+}
+
 // DropColumnOp drop a column from the table.
 //
 // While some dialects allow DROP CASCADE to drop dependent constraints,
 // explicit handling on constraints is preferred for transparency and debugging.
-// DropColumnOp depends on DropForeignKeyOp, DropPrimaryKeyOp, and ChangePrimaryKeyOp
-// if any of the constraints is defined on this table.
+// DropColumnOp depends on DropForeignKeyOp, DropPrimaryKeyOp, ChangePrimaryKeyOp, DropIndexOp,
+// and DropCheckOp if any of the constraints or indexes is defined on this table.
 type DropColumnOp struct {
 	TableName  string
 	ColumnName string
@@ -145,14 +161,23 @@ func (op *DropColumnOp) DependsOn(another Operation) bool {
 		return op.TableName == drop.TableName && drop.PrimaryKey.Columns.Contains(op.ColumnName)
 	case *ChangePrimaryKeyOp:
 		return op.TableName == drop.TableName && drop.Old.Columns.Contains(op.ColumnName)
+	case *DropIndexOp:
+		return op.TableName == drop.TableName && drop.Index.DependsOnColumn(op.ColumnName)
+	case *DropCheckOp:
+		// Columns referenced in the CHECK expression are not known, so drop the constraints first
+		// to prevent the database from dropping them together with the column.
+		return op.TableName == drop.TableName
 	}
 	return false
 }
 
 // AddForeignKey adds a new FOREIGN KEY constraint.
+//
+// NotValid skips the check of the existing rows, which are then validated with ValidateConstraintOp.
 type AddForeignKeyOp struct {
 	ForeignKey     sqlschema.ForeignKey
 	ConstraintName string
+	NotValid       bool
 }
 
 var _ Operation = (*AddForeignKeyOp)(nil)
@@ -198,9 +223,13 @@ func (op *DropForeignKeyOp) GetReverse() Operation {
 }
 
 // AddUniqueConstraintOp adds new UNIQUE constraint to the table.
+//
+// UsingIndex turns an existing unique index with the same name into the constraint,
+// e.g. one created by CreateIndexOp with Concurrently set.
 type AddUniqueConstraintOp struct {
-	TableName string
-	Unique    sqlschema.Unique
+	TableName  string
+	Unique     sqlschema.Unique
+	UsingIndex bool
 }
 
 var _ Operation = (*AddUniqueConstraintOp)(nil)
@@ -270,6 +299,10 @@ func (op *ChangeColumnTypeOp) GetReverse() Operation {
 	}
 }
 
+func (op *ChangeColumnTypeOp) DependsOn(another Operation) bool {
+	return dependsOnEnum(op.To, another)
+}
+
 // DropPrimaryKeyOp drops the table's PRIMARY KEY.
 type DropPrimaryKeyOp struct {
 	TableName  string
@@ -325,6 +358,274 @@ func (op *ChangePrimaryKeyOp) GetReverse() Operation {
 	}
 }
 
+// CreateIndexOp creates a new index on the table.
+// If Index.Name is empty, the dialect generates one using sqlschema.IndexName.
+//
+// Concurrently builds the index without blocking writes to the table.
+// Such operations cannot be executed inside a transaction.
+type CreateIndexOp struct {
+	TableName    string
+	Index        sqlschema.Index
+	Concurrently bool
+}
+
+var _ Operation = (*CreateIndexOp)(nil)
+
+func (op *CreateIndexOp) GetReverse() Operation {
+	return &DropIndexOp{
+		TableName:    op.TableName,
+		Index:        op.Index,
+		Concurrently: op.Concurrently,
+	}
+}
+
+func (op *CreateIndexOp) DependsOn(another Operation) bool {
+	switch another := another.(type) {
+	case *CreateTableOp:
+		return op.TableName == another.TableName
+	case *RenameTableOp:
+		return op.TableName == another.NewName
+	case *AddColumnOp:
+		return op.TableName == another.TableName && op.Index.DependsOnColumn(another.ColumnName)
+	case *RenameColumnOp:
+		return op.TableName == another.TableName && op.Index.DependsOnColumn(another.NewName)
+	case *DropIndexOp:
+		// Drop the old index first in case the new one re-uses its name.
+		return op.TableName == another.TableName
+	}
+	return false
+}
+
+// DropIndexOp drops an index.
+// Concurrently waits for the queries using the index to complete instead of blocking them.
+type DropIndexOp struct {
+	TableName    string
+	Index        sqlschema.Index
+	Concurrently bool
+}
+
+var _ Operation = (*DropIndexOp)(nil)
+
+func (op *DropIndexOp) GetReverse() Operation {
+	return &CreateIndexOp{
+		TableName:    op.TableName,
+		Index:        op.Index,
+		Concurrently: op.Concurrently,
+	}
+}
+
+func (op *DropIndexOp) DependsOn(another Operation) bool {
+	if rename, ok := another.(*RenameTableOp); ok {
+		return op.TableName == rename.NewName
+	}
+	return false
+}
+
+// AddCheckOp adds a new CHECK constraint to the table.
+//
+// NotValid skips the check of the existing rows, which are then validated with ValidateConstraintOp.
+type AddCheckOp struct {
+	TableName string
+	Check     sqlschema.Check
+	NotValid  bool
+}
+
+var _ Operation = (*AddCheckOp)(nil)
+
+func (op *AddCheckOp) GetReverse() Operation {
+	return &DropCheckOp{
+		TableName: op.TableName,
+		Check:     op.Check,
+	}
+}
+
+// DependsOn reports dependency on any new or renamed column in the table,
+// because the columns referenced in the CHECK expression are not known.
+func (op *AddCheckOp) DependsOn(another Operation) bool {
+	switch another := another.(type) {
+	case *RenameTableOp:
+		return op.TableName == another.NewName
+	case *AddColumnOp:
+		return op.TableName == another.TableName
+	case *RenameColumnOp:
+		return op.TableName == another.TableName
+	case *ChangeColumnTypeOp:
+		return op.TableName == another.TableName
+	case *DropCheckOp:
+		// Drop the old constraint first in case the new one re-uses its name.
+		return op.TableName == another.TableName
+	}
+	return false
+}
+
+// DropCheckOp drops a CHECK constraint.
+type DropCheckOp struct {
+	TableName string
+	Check     sqlschema.Check
+}
+
+var _ Operation = (*DropCheckOp)(nil)
+
+func (op *DropCheckOp) GetReverse() Operation {
+	return &AddCheckOp{
+		TableName: op.TableName,
+		Check:     op.Check,
+	}
+}
+
+func (op *DropCheckOp) DependsOn(another Operation) bool {
+	if rename, ok := another.(*RenameTableOp); ok {
+		return op.TableName == rename.NewName
+	}
+	return false
+}
+
+// CreateEnumOp creates a new enum type.
+type CreateEnumOp struct {
+	Enum sqlschema.Enum
+}
+
+var _ Operation = (*CreateEnumOp)(nil)
+
+func (op *CreateEnumOp) GetReverse() Operation {
+	return &DropEnumOp{Enum: op.Enum}
+}
+
+// DropEnumOp drops an enum type. It depends on the operations which
+// drop or change the type of the columns that use it.
+type DropEnumOp struct {
+	Enum sqlschema.Enum
+}
+
+var _ Operation = (*DropEnumOp)(nil)
+
+func (op *DropEnumOp) GetReverse() Operation {
+	return &CreateEnumOp{Enum: op.Enum}
+}
+
+func (op *DropEnumOp) DependsOn(another Operation) bool {
+	switch another := another.(type) {
+	case *DropTableOp:
+		return true
+	case *DropColumnOp:
+		return strings.EqualFold(another.Column.GetSQLType(), op.Enum.Name)
+	case *ChangeColumnTypeOp:
+		return strings.EqualFold(another.From.GetSQLType(), op.Enum.Name)
+	}
+	return false
+}
+
+// AlterEnumAddValueOp adds a new value to the enum type.
+// The value is placed before the Before value or after the After value, if either is set,
+// and at the end of the list otherwise.
+//
+// This operation is not reversible, because databases do not support removing enum values.
+type AlterEnumAddValueOp struct {
+	EnumName string
+	Value    string
+	Before   string
+	After    string
+}
+
+var _ Operation = (*AlterEnumAddValueOp)(nil)
+
+func (op *AlterEnumAddValueOp) GetReverse() Operation {
+	c := Unimplemented(fmt.Sprintf("WARNING: value %q cannot be removed from enum %s automatically", op.Value, op.EnumName))
+	return &c
+}
+
+// DependsOn reports dependency on the values added before this one, if it is placed relative to them.
+func (op *AlterEnumAddValueOp) DependsOn(another Operation) bool {
+	add, ok := another.(*AlterEnumAddValueOp)
+	return ok && op.EnumName == add.EnumName && (add.Value == op.Before || add.Value == op.After)
+}
+
+// SetCommentOp sets the comment on the column. Empty Comment removes it.
+// OldComment is used to revert the change.
+type SetCommentOp struct {
+	TableName  string
+	ColumnName string
+	Comment    string
+	OldComment string
+}
+
+var _ Operation = (*SetCommentOp)(nil)
+
+func (op *SetCommentOp) GetReverse() Operation {
+	return &SetCommentOp{
+		TableName:  op.TableName,
+		ColumnName: op.ColumnName,
+		Comment:    op.OldComment,
+		OldComment: op.Comment,
+	}
+}
+
+func (op *SetCommentOp) DependsOn(another Operation) bool {
+	switch another := another.(type) {
+	case *CreateTableOp:
+		return op.TableName == another.TableName
+	case *RenameTableOp:
+		return op.TableName == another.NewName
+	case *AddColumnOp:
+		return op.TableName == another.TableName && op.ColumnName == another.ColumnName
+	case *RenameColumnOp:
+		return op.TableName == another.TableName && op.ColumnName == another.NewName
+	}
+	return false
+}
+
+// ValidateConstraintOp checks the existing rows against a constraint which was added as NOT VALID.
+// Unlike adding a constraint, validation does not block writes to the table.
+type ValidateConstraintOp struct {
+	TableName      string
+	ConstraintName string
+}
+
+var _ Operation = (*ValidateConstraintOp)(nil)
+
+func (op *ValidateConstraintOp) GetReverse() Operation {
+	c := Unimplemented(fmt.Sprintf("WARNING: constraint %s cannot be marked NOT VALID again", op.ConstraintName))
+	return &c
+}
+
+func (op *ValidateConstraintOp) DependsOn(another Operation) bool {
+	switch another := another.(type) {
+	case *AddForeignKeyOp:
+		return op.TableName == another.TableName() && op.ConstraintName == another.ConstraintName
+	case *AddCheckOp:
+		return op.TableName == another.TableName && op.ConstraintName == another.Check.Name
+	}
+	return false
+}
+
+// SetLockTimeoutOp limits the time the following statements wait to acquire a lock on a table.
+// A zero Timeout resets the setting to the database default.
+//
+// AutoMigrator adds it around online migrations, see WithOnlineMigrations.
+type SetLockTimeoutOp struct {
+	Timeout time.Duration
+}
+
+var _ Operation = (*SetLockTimeoutOp)(nil)
+
+func (op *SetLockTimeoutOp) GetReverse() Operation {
+	return op
+}
+
+// dependsOnEnum checks if the column uses the enum type which is created or altered by another operation.
+func dependsOnEnum(column sqlschema.Column, another Operation) bool {
+	if column == nil {
+		return false
+	}
+	switch another := another.(type) {
+	case *CreateEnumOp:
+		return strings.EqualFold(column.GetSQLType(), another.Enum.Name)
+	case *AlterEnumAddValueOp:
+		return strings.EqualFold(column.GetSQLType(), another.EnumName)
+	}
+	return false
+}
+
 // Unimplemented denotes an Operation that cannot be executed.
 //
 // Operations, which cannot be reversed due to current technical limitations,
@@ -0,0 +1,134 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Safety classifies operations by their effect on the data stored in the database.
+type Safety int
+
+const (
+	// SafeChange modifies the schema without affecting the stored data,
+	// e.g. CreateTableOp, AddColumnOp, RenameColumnOp or DropIndexOp.
+	SafeChange Safety = iota
+
+	// LossyChange keeps the table and its columns, but may alter or truncate
+	// the stored values, e.g. ChangeColumnTypeOp which changes the data type.
+	LossyChange
+
+	// DestructiveChange irrecoverably deletes data, e.g. DropTableOp and DropColumnOp.
+	DestructiveChange
+)
+
+func (s Safety) String() string {
+	switch s {
+	case SafeChange:
+		return "safe"
+	case LossyChange:
+		return "lossy"
+	case DestructiveChange:
+		return "destructive"
+	}
+	return fmt.Sprintf("Safety(%d)", int(s))
+}
+
+// ClassifyOperation reports how the operation affects the data stored in the database.
+func ClassifyOperation(op Operation) Safety {
+	switch op := op.(type) {
+	case *DropTableOp, *DropColumnOp:
+		return DestructiveChange
+	case *ChangeColumnTypeOp:
+		from, to := op.From, op.To
+		// AutoMigrator keeps the current type if it is equivalent to the new one,
+		// so any difference in the type name means that the data will be converted.
+		if !strings.EqualFold(from.GetSQLType(), to.GetSQLType()) {
+			return LossyChange
+		}
+		if to.GetVarcharLen() != 0 && (from.GetVarcharLen() == 0 || to.GetVarcharLen() < from.GetVarcharLen()) {
+			return LossyChange
+		}
+	}
+	return SafeChange
+}
+
+// Plan is the list of operations AutoMigrator would apply to bring the database schema
+// in line with the models, in the order of their execution.
+type Plan struct {
+	Operations []PlannedOperation
+}
+
+// PlannedOperation is an operation with its safety classification.
+type PlannedOperation struct {
+	Operation Operation
+	Safety    Safety
+}
+
+func newPlan(changes *changeset) *Plan {
+	plan := &Plan{
+		Operations: make([]PlannedOperation, 0, len(changes.operations)),
+	}
+	for _, op := range changes.operations {
+		plan.Operations = append(plan.Operations, PlannedOperation{
+			Operation: op,
+			Safety:    ClassifyOperation(op),
+		})
+	}
+	return plan
+}
+
+// IsZero returns true if the database schema is up to date.
+func (p *Plan) IsZero() bool {
+	return p == nil || len(p.Operations) == 0
+}
+
+// Safety returns the classification of the most dangerous operation in the plan.
+func (p *Plan) Safety() Safety {
+	var safety Safety
+	for _, op := range p.Operations {
+		if op.Safety > safety {
+			safety = op.Safety
+		}
+	}
+	return safety
+}
+
+// Unsafe returns the operations which are classified above the allowed level.
+func (p *Plan) Unsafe(allowed Safety) []PlannedOperation {
+	var unsafe []PlannedOperation
+	for _, op := range p.Operations {
+		if op.Safety > allowed {
+			unsafe = append(unsafe, op)
+		}
+	}
+	return unsafe
+}
+
+// UnsafeChangesError is returned when the migration includes operations
+// which are not allowed by the safety policy, see WithSafetyPolicy.
+type UnsafeChangesError struct {
+	Allowed    Safety
+	Operations []PlannedOperation
+}
+
+func (e *UnsafeChangesError) Error() string {
+	ops := make([]string, len(e.Operations))
+	for i, op := range e.Operations {
+		ops[i] = fmt.Sprintf("%s (%s)", describeOperation(op.Operation), op.Safety)
+	}
+	return fmt.Sprintf("migrate: changes are not allowed by the safety policy (%s): %s",
+		e.Allowed, strings.Join(ops, ", "))
+}
+
+// describeOperation returns a short description of the operations which are not always safe.
+func describeOperation(op Operation) string {
+	switch op := op.(type) {
+	case *DropTableOp:
+		return "drop table " + op.TableName
+	case *DropColumnOp:
+		return "drop column " + op.TableName + "." + op.ColumnName
+	case *ChangeColumnTypeOp:
+		return "change type of column " + op.TableName + "." + op.Column
+	}
+	return fmt.Sprintf("%T", op)
+}
@@ -14,6 +14,7 @@ type Column interface {
 	GetIsNullable() bool
 	GetIsAutoIncrement() bool
 	GetIsIdentity() bool
+	GetComment() string
 	AppendQuery(schema.QueryGen, []byte) ([]byte, error)
 }
 
@@ -31,6 +32,7 @@ type BaseColumn struct {
 	IsNullable      bool
 	IsAutoIncrement bool
 	IsIdentity      bool
+	Comment         string
 	// TODO: add Precision and Cardinality for timestamps/bit-strings/floats and arrays respectively.
 }
 
@@ -62,6 +64,10 @@ func (cd BaseColumn) GetIsIdentity() bool {
 	return cd.IsIdentity
 }
 
+func (cd BaseColumn) GetComment() string {
+	return cd.Comment
+}
+
 // AppendQuery appends full SQL data type.
 func (c *BaseColumn) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err error) {
 	b = append(b, c.SQLType...)
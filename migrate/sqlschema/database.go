@@ -10,6 +10,7 @@ import (
 type Database interface {
 	GetTables() []Table
 	GetForeignKeys() map[ForeignKey]string
+	GetEnums() []Enum
 }
 
 var _ Database = (*BaseDatabase)(nil)
@@ -21,6 +22,7 @@ var _ Database = (*BaseDatabase)(nil)
 type BaseDatabase struct {
 	Tables      []Table
 	ForeignKeys map[ForeignKey]string
+	Enums       []Enum
 }
 
 func (ds BaseDatabase) GetTables() []Table {
@@ -31,6 +33,22 @@ func (ds BaseDatabase) GetForeignKeys() map[ForeignKey]string {
 	return ds.ForeignKeys
 }
 
+func (ds BaseDatabase) GetEnums() []Enum {
+	return ds.Enums
+}
+
+// Enum represents a user-defined enum type, e.g. CREATE TYPE mood AS ENUM ('sad', 'ok', 'happy').
+type Enum struct {
+	Name string
+
+	// Values holds the labels of the enum in their sort order.
+	Values []string
+}
+
+func (e Enum) GetName() string {
+	return e.Name
+}
+
 type ForeignKey struct {
 	From ColumnReference
 	To   ColumnReference
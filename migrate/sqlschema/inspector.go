@@ -3,6 +3,7 @@ package sqlschema
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -154,7 +155,15 @@ func (bmi *BunModelInspector) Inspect(ctx context.Context) (Database, error) {
 				IsNullable:      !f.NotNull,
 				IsAutoIncrement: f.AutoIncrement,
 				IsIdentity:      f.Identity,
+				Comment:         f.Comment,
 			})
+
+			// Models which share an enum type should declare the same values; the first declaration wins.
+			if len(f.EnumValues) > 0 && !slices.ContainsFunc(state.Enums, func(e Enum) bool {
+				return e.Name == f.UserSQLType
+			}) {
+				state.Enums = append(state.Enums, Enum{Name: f.UserSQLType, Values: f.EnumValues})
+			}
 		}
 
 		var unique []Unique
@@ -176,6 +185,24 @@ func (bmi *BunModelInspector) Inspect(ctx context.Context) (Database, error) {
 			unique = append(unique, Unique{Name: name, Columns: NewColumns(columns...)})
 		}
 
+		var indexes []Index
+		for _, index := range t.Indexes {
+			indexes = append(indexes, Index{
+				Name:    index.Name,
+				Unique:  index.Unique,
+				Columns: index.Columns,
+				Expr:    index.Expr,
+				Using:   index.Using,
+				Include: index.Include,
+				Where:   index.Where,
+			})
+		}
+
+		var checks []Check
+		for _, check := range t.Checks {
+			checks = append(checks, Check{Name: check.Name, Expr: check.Expr})
+		}
+
 		var pk *PrimaryKey
 		if len(t.PKs) > 0 {
 			var columns []string
@@ -199,6 +226,8 @@ func (bmi *BunModelInspector) Inspect(ctx context.Context) (Database, error) {
 				Columns:           columns,
 				UniqueConstraints: unique,
 				PrimaryKey:        pk,
+				Indexes:           indexes,
+				Checks:            checks,
 			},
 			Model: t.ZeroIface,
 		})
@@ -229,12 +258,18 @@ func (bmi *BunModelInspector) Inspect(ctx context.Context) (Database, error) {
 	return state, nil
 }
 
+// parseLen splits the type into its name and length, e.g. VARCHAR(255) -> VARCHAR, 255.
+// Types with unbounded length, e.g. NVARCHAR(MAX), are returned as-is.
 func parseLen(typ string) (string, int, error) {
 	paren := strings.Index(typ, "(")
 	if paren == -1 {
 		return typ, 0, nil
 	}
-	length, err := strconv.Atoi(typ[paren+1 : len(typ)-1])
+	arg := typ[paren+1 : len(typ)-1]
+	if strings.EqualFold(arg, "max") {
+		return typ, 0, nil
+	}
+	length, err := strconv.Atoi(arg)
 	if err != nil {
 		return typ, 0, err
 	}
@@ -2,8 +2,11 @@ package sqlschema
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -12,15 +15,57 @@ type MigratorDialect interface {
 	NewMigrator(db *bun.DB, schemaName string) Migrator
 }
 
+// Migrator generates SQL queries for migrate.Operation.
+//
+// Implementations may keep track of the operations they have processed,
+// in which case the operations must be passed to AppendSQL in the order
+// they are going to be applied.
 type Migrator interface {
 	AppendSQL(b []byte, operation any) ([]byte, error)
 }
 
+// DatabaseTracker is implemented by migrators which inspect the database to get
+// the complete definition of a table before altering it, e.g. SQLite.
+// TrackDatabase makes them use the definitions from the state instead,
+// which allows generating migrations without a database connection.
+type DatabaseTracker interface {
+	TrackDatabase(state Database)
+}
+
+// TxMigrator is implemented by migrators which generate SQL that cannot be run
+// in a transaction for some operations, e.g. SQLite, which cannot disable foreign keys
+// in a transaction while re-creating a table.
+type TxMigrator interface {
+	// Transactional reports whether the SQL appended for the operation by AppendSQL
+	// can be run in a transaction.
+	Transactional(operation any) bool
+}
+
 // migrator is a dialect-agnostic wrapper for sqlschema.MigratorDialect.
 type migrator struct {
 	Migrator
 }
 
+var (
+	_ DatabaseTracker = (*migrator)(nil)
+	_ TxMigrator      = (*migrator)(nil)
+)
+
+// TrackDatabase passes the state to the dialect's migrator if it keeps track of the tables.
+func (m *migrator) TrackDatabase(state Database) {
+	if t, ok := m.Migrator.(DatabaseTracker); ok {
+		t.TrackDatabase(state)
+	}
+}
+
+// Transactional asks the dialect's migrator if the operation can be run in a transaction.
+func (m *migrator) Transactional(operation any) bool {
+	if t, ok := m.Migrator.(TxMigrator); ok {
+		return t.Transactional(operation)
+	}
+	return true
+}
+
 func NewMigrator(db *bun.DB, schemaName string) (Migrator, error) {
 	md, ok := db.Dialect().(MigratorDialect)
 	if !ok {
@@ -44,6 +89,173 @@ func (m *BaseMigrator) AppendCreateTable(b []byte, model any) ([]byte, error) {
 	return m.db.NewCreateTable().Model(model).AppendQuery(m.db.QueryGen(), b)
 }
 
+// AppendTableDefinition appends the parenthesized definition of the table for CREATE TABLE statement:
+// its columns, primary key, unique and check constraints. Indexes and foreign keys are created separately.
+// It is used to re-create an inspected table, which has no model.
+func (m *BaseMigrator) AppendTableDefinition(b []byte, table Table) (_ []byte, err error) {
+	gen := m.db.QueryGen()
+	sequence := gen.HasFeature(feature.GeneratedIdentity) ||
+		gen.HasFeature(feature.AutoIncrement) ||
+		gen.HasFeature(feature.Identity)
+
+	b = append(b, " ("...)
+	for i, col := range table.GetColumns() {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = gen.AppendName(b, col.GetName())
+		b = append(b, " "...)
+		if b, err = col.AppendQuery(gen, b); err != nil {
+			return b, err
+		}
+
+		if !col.GetIsNullable() {
+			b = append(b, " NOT NULL"...)
+		}
+
+		if sequence && (col.GetIsIdentity() || col.GetIsAutoIncrement()) {
+			field := &schema.Field{
+				Name:               col.GetName(),
+				SQLName:            schema.Safe(gen.AppendName(nil, col.GetName())),
+				CreateTableSQLType: col.GetSQLType(),
+			}
+			b = m.db.Dialect().AppendSequence(b, &schema.Table{Name: table.GetName()}, field)
+		}
+
+		if def := col.GetDefaultValue(); def != "" {
+			b = append(b, " DEFAULT "...)
+			b = appendDefault(gen, b, def)
+		}
+	}
+
+	if pk := table.GetPrimaryKey(); pk != nil {
+		b = append(b, ", PRIMARY KEY ("...)
+		b, _ = pk.Columns.AppendQuery(gen, b)
+		b = append(b, ")"...)
+	}
+
+	for _, unique := range table.GetUniqueConstraints() {
+		b = append(b, ", "...)
+		if unique.Name != "" {
+			b = append(b, "CONSTRAINT "...)
+			b = gen.AppendName(b, unique.Name)
+			b = append(b, " "...)
+		}
+		b = append(b, "UNIQUE ("...)
+		b, _ = unique.Columns.AppendQuery(gen, b)
+		b = append(b, ")"...)
+	}
+
+	for _, check := range table.GetChecks() {
+		b = append(b, ", "...)
+		if check.Name != "" {
+			b = append(b, "CONSTRAINT "...)
+			b = gen.AppendName(b, check.Name)
+			b = append(b, " "...)
+		}
+		b = append(b, "CHECK ("...)
+		b = append(b, check.Expr...)
+		b = append(b, ")"...)
+	}
+
+	b = append(b, ")"...)
+	return b, nil
+}
+
+// appendDefault appends the default value of an inspected column. Inspectors report string literals
+// without quotes, so the value is quoted unless it is a number, a keyword or an expression.
+func appendDefault(gen schema.QueryGen, b []byte, def string) []byte {
+	if isDefaultExpr(def) {
+		return append(b, def...)
+	}
+	return gen.Dialect().AppendString(b, def)
+}
+
+// isDefaultExpr reports whether the default value can be used in the DEFAULT clause as-is.
+func isDefaultExpr(s string) bool {
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "true", "false",
+		"current_timestamp", "current_date", "current_time", "localtime", "localtimestamp",
+		"current_user", "session_user", "user":
+		return true
+	}
+	switch {
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'',
+		strings.HasPrefix(s, "("),
+		strings.Contains(s, "::"):
+		return true
+	}
+	return isFunctionCall(s)
+}
+
+// isFunctionCall reports whether s looks like a function call, e.g. gen_random_uuid().
+func isFunctionCall(s string) bool {
+	i := strings.IndexByte(s, '(')
+	if i <= 0 || !strings.HasSuffix(s, ")") {
+		return false
+	}
+	for _, r := range s[:i] {
+		if r != '_' && r != '.' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *BaseMigrator) AppendDropTable(b []byte, schemaName, tableName string) ([]byte, error) {
 	return m.db.NewDropTable().TableExpr("?.?", bun.Ident(schemaName), bun.Ident(tableName)).AppendQuery(m.db.QueryGen(), b)
 }
+
+// AppendCreateIndex appends CREATE INDEX statement for the index on the table in the schema.
+func (m *BaseMigrator) AppendCreateIndex(b []byte, schemaName, tableName string, index Index) ([]byte, error) {
+	return m.NewCreateIndexQuery(schemaName, tableName, index).AppendQuery(m.db.QueryGen(), b)
+}
+
+// NewCreateIndexQuery creates a CREATE INDEX query for the index on the table in the schema,
+// which dialects can extend with their own options.
+func (m *BaseMigrator) NewCreateIndexQuery(schemaName, tableName string, index Index) *bun.CreateIndexQuery {
+	q := m.db.NewCreateIndex().
+		Index(IndexName(tableName, index)).
+		TableExpr("?.?", bun.Ident(schemaName), bun.Ident(tableName))
+	if index.Unique {
+		q = q.Unique()
+	}
+	if index.Using != "" {
+		q = q.Using(index.Using)
+	}
+	if index.Expr != "" {
+		q = q.ColumnExpr("?", bun.Safe(index.Expr))
+	} else {
+		q = q.Column(index.Columns...)
+	}
+	if len(index.Include) > 0 {
+		q = q.Include(index.Include...)
+	}
+	if index.Where != "" {
+		q = q.Where("?", bun.Safe(index.Where))
+	}
+	return q
+}
+
+func (m *BaseMigrator) AppendDropIndex(b []byte, schemaName, indexName string) ([]byte, error) {
+	return m.db.NewDropIndex().Index("?.?", bun.Ident(schemaName), bun.Ident(indexName)).AppendQuery(m.db.QueryGen(), b)
+}
+
+// IndexName returns the name of the index or generates one using
+// the same naming scheme as Postgres: <table>_<column>_idx.
+// Words in the index expression are used in place of column names.
+func IndexName(tableName string, index Index) string {
+	if index.Name != "" {
+		return index.Name
+	}
+	parts := index.Columns
+	if index.Expr != "" {
+		parts = strings.FieldsFunc(strings.ToLower(index.Expr), func(r rune) bool {
+			return r != '_' && (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		})
+	}
+	return fmt.Sprintf("%s_%s_idx", tableName, strings.Join(parts, "_"))
+}
@@ -0,0 +1,252 @@
+package sqlschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// snapshotVersion is incremented whenever the snapshot format changes incompatibly.
+const snapshotVersion = 1
+
+// snapshot is the JSON representation of a database schema.
+// Its fields have explicit names, so that renaming the Go fields does not change the format.
+type snapshot struct {
+	Version     int                  `json:"version"`
+	Enums       []snapshotEnum       `json:"enums,omitempty"`
+	Tables      []snapshotTable      `json:"tables"`
+	ForeignKeys []snapshotForeignKey `json:"foreign_keys,omitempty"`
+}
+
+type snapshotEnum struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type snapshotTable struct {
+	Schema            string               `json:"schema,omitempty"`
+	Name              string               `json:"name"`
+	Columns           []snapshotColumn     `json:"columns"`
+	PrimaryKey        *snapshotConstraint  `json:"primary_key,omitempty"`
+	UniqueConstraints []snapshotConstraint `json:"unique_constraints,omitempty"`
+	Indexes           []snapshotIndex      `json:"indexes,omitempty"`
+	Checks            []snapshotCheck      `json:"checks,omitempty"`
+}
+
+type snapshotColumn struct {
+	Name            string `json:"name"`
+	SQLType         string `json:"sql_type"`
+	VarcharLen      int    `json:"varchar_len,omitempty"`
+	DefaultValue    string `json:"default,omitempty"`
+	IsNullable      bool   `json:"nullable,omitempty"`
+	IsAutoIncrement bool   `json:"auto_increment,omitempty"`
+	IsIdentity      bool   `json:"identity,omitempty"`
+	Comment         string `json:"comment,omitempty"`
+}
+
+type snapshotConstraint struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+}
+
+type snapshotIndex struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+	Expr    string   `json:"expr,omitempty"`
+	Using   string   `json:"using,omitempty"`
+	Include []string `json:"include,omitempty"`
+	Where   string   `json:"where,omitempty"`
+}
+
+type snapshotCheck struct {
+	Name string `json:"name,omitempty"`
+	Expr string `json:"expr"`
+}
+
+type snapshotForeignKey struct {
+	Name       string   `json:"name,omitempty"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+}
+
+// WriteSnapshot writes the database schema to w as indented JSON.
+//
+// The output is stable: enums, tables, constraints, indexes and foreign keys are sorted
+// by name, while columns keep their order. Writing the same schema twice produces
+// identical output, which makes the snapshot suitable for committing to version control.
+func WriteSnapshot(w io.Writer, db Database) error {
+	s := snapshot{
+		Version: snapshotVersion,
+		Tables:  []snapshotTable{},
+	}
+
+	for _, enum := range db.GetEnums() {
+		s.Enums = append(s.Enums, snapshotEnum{Name: enum.Name, Values: enum.Values})
+	}
+	slices.SortFunc(s.Enums, func(a, b snapshotEnum) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, table := range db.GetTables() {
+		s.Tables = append(s.Tables, newSnapshotTable(table))
+	}
+	slices.SortFunc(s.Tables, func(a, b snapshotTable) int {
+		if c := strings.Compare(a.Schema, b.Schema); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for fk, name := range db.GetForeignKeys() {
+		s.ForeignKeys = append(s.ForeignKeys, snapshotForeignKey{
+			Name:       name,
+			Table:      fk.From.TableName,
+			Columns:    fk.From.Column.Split(),
+			RefTable:   fk.To.TableName,
+			RefColumns: fk.To.Column.Split(),
+		})
+	}
+	slices.SortFunc(s.ForeignKeys, func(a, b snapshotForeignKey) int {
+		return strings.Compare(a.key(), b.key())
+	})
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = w.Write(b)
+	return err
+}
+
+func newSnapshotTable(table Table) snapshotTable {
+	st := snapshotTable{
+		Schema:  table.GetSchema(),
+		Name:    table.GetName(),
+		Columns: []snapshotColumn{},
+	}
+
+	for _, col := range table.GetColumns() {
+		st.Columns = append(st.Columns, snapshotColumn{
+			Name:            col.GetName(),
+			SQLType:         col.GetSQLType(),
+			VarcharLen:      col.GetVarcharLen(),
+			DefaultValue:    col.GetDefaultValue(),
+			IsNullable:      col.GetIsNullable(),
+			IsAutoIncrement: col.GetIsAutoIncrement(),
+			IsIdentity:      col.GetIsIdentity(),
+			Comment:         col.GetComment(),
+		})
+	}
+
+	if pk := table.GetPrimaryKey(); pk != nil {
+		st.PrimaryKey = &snapshotConstraint{Name: pk.Name, Columns: pk.Columns.Split()}
+	}
+
+	for _, unique := range table.GetUniqueConstraints() {
+		st.UniqueConstraints = append(st.UniqueConstraints, snapshotConstraint{
+			Name:    unique.Name,
+			Columns: unique.Columns.Split(),
+		})
+	}
+	slices.SortFunc(st.UniqueConstraints, func(a, b snapshotConstraint) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return slices.Compare(a.Columns, b.Columns)
+	})
+
+	for _, index := range table.GetIndexes() {
+		st.Indexes = append(st.Indexes, snapshotIndex(index))
+	}
+	slices.SortFunc(st.Indexes, func(a, b snapshotIndex) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, check := range table.GetChecks() {
+		st.Checks = append(st.Checks, snapshotCheck(check))
+	}
+	slices.SortFunc(st.Checks, func(a, b snapshotCheck) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Expr, b.Expr)
+	})
+
+	return st
+}
+
+func (fk snapshotForeignKey) key() string {
+	return fmt.Sprintf("%s(%s) %s(%s)",
+		fk.Table, strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","))
+}
+
+// ReadSnapshot reads the database schema written by WriteSnapshot.
+func ReadSnapshot(r io.Reader) (Database, error) {
+	var s snapshot
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("sqlschema: invalid snapshot: %w", err)
+	}
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("sqlschema: unsupported snapshot version %d", s.Version)
+	}
+
+	db := BaseDatabase{
+		ForeignKeys: make(map[ForeignKey]string, len(s.ForeignKeys)),
+	}
+	for _, enum := range s.Enums {
+		db.Enums = append(db.Enums, Enum{Name: enum.Name, Values: enum.Values})
+	}
+	for _, st := range s.Tables {
+		db.Tables = append(db.Tables, st.table())
+	}
+	for _, fk := range s.ForeignKeys {
+		db.ForeignKeys[ForeignKey{
+			From: NewColumnReference(fk.Table, fk.Columns...),
+			To:   NewColumnReference(fk.RefTable, fk.RefColumns...),
+		}] = fk.Name
+	}
+	return db, nil
+}
+
+func (st snapshotTable) table() *BaseTable {
+	table := &BaseTable{
+		Schema: st.Schema,
+		Name:   st.Name,
+	}
+	for _, col := range st.Columns {
+		table.Columns = append(table.Columns, &BaseColumn{
+			Name:            col.Name,
+			SQLType:         col.SQLType,
+			VarcharLen:      col.VarcharLen,
+			DefaultValue:    col.DefaultValue,
+			IsNullable:      col.IsNullable,
+			IsAutoIncrement: col.IsAutoIncrement,
+			IsIdentity:      col.IsIdentity,
+			Comment:         col.Comment,
+		})
+	}
+	if pk := st.PrimaryKey; pk != nil {
+		table.PrimaryKey = &PrimaryKey{Name: pk.Name, Columns: NewColumns(pk.Columns...)}
+	}
+	for _, unique := range st.UniqueConstraints {
+		table.UniqueConstraints = append(table.UniqueConstraints, Unique{
+			Name:    unique.Name,
+			Columns: NewColumns(unique.Columns...),
+		})
+	}
+	for _, index := range st.Indexes {
+		table.Indexes = append(table.Indexes, Index(index))
+	}
+	for _, check := range st.Checks {
+		table.Checks = append(table.Checks, Check(check))
+	}
+	return table
+}
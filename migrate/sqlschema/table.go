@@ -1,11 +1,19 @@
 package sqlschema
 
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
 type Table interface {
 	GetSchema() string
 	GetName() string
 	GetColumns() []Column
 	GetPrimaryKey() *PrimaryKey
 	GetUniqueConstraints() []Unique
+	GetIndexes() []Index
+	GetChecks() []Check
 }
 
 var _ Table = (*BaseTable)(nil)
@@ -27,6 +35,12 @@ type BaseTable struct {
 
 	// UniqueConstraints defined on the table.
 	UniqueConstraints []Unique
+
+	// Indexes defined on the table, excluding the ones which back PRIMARY KEY and UNIQUE constraints.
+	Indexes []Index
+
+	// Checks holds CHECK constraints defined on the table.
+	Checks []Check
 }
 
 // PrimaryKey represents a primary key constraint defined on 1 or more columns.
@@ -35,6 +49,82 @@ type PrimaryKey struct {
 	Columns Columns
 }
 
+// Index represents an index defined on the table.
+type Index struct {
+	Name   string
+	Unique bool
+
+	// Columns holds the names of the indexed columns in the order they appear in the index.
+	Columns []string
+
+	// Expr is the indexed expression. Inspectors report all key columns
+	// in Expr if at least one of them is an expression.
+	Expr string
+
+	// Using is the index access method. An empty value means the default one.
+	Using   string
+	Include []string
+	Where   string
+}
+
+// Equals checks that two indexes have the same definition, assuming both are defined for the same table.
+// Index names are not compared, the same way they are not compared for unique constraints.
+//
+// Expressions are compared after normalization, which ignores letter case, whitespace,
+// quotes, parentheses and type casts, because databases often report them in a different
+// form than they were written in. For reliable results, write the expression in the model
+// the way the database reports it.
+func (i Index) Equals(other Index) bool {
+	return i.Unique == other.Unique &&
+		slices.Equal(i.Columns, other.Columns) &&
+		normalizeExpr(i.Expr) == normalizeExpr(other.Expr) &&
+		normalizeMethod(i.Using) == normalizeMethod(other.Using) &&
+		slices.Equal(i.Include, other.Include) &&
+		normalizeExpr(i.Where) == normalizeExpr(other.Where)
+}
+
+// DependsOnColumn checks if the column is indexed or included in the index.
+// Columns referenced in expressions are not detected.
+func (i Index) DependsOnColumn(column string) bool {
+	return slices.Contains(i.Columns, column) || slices.Contains(i.Include, column)
+}
+
+// Check represents a CHECK constraint defined on the table.
+type Check struct {
+	Name string
+	Expr string
+}
+
+// Equals checks that two CHECK constraints have the same expression, assuming both are defined for the same table.
+// Expressions are normalized the same way as index expressions, see Index.Equals.
+func (c Check) Equals(other Check) bool {
+	return normalizeExpr(c.Expr) == normalizeExpr(other.Expr)
+}
+
+// reTypeCast matches type casts, e.g. ::text, in an expression stripped of whitespace.
+var reTypeCast = regexp.MustCompile(`::[a-z_]+(\[\])?`)
+
+// normalizeExpr converts the expression to a form in which it can be compared with
+// the same expression reported by the database.
+func normalizeExpr(expr string) string {
+	expr = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r', '"', '`', '[', ']', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.ToLower(expr))
+	return reTypeCast.ReplaceAllString(expr, "")
+}
+
+// normalizeMethod treats B-tree, which is the default index method, as if it was not specified.
+func normalizeMethod(method string) string {
+	if method = strings.ToLower(method); method == "btree" {
+		return ""
+	}
+	return method
+}
+
 func (td *BaseTable) GetSchema() string {
 	return td.Schema
 }
@@ -54,3 +144,11 @@ func (td *BaseTable) GetPrimaryKey() *PrimaryKey {
 func (td *BaseTable) GetUniqueConstraints() []Unique {
 	return td.UniqueConstraints
 }
+
+func (td *BaseTable) GetIndexes() []Index {
+	return td.Indexes
+}
+
+func (td *BaseTable) GetChecks() []Check {
+	return td.Checks
+}
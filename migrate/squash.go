@@ -0,0 +1,233 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/migrate/sqlschema"
+)
+
+// squashedDirective lists the migrations replaced by a baseline in the first line of its up file.
+const squashedDirective = "--bun:squashed "
+
+// Squash replaces the migrations up to and including the cutoff with a single baseline migration,
+// which re-creates the current database schema. The database must have all migrations up to
+// the cutoff applied and none of the later ones, so that its schema matches the cutoff.
+//
+// The baseline takes the name of the cutoff migration, so that new databases apply it
+// instead of the squashed migrations, while the databases which have already applied the cutoff
+// consider the baseline applied and keep the history of the squashed migrations.
+// Squash records which baseline replaced them in the migrations table of this database;
+// in other databases they are recognized with the --bun:squashed directive of the baseline.
+//
+// The SQL files of the squashed migrations are removed from the migrations directory.
+// Go migrations must be removed manually.
+func (m *Migrator) Squash(ctx context.Context, cutoff string) ([]*MigrationFile, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	migrations, _, err := m.migrationsWithStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := migrations.find(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	var squashed MigrationSlice
+	for i := range migrations {
+		migration := &migrations[i]
+		if !migrationLess(*target, *migration) {
+			if !migration.IsApplied() {
+				return nil, fmt.Errorf("migrate: squash: migration %s is not applied", migration)
+			}
+			squashed = append(squashed, *migration)
+		} else if migration.IsApplied() {
+			return nil, fmt.Errorf("migrate: squash: migration %s is applied after %s (roll it back first)",
+				migration, target)
+		}
+	}
+
+	schemaName := m.db.Dialect().DefaultSchema()
+	inspector, err := sqlschema.NewInspector(m.db,
+		sqlschema.WithSchemaName(schemaName),
+		sqlschema.WithExcludeTables(m.ownTables()...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: squash: %w", err)
+	}
+	dbMigrator, err := sqlschema.NewMigrator(m.db, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: squash: %w", err)
+	}
+
+	state, err := inspector.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: squash: %w", err)
+	}
+	up := m.baselineChanges(state)
+
+	names := make([]string, len(squashed))
+	for i := range squashed {
+		names[i] = squashed[i].Name
+	}
+
+	var upBuf, downBuf bytes.Buffer
+	upBuf.WriteString(squashedDirective + strings.Join(names, " ") + "\n")
+	if err := up.writeTo(&upBuf, dbMigrator, ";\n--bun:split\n"); err != nil {
+		return nil, fmt.Errorf("migrate: squash: %w", err)
+	}
+	if err := up.GetReverse().writeTo(&downBuf, dbMigrator, ";\n--bun:split\n"); err != nil {
+		return nil, fmt.Errorf("migrate: squash: %w", err)
+	}
+
+	if err := m.upgradeTable(ctx); err != nil {
+		return nil, err
+	}
+
+	// Write the baseline and record it in the migrations table before removing the squashed
+	// migrations, so that a failure does not leave the directory without either of them.
+	dir := m.migrations.getDirectory()
+	var files []*MigrationFile
+	for _, f := range []struct {
+		direction string
+		content   []byte
+	}{
+		{"up", upBuf.Bytes()},
+		{"down", downBuf.Bytes()},
+	} {
+		fname := fmt.Sprintf("%s_baseline.%s.sql", target.Name, f.direction)
+		fpath := filepath.Join(dir, fname)
+		if err := os.WriteFile(fpath, f.content, 0o644); err != nil {
+			return nil, err
+		}
+		files = append(files, &MigrationFile{
+			Name:    fname,
+			Path:    fpath,
+			Content: string(f.content),
+		})
+	}
+
+	if err := m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().
+			Model((*Migration)(nil)).
+			ModelTableExpr(m.table).
+			Set("? = ?", bun.Ident("squashed_into"), target.Name).
+			Where("? IN (?)", bun.Ident("name"), bun.In(names)).
+			Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().
+			Model((*Migration)(nil)).
+			ModelTableExpr(m.table).
+			Set("? = ?", bun.Ident("checksum"), checksum(upBuf.Bytes())).
+			Where("? = ?", bun.Ident("name"), target.Name).
+			Exec(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	keep := make([]string, len(files))
+	for i, f := range files {
+		keep[i] = f.Name
+	}
+	if err := removeSQLFiles(dir, names, keep); err != nil {
+		return nil, fmt.Errorf("migrate: squash: %w", err)
+	}
+
+	return files, nil
+}
+
+// baselineChanges returns the operations which re-create the inspected database schema.
+func (m *Migrator) baselineChanges(state sqlschema.Database) *changeset {
+	var c changeset
+	for _, enum := range state.GetEnums() {
+		c.Add(&CreateEnumOp{Enum: enum})
+	}
+
+	tables := slices.Clone(state.GetTables())
+	slices.SortFunc(tables, func(a, b sqlschema.Table) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	comments := m.db.HasFeature(feature.CommentOnColumn)
+	for _, table := range tables {
+		c.Add(&CreateTableOp{TableName: table.GetName(), Table: table})
+		for _, index := range table.GetIndexes() {
+			c.Add(&CreateIndexOp{TableName: table.GetName(), Index: index})
+		}
+		if !comments {
+			continue
+		}
+		for _, col := range table.GetColumns() {
+			if col.GetComment() != "" {
+				c.Add(&SetCommentOp{TableName: table.GetName(), ColumnName: col.GetName(), Comment: col.GetComment()})
+			}
+		}
+	}
+
+	// Foreign keys are added once all tables exist.
+	fks := state.GetForeignKeys()
+	keys := make([]sqlschema.ForeignKey, 0, len(fks))
+	for fk := range fks {
+		keys = append(keys, fk)
+	}
+	slices.SortFunc(keys, func(a, b sqlschema.ForeignKey) int {
+		return strings.Compare(foreignKeyString(a), foreignKeyString(b))
+	})
+	for _, fk := range keys {
+		c.Add(&AddForeignKeyOp{ForeignKey: fk, ConstraintName: fks[fk]})
+	}
+	return &c
+}
+
+func foreignKeyString(fk sqlschema.ForeignKey) string {
+	return fmt.Sprintf("%s(%s) %s(%s)", fk.From.TableName, fk.From.Column, fk.To.TableName, fk.To.Column)
+}
+
+// removeSQLFiles removes the SQL files of the migrations from the directory,
+// except for the files listed in keep.
+func removeSQLFiles(dir string, names, keep []string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[string]bool, len(names))
+	for _, name := range names {
+		remove[name] = true
+	}
+	for _, entry := range entries {
+		fname := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fname, ".sql") || slices.Contains(keep, fname) {
+			continue
+		}
+		name, _, err := extractMigrationName(fname)
+		if err != nil || !remove[name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, fname)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSquashed returns the names of the migrations listed in the --bun:squashed directive.
+func parseSquashed(contents []byte) []string {
+	line, _, _ := bytes.Cut(contents, []byte("\n"))
+	names, ok := bytes.CutPrefix(line, []byte(squashedDirective))
+	if !ok {
+		return nil
+	}
+	return strings.Fields(string(names))
+}
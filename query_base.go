@@ -158,16 +158,20 @@ func (q *baseQuery) GetTableName() string {
 }
 
 func (q *baseQuery) setConn(db IConn) {
-	// Unwrap Bun wrappers to not call query hooks twice.
+	q.conn = unwrapConn(db)
+}
+
+// unwrapConn unwraps Bun wrappers to not call query hooks twice.
+func unwrapConn(db IConn) IConn {
 	switch db := db.(type) {
 	case *DB:
-		q.conn = db.DB
+		return db.DB
 	case Conn:
-		q.conn = db.Conn
+		return db.Conn
 	case Tx:
-		q.conn = db.Tx
+		return db.Tx
 	default:
-		q.conn = db
+		return db
 	}
 }
 
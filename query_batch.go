@@ -0,0 +1,291 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/uptrace/bun/internal"
+)
+
+// BatchExecer is implemented by the driver connections which execute several queries
+// in one round-trip, e.g. pgdriver.Conn. ExecBatch returns the results of the queries
+// or the error of the failed query.
+type BatchExecer interface {
+	ExecBatch(ctx context.Context, queries []string) ([]driver.Result, error)
+}
+
+// Batch queues insert, update and delete queries and executes them together in a transaction.
+// If the driver connection implements BatchExecer, the queries are sent in one round-trip.
+// Otherwise, they are executed one by one.
+//
+//	res, err := db.NewBatch().
+//		Queue(db.NewInsert().Model(order)).
+//		Queue(db.NewUpdate().Model(product).Column("stock").WherePK()).
+//		Exec(ctx)
+//
+// RETURNING values are not scanned into the models.
+type Batch struct {
+	db      *DB
+	conn    IConn
+	queries []batchQuery
+}
+
+type batchQuery interface {
+	Query
+
+	// beforeBatch runs the model hooks and generates the query.
+	beforeBatch(ctx context.Context) (string, error)
+	afterBatch(ctx context.Context) error
+}
+
+func NewBatch(db *DB) *Batch {
+	return &Batch{db: db}
+}
+
+// Conn sets the connection which executes the queries instead of the connections of the queries.
+// Within a Tx, the queries are executed one by one, because database/sql does not give
+// access to the driver connection of a transaction.
+func (b *Batch) Conn(db IConn) *Batch {
+	b.conn = unwrapConn(db)
+	return b
+}
+
+// Queue adds the query, which must be an *InsertQuery, *UpdateQuery or *DeleteQuery.
+func (b *Batch) Queue(q Query) *Batch {
+	bq, ok := q.(batchQuery)
+	if !ok {
+		bq = unsupportedBatchQuery{Query: q}
+	}
+	b.queries = append(b.queries, bq)
+	return b
+}
+
+// Len returns the number of queued queries.
+func (b *Batch) Len() int {
+	return len(b.queries)
+}
+
+// Exec executes the queued queries and returns their results. If a query fails,
+// no changes are committed.
+func (b *Batch) Exec(ctx context.Context) ([]sql.Result, error) {
+	if len(b.queries) == 0 {
+		return nil, nil
+	}
+
+	queries := make([]string, len(b.queries))
+	for i, q := range b.queries {
+		query, err := q.beforeBatch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = query
+	}
+
+	ctxs := make([]context.Context, len(b.queries))
+	events := make([]*QueryEvent, len(b.queries))
+	for i, q := range b.queries {
+		ctxs[i], events[i] = b.db.beforeQuery(ctx, q, queries[i], nil, queries[i], q.GetModel())
+	}
+
+	res, err := b.exec(ctx, queries)
+
+	for i := range b.queries {
+		var r sql.Result
+		if i < len(res) {
+			r = res[i]
+		}
+		b.db.afterQuery(ctxs[i], events[i], r, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range b.queries {
+		if err := q.afterBatch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (b *Batch) exec(ctx context.Context, queries []string) ([]sql.Result, error) {
+	conn := b.conn
+	if conn == nil {
+		conn = b.db.DB
+		if b.db.resolver != nil {
+			if c := b.db.resolver.ResolveConn(ctx, b.queries[0]); c != nil {
+				conn = c
+			}
+		}
+	}
+
+	switch conn := conn.(type) {
+	case *sql.DB:
+		sqlConn, err := conn.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer sqlConn.Close()
+		return b.execConn(ctx, sqlConn, queries)
+	case *sql.Conn:
+		return b.execConn(ctx, conn, queries)
+	default:
+		return b.execEach(ctx, conn, queries)
+	}
+}
+
+// execConn executes the queries in one round-trip if the driver supports it
+// or one by one in a transaction.
+func (b *Batch) execConn(ctx context.Context, conn *sql.Conn, queries []string) ([]sql.Result, error) {
+	var res []sql.Result
+	var supported bool
+
+	if err := conn.Raw(func(driverConn any) error {
+		execer, ok := driverConn.(BatchExecer)
+		if !ok {
+			return nil
+		}
+		supported = true
+
+		driverRes, err := execer.ExecBatch(ctx, queries)
+		if err != nil {
+			return err
+		}
+		res = make([]sql.Result, len(driverRes))
+		for i, r := range driverRes {
+			res[i] = r
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if supported {
+		return res, nil
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err = b.execEach(ctx, tx, queries)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (b *Batch) execEach(ctx context.Context, conn IConn, queries []string) ([]sql.Result, error) {
+	res := make([]sql.Result, len(queries))
+	for i, query := range queries {
+		r, err := conn.ExecContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = r
+	}
+	return res, nil
+}
+
+type unsupportedBatchQuery struct {
+	Query
+}
+
+func (q unsupportedBatchQuery) beforeBatch(ctx context.Context) (string, error) {
+	return "", errors.New("bun: Batch supports only insert, update and delete queries")
+}
+
+func (q unsupportedBatchQuery) afterBatch(ctx context.Context) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *InsertQuery) beforeBatch(ctx context.Context) (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if q.table != nil {
+		if err := q.beforeInsertHook(ctx); err != nil {
+			return "", err
+		}
+	}
+	if err := q.beforeAppendModel(ctx, q); err != nil {
+		return "", err
+	}
+	setCommentFromContext(ctx, q)
+
+	queryBytes, err := q.AppendQuery(q.db.gen, q.db.makeQueryBytes())
+	if err != nil {
+		return "", err
+	}
+	return internal.String(queryBytes), nil
+}
+
+func (q *InsertQuery) afterBatch(ctx context.Context) error {
+	if q.table != nil {
+		return q.afterInsertHook(ctx)
+	}
+	return nil
+}
+
+func (q *UpdateQuery) beforeBatch(ctx context.Context) (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if q.table != nil {
+		if err := q.beforeUpdateHook(ctx); err != nil {
+			return "", err
+		}
+	}
+	if err := q.beforeAppendModel(ctx, q); err != nil {
+		return "", err
+	}
+	setCommentFromContext(ctx, q)
+
+	queryBytes, err := q.AppendQuery(q.db.gen, q.db.makeQueryBytes())
+	if err != nil {
+		return "", err
+	}
+	return internal.String(queryBytes), nil
+}
+
+func (q *UpdateQuery) afterBatch(ctx context.Context) error {
+	if q.table != nil {
+		return q.afterUpdateHook(ctx)
+	}
+	return nil
+}
+
+func (q *DeleteQuery) beforeBatch(ctx context.Context) (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if q.table != nil {
+		if err := q.beforeDeleteHook(ctx); err != nil {
+			return "", err
+		}
+	}
+	if err := q.beforeAppendModel(ctx, q); err != nil {
+		return "", err
+	}
+	setCommentFromContext(ctx, q)
+
+	queryBytes, err := q.AppendQuery(q.db.gen, q.db.makeQueryBytes())
+	if err != nil {
+		return "", err
+	}
+	return internal.String(queryBytes), nil
+}
+
+func (q *DeleteQuery) afterBatch(ctx context.Context) error {
+	if q.table != nil {
+		return q.afterDeleteHook(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// Cursor scans the rows selected by a query into the model one row at a time,
+// so the rows do not have to fit in memory, e.g. when exporting a large table.
+//
+//	cursor, err := db.NewSelect().Model((*User)(nil)).Relation("Profile").Cursor(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer cursor.Close()
+//
+//	for cursor.Next() {
+//		user := new(User)
+//		if err := cursor.Scan(user); err != nil {
+//			return err
+//		}
+//	}
+//	return cursor.Err()
+type Cursor struct {
+	ctx   context.Context
+	q     *SelectQuery
+	model *structTableModel
+	rows  *sql.Rows
+	dest  []any
+
+	done bool
+	err  error
+}
+
+// Cursor executes the query and returns the cursor over the selected rows.
+// The query must have a struct or slice model. Has-one and belongs-to relations are joined
+// and scanned with each row; has-many and many-to-many relations are not supported,
+// because they are selected with separate queries after all rows are scanned.
+func (q *SelectQuery) Cursor(ctx context.Context) (*Cursor, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	var model *structTableModel
+	switch m := q.tableModel.(type) {
+	case *structTableModel:
+		model = m
+	case *sliceTableModel:
+		model = &m.structTableModel
+	default:
+		return nil, fmt.Errorf("bun: Cursor requires a struct or slice model, got %T", q.model)
+	}
+	for _, j := range model.getJoins() {
+		switch j.Relation.Type {
+		case schema.HasManyRelation, schema.ManyToManyRelation:
+			return nil, fmt.Errorf("bun: Cursor does not support has-many and many-to-many relations, got %q",
+				j.Relation.Field.GoName)
+		}
+	}
+
+	if err := q.beforeSelectHook(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Rows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	model.columns = columns
+
+	return &Cursor{
+		ctx:   ctx,
+		q:     q,
+		model: model,
+		rows:  rows,
+		dest:  makeDest(model, len(columns)),
+	}, nil
+}
+
+// Next prepares the next row for Scan. It returns false when there are no more rows
+// or an error occurred, which is returned by Err. The query's AfterSelectHook
+// is called after the last row.
+func (c *Cursor) Next() bool {
+	if c.done {
+		return false
+	}
+	if c.rows.Next() {
+		return true
+	}
+
+	c.done = true
+	if c.err = c.rows.Err(); c.err == nil {
+		c.err = c.q.afterSelectHook(c.ctx)
+	}
+	return false
+}
+
+// Scan scans the current row into dest, which must be a pointer to the model's struct.
+// Joined has-one and belongs-to relations are scanned into the structs the dest fields
+// point to, allocating them when the fields are nil.
+func (c *Cursor) Scan(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Type().Elem() != c.model.table.Type {
+		return fmt.Errorf("bun: Cursor.Scan(%T) requires *%s", dest, c.model.table.TypeName)
+	}
+
+	c.model.strct = v.Elem()
+	c.model.structInited = false
+	return c.model.scanRow(c.ctx, c.rows, c.dest)
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close closes the cursor. It is safe to call Close more than once.
+func (c *Cursor) Close() error {
+	c.done = true
+	return c.rows.Close()
+}
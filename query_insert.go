@@ -19,10 +19,13 @@ type InsertQuery struct {
 
 	on schema.QueryWithArgs
 	setQuery
+	upsert *Upsert
 
 	ignore  bool
 	replace bool
 	comment string
+	chunked bool
+	chunk   int
 }
 
 var _ Query = (*InsertQuery)(nil)
@@ -170,6 +173,37 @@ func (q *InsertQuery) Replace() *InsertQuery {
 
 //------------------------------------------------------------------------------
 
+// Chunk enables inserting a slice model by chunks of at most size rows. When the slice
+// has more rows, they are inserted by several statements executed in one transaction,
+// or in the transaction the query is bound to, and BeforeInsert and AfterInsert hooks
+// are called for each chunk. The result aggregates RowsAffected of all statements and
+// the values returned for each chunk are appended to the slices passed to Scan or Exec.
+//
+// If size is 0, it is derived from the dialect's limit of values in a statement,
+// see schema.MaxValuesDialect. Slice models are inserted by a single statement
+// unless Chunk is called.
+func (q *InsertQuery) Chunk(size int) *InsertQuery {
+	q.chunked = true
+	q.chunk = size
+	return q
+}
+
+func (q *InsertQuery) chunkSize() int {
+	if !q.chunked {
+		return 0
+	}
+	if q.chunk > 0 {
+		return q.chunk
+	}
+	d, ok := q.db.dialect.(schema.MaxValuesDialect)
+	if !ok || q.table == nil || len(q.table.Fields) == 0 {
+		return 0
+	}
+	return max(d.MaxValues()/len(q.table.Fields), 1)
+}
+
+//------------------------------------------------------------------------------
+
 // Comment adds a comment to the query, wrapped by /* ... */.
 func (q *InsertQuery) Comment(comment string) *InsertQuery {
 	q.comment = comment
@@ -196,6 +230,10 @@ func (q *InsertQuery) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err
 		return nil, err
 	}
 
+	if q.upsertMerge(gen) {
+		return q.appendUpsertMerge(gen, b)
+	}
+
 	if q.replace {
 		b = append(b, "REPLACE "...)
 	} else {
@@ -206,7 +244,7 @@ func (q *InsertQuery) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err
 	}
 	b = append(b, "INTO "...)
 
-	if q.db.HasFeature(feature.InsertTableAlias) && !q.on.IsZero() {
+	if q.db.HasFeature(feature.InsertTableAlias) && (!q.on.IsZero() || q.upsert != nil) {
 		b, err = q.appendFirstTableWithAlias(gen, b)
 	} else {
 		b, err = q.appendFirstTable(gen, b)
@@ -351,7 +389,8 @@ func (q *InsertQuery) appendStructValues(
 		case isTemplate:
 			b = append(b, '?')
 		case q.marshalsToDefault(f, strct):
-			if q.db.HasFeature(feature.DefaultPlaceholder) {
+			// DEFAULT is not allowed in the source rows of MERGE.
+			if q.db.HasFeature(feature.DefaultPlaceholder) && !q.upsertMerge(gen) {
 				b = append(b, "DEFAULT"...)
 			} else if f.SQLDefault != "" {
 				b = append(b, f.SQLDefault...)
@@ -476,6 +515,9 @@ func (q *InsertQuery) SetValues(values *ValuesQuery) *InsertQuery {
 }
 
 func (q *InsertQuery) appendOn(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	if q.upsert != nil {
+		return q.appendUpsertOn(gen, b)
+	}
 	if q.on.IsZero() {
 		return b, nil
 	}
@@ -576,6 +618,12 @@ func (q *InsertQuery) scanOrExec(
 		return nil, q.err
 	}
 
+	if model, ok := q.tableModel.(*sliceTableModel); ok {
+		if size := q.chunkSize(); size > 0 && model.slice.Len() > size {
+			return q.execChunks(ctx, model, size, dest, hasDest)
+		}
+	}
+
 	if q.table != nil {
 		if err := q.beforeInsertHook(ctx); err != nil {
 			return nil, err
@@ -635,6 +683,112 @@ func (q *InsertQuery) scanOrExec(
 	return res, nil
 }
 
+// execChunks inserts the rows of the slice model by chunks in a transaction.
+func (q *InsertQuery) execChunks(
+	ctx context.Context, model *sliceTableModel, size int, dest []any, hasDest bool,
+) (sql.Result, error) {
+	conn := q.resolveConn(ctx, q)
+
+	beginner, ok := conn.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		// The query is already bound to a transaction.
+		return q.insertChunks(ctx, conn, model, size, dest, hasDest)
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := q.insertChunks(ctx, tx, model, size, dest, hasDest)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (q *InsertQuery) insertChunks(
+	ctx context.Context, conn IConn, model *sliceTableModel, size int, dest []any, hasDest bool,
+) (sql.Result, error) {
+	// Each chunk is scanned into new slices, which are appended to dest when all chunks are inserted.
+	acc := make([]reflect.Value, len(dest))
+	for i, d := range dest {
+		v := reflect.ValueOf(d)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+			return nil, fmt.Errorf("bun: chunked insert can only scan into slices, got %T", d)
+		}
+		acc[i] = reflect.MakeSlice(v.Elem().Type(), 0, model.slice.Len())
+	}
+
+	origConn, origModel, origTableModel := q.conn, q.model, q.tableModel
+	defer func() {
+		q.conn, q.model, q.tableModel = origConn, origModel, origTableModel
+	}()
+
+	q.conn = conn
+	slice := model.slice
+	sliceLen := slice.Len()
+
+	var res chunkedResult
+	for i := 0; i < sliceLen; i += size {
+		j := min(i+size, sliceLen)
+
+		// The chunk shares the backing array with the slice,
+		// so the returned columns are scanned into the slice elements.
+		chunk := reflect.New(slice.Type())
+		chunk.Elem().Set(slice.Slice3(i, j, j))
+		q.setModel(chunk.Interface())
+
+		chunkDest := make([]any, len(dest))
+		for k := range dest {
+			chunkDest[k] = reflect.New(acc[k].Type()).Interface()
+		}
+		r, err := q.scanOrExec(ctx, chunkDest, hasDest)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, r)
+		for k := range dest {
+			acc[k] = reflect.AppendSlice(acc[k], reflect.ValueOf(chunkDest[k]).Elem())
+		}
+	}
+
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(acc[i])
+	}
+	return res, nil
+}
+
+// chunkedResult is the result of a query executed by several statements.
+type chunkedResult []sql.Result
+
+var _ sql.Result = (chunkedResult)(nil)
+
+func (res chunkedResult) RowsAffected() (int64, error) {
+	var n int64
+	for _, r := range res {
+		affected, err := r.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		n += affected
+	}
+	return n, nil
+}
+
+// LastInsertId returns the ID of the last statement.
+func (res chunkedResult) LastInsertId() (int64, error) {
+	if len(res) == 0 {
+		return 0, nil
+	}
+	return res[len(res)-1].LastInsertId()
+}
+
 func (q *InsertQuery) beforeInsertHook(ctx context.Context) error {
 	if hook, ok := q.table.ZeroIface.(BeforeInsertHook); ok {
 		if err := hook.BeforeInsert(ctx, q); err != nil {
@@ -682,6 +836,10 @@ func (q *InsertQuery) tryLastInsertID(res sql.Result, dest []any) error {
 			return err
 		}
 	case *sliceTableModel:
+		// The upserted rows are not inserted with consecutive IDs.
+		if q.upsert != nil {
+			return nil
+		}
 		sliceLen := model.slice.Len()
 		for i := 0; i < sliceLen; i++ {
 			strct := indirect(model.slice.Index(i))
@@ -0,0 +1,310 @@
+package bun
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/schema"
+)
+
+// ErrInvalidCursor is returned by SelectQuery.ScanKeyset when the cursor cannot be decoded
+// or does not match the key columns.
+var ErrInvalidCursor = errors.New("bun: invalid keyset cursor")
+
+// KeysetColumn is a column of the key which orders the rows for keyset pagination.
+type KeysetColumn struct {
+	// Column is the name of the model's column.
+	Column string
+
+	// Desc orders the rows by the column in descending order.
+	Desc bool
+
+	// Nullable must be set for the columns which may contain NULL values.
+	// NULL values are ordered after all other values, regardless of Desc.
+	Nullable bool
+}
+
+// Keyset configures keyset (cursor) pagination, see SelectQuery.ScanKeyset.
+type Keyset struct {
+	// Columns hold the key which orders the rows. The key must be unique,
+	// e.g. it should end with the primary key.
+	Columns []KeysetColumn
+
+	// Cursor is KeysetPage.Next or KeysetPage.Prev returned with an adjacent page.
+	// An empty cursor selects the first page.
+	Cursor string
+
+	// Limit is the maximum number of rows on the page.
+	Limit int
+}
+
+// KeysetPage holds the cursors which select the pages adjacent to the scanned one.
+type KeysetPage struct {
+	// Next selects the rows after the page. It is empty if there are no such rows.
+	Next string
+
+	// Prev selects the rows before the page. It is empty if there are no such rows.
+	Prev string
+}
+
+// keysetCursor is the decoded cursor: the key of the row that the page starts after or ends before.
+type keysetCursor struct {
+	Before bool              `json:"b,omitempty"`
+	Key    []json.RawMessage `json:"k"`
+}
+
+// ScanKeyset selects a page of rows ordered by the key columns into the model,
+// which must be a slice of structs, and returns the cursors for the adjacent pages.
+//
+// Instead of skipping the rows on the previous pages with OFFSET, the query selects the rows
+// whose key is greater than the cursor, which allows the database to use an index on
+// the key columns and keeps pages stable when rows are inserted or deleted.
+// The cursors are opaque to the client, e.g. they can be passed in the URL.
+//
+// ScanKeyset adds WHERE, ORDER BY and LIMIT to the query, so the query must not have
+// its own ORDER BY or LIMIT. Conditions added with WhereOr should be grouped with WhereGroup.
+//
+// The cursor is compared with the key using row values, e.g. (a, b) > (1, 2), when the dialect
+// supports them and all key columns are ordered in the same direction and are not nullable.
+// Otherwise the comparison is expanded to a = 1 AND b > 2 OR a > 1.
+func (q *SelectQuery) ScanKeyset(ctx context.Context, keyset Keyset) (*KeysetPage, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	model, ok := q.tableModel.(*sliceTableModel)
+	if !ok {
+		return nil, fmt.Errorf("bun: ScanKeyset requires a slice model, got %T", q.model)
+	}
+	if len(keyset.Columns) == 0 {
+		return nil, errors.New("bun: ScanKeyset requires at least one key column")
+	}
+	if keyset.Limit <= 0 {
+		return nil, errors.New("bun: ScanKeyset requires a positive limit")
+	}
+	if len(q.order) > 0 || q.limit != 0 {
+		return nil, errors.New("bun: ScanKeyset query must not have ORDER BY or LIMIT")
+	}
+
+	fields := make([]*schema.Field, len(keyset.Columns))
+	for i, col := range keyset.Columns {
+		field, err := q.table.Field(col.Column)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = field
+	}
+
+	var cursor *keysetCursor
+	if keyset.Cursor != "" {
+		c, err := decodeKeysetCursor(keyset.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+
+		key, err := q.keysetValues(fields, c)
+		if err != nil {
+			return nil, err
+		}
+		where, args := q.keysetWhere(keyset.Columns, key, c.Before)
+		q.Where(where, args...)
+	}
+
+	// Rows before the cursor are selected in the reverse order, starting from the closest one.
+	backward := cursor != nil && cursor.Before
+	for _, col := range keyset.Columns {
+		desc := col.Desc != backward
+		if col.Nullable {
+			q.OrderExpr("CASE WHEN ?TableAlias.? IS NULL THEN 1 ELSE 0 END "+orderDir(backward),
+				Ident(col.Column))
+		}
+		q.OrderExpr("?TableAlias.? "+orderDir(desc), Ident(col.Column))
+	}
+	// Select an extra row to find out whether there are more rows.
+	q.Limit(keyset.Limit + 1)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	slice := model.slice
+	more := slice.Len() > keyset.Limit
+	if more {
+		slice.Set(slice.Slice(0, keyset.Limit))
+	}
+	if backward {
+		swap := reflect.Swapper(slice.Interface())
+		for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	page := new(KeysetPage)
+	if slice.Len() == 0 {
+		return page, nil
+	}
+
+	var err error
+	if more || backward {
+		if page.Next, err = encodeKeysetCursor(fields, slice.Index(slice.Len()-1), false); err != nil {
+			return nil, err
+		}
+	}
+	if (more && backward) || (cursor != nil && !backward) {
+		if page.Prev, err = encodeKeysetCursor(fields, slice.Index(0), true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// keysetValues decodes the key in the cursor into the SQL literals of the key columns.
+func (q *SelectQuery) keysetValues(fields []*schema.Field, c *keysetCursor) ([]string, error) {
+	if len(c.Key) != len(fields) {
+		return nil, ErrInvalidCursor
+	}
+
+	// The values are decoded into a struct and formatted by the fields. Unlike inserted values,
+	// zero values of nullzero fields are kept, because they are scanned from non-NULL columns.
+	strct := reflect.New(q.table.Type).Elem()
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		fv := field.Value(strct)
+		if err := json.Unmarshal(c.Key[i], fv.Addr().Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		if field.IsPtr && fv.IsNil() {
+			values[i] = string(dialect.AppendNull(nil))
+		} else {
+			values[i] = string(field.Append(q.db.gen, nil, fv))
+		}
+	}
+	return values, nil
+}
+
+// keysetWhere builds the condition which selects the rows after or before the key.
+func (q *SelectQuery) keysetWhere(columns []KeysetColumn, key []string, before bool) (string, []any) {
+	rowValue := q.db.HasFeature(feature.RowValueCompare)
+	for _, col := range columns {
+		if col.Nullable || col.Desc != columns[0].Desc {
+			rowValue = false
+		}
+	}
+	if rowValue {
+		names := make([]string, len(columns))
+		values := make([]string, len(columns))
+		args := make([]any, 0, 2*len(columns))
+		for i, col := range columns {
+			names[i] = "?TableAlias.?"
+			values[i] = "?"
+			args = append(args, Ident(col.Column))
+		}
+		for _, value := range key {
+			args = append(args, Safe(value))
+		}
+		return "(" + strings.Join(names, ", ") + ") " + keysetOp(columns[0].Desc, before) +
+			" (" + strings.Join(values, ", ") + ")", args
+	}
+
+	var terms []string
+	var args []any
+	for i, col := range columns {
+		cmp, cmpArgs := keysetCompare(col, key[i], before)
+		if cmp == "" {
+			continue
+		}
+		var term []string
+		for j := range i {
+			eq, eqArgs := keysetEqual(columns[j], key[j])
+			term = append(term, eq)
+			args = append(args, eqArgs...)
+		}
+		terms = append(terms, strings.Join(append(term, cmp), " AND "))
+		args = append(args, cmpArgs...)
+	}
+	if len(terms) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(terms, ") OR (") + ")", args
+}
+
+// keysetEqual returns the condition which selects the rows with the column equal to the value.
+func keysetEqual(col KeysetColumn, value string) (string, []any) {
+	if value == "NULL" {
+		return "?TableAlias.? IS NULL", []any{Ident(col.Column)}
+	}
+	return "?TableAlias.? = ?", []any{Ident(col.Column), Safe(value)}
+}
+
+// keysetCompare returns the condition which selects the rows ordered strictly after or before
+// the value by the column, or an empty string if there are no such rows.
+func keysetCompare(col KeysetColumn, value string, before bool) (string, []any) {
+	if value == "NULL" {
+		// NULL values are ordered last.
+		if before && col.Nullable {
+			return "?TableAlias.? IS NOT NULL", []any{Ident(col.Column)}
+		}
+		return "", nil
+	}
+
+	cmp := "?TableAlias.? " + keysetOp(col.Desc, before) + " ?"
+	if col.Nullable && !before {
+		return "(" + cmp + " OR ?TableAlias.? IS NULL)", []any{Ident(col.Column), Safe(value), Ident(col.Column)}
+	}
+	return cmp, []any{Ident(col.Column), Safe(value)}
+}
+
+func keysetOp(desc, before bool) string {
+	if desc != before {
+		return "<"
+	}
+	return ">"
+}
+
+func orderDir(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+func encodeKeysetCursor(fields []*schema.Field, elem reflect.Value, before bool) (string, error) {
+	strct := reflect.Indirect(elem)
+	c := keysetCursor{
+		Before: before,
+		Key:    make([]json.RawMessage, len(fields)),
+	}
+	for i, field := range fields {
+		b, err := json.Marshal(field.Value(strct).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Key[i] = b
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeKeysetCursor(s string) (*keysetCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	c := new(keysetCursor)
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return c, nil
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/uptrace/bun/dialect"
@@ -29,6 +30,7 @@ type SelectQuery struct {
 	joins      []joinQuery
 	group      []schema.QueryWithArgs
 	having     []schema.QueryWithArgs
+	windows    []namedWindow
 	selFor     schema.QueryWithArgs
 
 	union   []union
@@ -640,6 +642,11 @@ func (q *SelectQuery) appendQuery(
 		}
 	}
 
+	b, err = q.appendWindows(gen, b)
+	if err != nil {
+		return nil, err
+	}
+
 	if !count {
 		b, err = q.appendOrder(gen, b)
 		if err != nil {
@@ -1167,6 +1174,7 @@ func (q *SelectQuery) Clone() *SelectQuery {
 		joins:      make([]joinQuery, len(q.joins)),
 		group:      cloneArgs(q.group),
 		having:     cloneArgs(q.having),
+		windows:    slices.Clone(q.windows),
 		union:      make([]union, len(q.union)),
 		comment:    q.comment,
 	}
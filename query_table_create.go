@@ -212,6 +212,7 @@ func (q *CreateTableQuery) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte,
 		b = q.appendPKConstraint(b, q.table.PKs)
 	}
 	b = q.appendUniqueConstraints(gen, b)
+	b = q.appendCheckConstraints(gen, b)
 
 	if q.fksFromRel {
 		b, err = q.appendFKConstraintsRel(gen, b)
@@ -306,6 +307,17 @@ func (q *CreateTableQuery) appendUniqueConstraint(
 	return b
 }
 
+func (q *CreateTableQuery) appendCheckConstraints(gen schema.QueryGen, b []byte) []byte {
+	for _, check := range q.table.Checks {
+		b = append(b, ", CONSTRAINT "...)
+		b = gen.AppendIdent(b, check.Name)
+		b = append(b, " CHECK ("...)
+		b = append(b, check.Expr...)
+		b = append(b, ")"...)
+	}
+	return b
+}
+
 // appendFKConstraintsRel appends a FOREIGN KEY clause for each of the model's existing relations.
 func (q *CreateTableQuery) appendFKConstraintsRel(gen schema.QueryGen, b []byte) (_ []byte, err error) {
 	relations := q.tableModel.Table().Relations
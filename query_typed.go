@@ -0,0 +1,251 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+	"strings"
+)
+
+// Column is a typed reference to the column of the model T which holds values of type V.
+// Conditions and orders built from it can only be used in the queries selecting T,
+// so mistakes in column names and value types are caught by the compiler.
+//
+// Columns are usually generated from the models with GenerateColumns.
+type Column[T, V any] struct {
+	name string
+}
+
+// NewColumn creates a reference to the column with the SQL name.
+func NewColumn[T, V any](name string) Column[T, V] {
+	return Column[T, V]{name: name}
+}
+
+// Name returns the SQL name of the column.
+func (c Column[T, V]) Name() string {
+	return c.name
+}
+
+func (c Column[T, V]) Eq(value V) Cond[T] {
+	return c.cond("?TableAlias.? = ?", value)
+}
+
+func (c Column[T, V]) NotEq(value V) Cond[T] {
+	return c.cond("?TableAlias.? <> ?", value)
+}
+
+func (c Column[T, V]) Gt(value V) Cond[T] {
+	return c.cond("?TableAlias.? > ?", value)
+}
+
+func (c Column[T, V]) Gte(value V) Cond[T] {
+	return c.cond("?TableAlias.? >= ?", value)
+}
+
+func (c Column[T, V]) Lt(value V) Cond[T] {
+	return c.cond("?TableAlias.? < ?", value)
+}
+
+func (c Column[T, V]) Lte(value V) Cond[T] {
+	return c.cond("?TableAlias.? <= ?", value)
+}
+
+// In returns the condition which holds when the column equals any of the values.
+// Without values the condition never holds.
+func (c Column[T, V]) In(values ...V) Cond[T] {
+	if len(values) == 0 {
+		return Cond[T]{query: "1 = 0"}
+	}
+	return c.cond("?TableAlias.? IN (?)", In(values))
+}
+
+func (c Column[T, V]) IsNull() Cond[T] {
+	return Cond[T]{query: "?TableAlias.? IS NULL", args: []any{Ident(c.name)}}
+}
+
+func (c Column[T, V]) IsNotNull() Cond[T] {
+	return Cond[T]{query: "?TableAlias.? IS NOT NULL", args: []any{Ident(c.name)}}
+}
+
+func (c Column[T, V]) Asc() Order[T] {
+	return Order[T]{query: "?TableAlias.? ASC", args: []any{Ident(c.name)}}
+}
+
+func (c Column[T, V]) Desc() Order[T] {
+	return Order[T]{query: "?TableAlias.? DESC", args: []any{Ident(c.name)}}
+}
+
+func (c Column[T, V]) cond(query string, value any) Cond[T] {
+	return Cond[T]{query: query, args: []any{Ident(c.name), value}}
+}
+
+// Cond is a condition on the columns of the model T, see Column.
+type Cond[T any] struct {
+	query string
+	args  []any
+}
+
+// And returns the condition which holds when c and all other conditions hold.
+func (c Cond[T]) And(other ...Cond[T]) Cond[T] {
+	return joinConds(" AND ", append([]Cond[T]{c}, other...))
+}
+
+// Or returns the condition which holds when c or any of other conditions holds.
+func (c Cond[T]) Or(other ...Cond[T]) Cond[T] {
+	return joinConds(" OR ", append([]Cond[T]{c}, other...))
+}
+
+// Query returns the condition with its arguments, e.g. for SelectQuery.Where.
+func (c Cond[T]) Query() (string, []any) {
+	return c.query, c.args
+}
+
+func joinConds[T any](sep string, conds []Cond[T]) Cond[T] {
+	queries := make([]string, len(conds))
+	var args []any
+	for i, cond := range conds {
+		queries[i] = "(" + cond.query + ")"
+		args = append(args, cond.args...)
+	}
+	return Cond[T]{query: strings.Join(queries, sep), args: args}
+}
+
+// Order is an ORDER BY expression on the columns of the model T, see Column.
+type Order[T any] struct {
+	query string
+	args  []any
+}
+
+//------------------------------------------------------------------------------
+
+// TypedSelectQuery selects the rows of the model T and scans them into []T or *T.
+// Use Query or Apply for the clauses it does not provide, e.g. joins.
+type TypedSelectQuery[T any] struct {
+	q    *SelectQuery
+	rows []T
+}
+
+// Select creates a query which selects the rows of the model T.
+//
+//	users, err := bun.Select[User](db).
+//		Where(UserColumns.Email.Eq(email)).
+//		Order(UserColumns.CreatedAt.Desc()).
+//		All(ctx)
+func Select[T any](db IDB) *TypedSelectQuery[T] {
+	q := new(TypedSelectQuery[T])
+	// The model is a slice from the start, so that relations are joined the same way
+	// regardless of whether All or One is called.
+	q.q = db.NewSelect().Model(&q.rows)
+	return q
+}
+
+// Query returns the underlying query.
+func (q *TypedSelectQuery[T]) Query() *SelectQuery {
+	return q.q
+}
+
+// Apply calls each function in fns, passing the underlying query as an argument.
+func (q *TypedSelectQuery[T]) Apply(fns ...func(*SelectQuery) *SelectQuery) *TypedSelectQuery[T] {
+	q.q = q.q.Apply(fns...)
+	return q
+}
+
+// Where adds the conditions joined with AND.
+func (q *TypedSelectQuery[T]) Where(conds ...Cond[T]) *TypedSelectQuery[T] {
+	for _, cond := range conds {
+		q.q.Where(cond.query, cond.args...)
+	}
+	return q
+}
+
+// WhereOr adds the conditions joined with OR.
+func (q *TypedSelectQuery[T]) WhereOr(conds ...Cond[T]) *TypedSelectQuery[T] {
+	for _, cond := range conds {
+		q.q.WhereOr(cond.query, cond.args...)
+	}
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Order(orders ...Order[T]) *TypedSelectQuery[T] {
+	for _, order := range orders {
+		q.q.OrderExpr(order.query, order.args...)
+	}
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Limit(n int) *TypedSelectQuery[T] {
+	q.q.Limit(n)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Offset(n int) *TypedSelectQuery[T] {
+	q.q.Offset(n)
+	return q
+}
+
+// All returns all selected rows.
+func (q *TypedSelectQuery[T]) All(ctx context.Context) ([]T, error) {
+	if err := q.q.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return q.rows, nil
+}
+
+// One returns the first selected row or sql.ErrNoRows.
+func (q *TypedSelectQuery[T]) One(ctx context.Context) (*T, error) {
+	if err := q.q.Limit(1).Scan(ctx); err != nil {
+		return nil, err
+	}
+	if len(q.rows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &q.rows[0], nil
+}
+
+// Iter returns an iterator over the selected rows, which are scanned one at a time
+// instead of being loaded into memory, see Cursor. It stops after the first error.
+//
+//	for user, err := range bun.Select[User](db).Iter(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (q *TypedSelectQuery[T]) Iter(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		cursor, err := q.q.Cursor(ctx)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer cursor.Close()
+
+		for cursor.Next() {
+			var row T
+			if err := cursor.Scan(&row); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+func (q *TypedSelectQuery[T]) Count(ctx context.Context) (int, error) {
+	return q.q.Count(ctx)
+}
+
+func (q *TypedSelectQuery[T]) Exists(ctx context.Context) (bool, error) {
+	return q.q.Exists(ctx)
+}
+
+func (q *TypedSelectQuery[T]) String() string {
+	return q.q.String()
+}
@@ -0,0 +1,301 @@
+package bun
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/schema"
+)
+
+// Upsert describes what InsertQuery.Upsert does with the rows which conflict with existing rows.
+// Depending on the dialect, it generates:
+//
+//   - ON CONFLICT (...) DO UPDATE SET col = EXCLUDED.col on PostgreSQL and SQLite;
+//
+//   - ON DUPLICATE KEY UPDATE col = VALUES(col) on MySQL, which ignores the conflict columns
+//     and checks all unique keys instead;
+//
+//   - MERGE ... WHEN MATCHED THEN UPDATE ... WHEN NOT MATCHED THEN INSERT on MSSQL.
+//
+//     db.NewInsert().Model(&users).
+//     Upsert(bun.NewUpsert("email").Set("name", "updated_at")).
+//     Exec(ctx)
+type Upsert struct {
+	conflict  []string
+	columns   []string
+	where     []schema.QueryWithSep
+	doNothing bool
+}
+
+// NewUpsert creates an upsert of the rows which conflict on the columns, which must have
+// a unique index. The primary key is used if no columns are given.
+func NewUpsert(conflict ...string) *Upsert {
+	return &Upsert{conflict: conflict}
+}
+
+// Set sets the columns which are updated in the conflicting rows. By default, all inserted
+// columns are updated, except for the primary key and the conflict columns.
+func (u *Upsert) Set(columns ...string) *Upsert {
+	u.columns = append(u.columns, columns...)
+	return u
+}
+
+// Where adds the condition for updating the conflicting row. ?TableAlias refers to the existing
+// row and Excluded to the row proposed for insertion, e.g.
+//
+//	Where("?TableAlias.updated_at < ?", bun.Excluded("updated_at"))
+//
+// It is not supported on MySQL.
+func (u *Upsert) Where(query string, args ...any) *Upsert {
+	u.where = append(u.where, schema.SafeQueryWithSep(query, args, " AND "))
+	return u
+}
+
+// DoNothing keeps the conflicting rows unchanged. Only the inserted rows are returned,
+// so RETURNING should not be scanned into the slice model.
+func (u *Upsert) DoNothing() *Upsert {
+	u.doNothing = true
+	return u
+}
+
+// Excluded references the column of the row proposed for insertion in Upsert.Where.
+func Excluded(column string) schema.QueryAppender {
+	return excludedColumn(column)
+}
+
+type excludedColumn string
+
+func (c excludedColumn) AppendQuery(gen schema.QueryGen, b []byte) ([]byte, error) {
+	if gen.HasFeature(feature.InsertOnConflict) {
+		b = append(b, "EXCLUDED."...)
+	} else {
+		b = gen.AppendIdent(b, upsertSourceAlias)
+		b = append(b, '.')
+	}
+	return gen.AppendIdent(b, string(c)), nil
+}
+
+// upsertSourceAlias is the alias of the inserted rows in MERGE.
+const upsertSourceAlias = "_data"
+
+//------------------------------------------------------------------------------
+
+// Upsert inserts the rows and updates the rows which conflict with existing rows, see Upsert.
+// On PostgreSQL, SQLite and MSSQL, the inserted and updated rows are scanned back into
+// the model. It replaces On and Set.
+func (q *InsertQuery) Upsert(upsert *Upsert) *InsertQuery {
+	q.upsert = upsert
+	return q
+}
+
+// upsertMerge reports whether the upsert is generated as MERGE.
+func (q *InsertQuery) upsertMerge(gen schema.QueryGen) bool {
+	return q.upsert != nil &&
+		!gen.HasFeature(feature.InsertOnConflict) &&
+		!gen.HasFeature(feature.InsertOnDuplicateKey)
+}
+
+// upsertFields returns the conflict columns and the updated columns.
+func (q *InsertQuery) upsertFields(fields []*schema.Field) (conflict, update []*schema.Field, _ error) {
+	if q.table == nil {
+		return nil, nil, errNilModel
+	}
+
+	if len(q.upsert.conflict) > 0 {
+		for _, col := range q.upsert.conflict {
+			field, err := q.table.Field(col)
+			if err != nil {
+				return nil, nil, err
+			}
+			conflict = append(conflict, field)
+		}
+	} else {
+		conflict = q.table.PKs
+	}
+
+	if q.upsert.doNothing {
+		return conflict, nil, nil
+	}
+
+	if len(q.upsert.columns) > 0 {
+		for _, col := range q.upsert.columns {
+			field, err := q.table.Field(col)
+			if err != nil {
+				return nil, nil, err
+			}
+			update = append(update, field)
+		}
+		return conflict, update, nil
+	}
+
+	for _, f := range fields {
+		if !f.IsPK && !slices.Contains(conflict, f) {
+			update = append(update, f)
+		}
+	}
+	return conflict, update, nil
+}
+
+func (q *InsertQuery) appendUpsertOn(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	fields, err := q.getFields()
+	if err != nil {
+		return nil, err
+	}
+	conflict, update, err := q.upsertFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if gen.HasFeature(feature.InsertOnDuplicateKey) {
+		if len(q.upsert.where) > 0 {
+			return nil, errors.New("bun: Upsert.Where is not supported with ON DUPLICATE KEY UPDATE")
+		}
+		if len(update) == 0 {
+			// Assigning a column to itself keeps the row unchanged, unlike INSERT IGNORE,
+			// which also ignores other errors.
+			if len(conflict) == 0 {
+				return nil, errors.New("bun: Upsert.DoNothing requires a conflict column or a primary key")
+			}
+			b = append(b, " ON DUPLICATE KEY UPDATE "...)
+			b = append(b, conflict[0].SQLName...)
+			b = append(b, " = "...)
+			b = append(b, conflict[0].SQLName...)
+			return b, nil
+		}
+		b = append(b, " ON DUPLICATE KEY UPDATE"...)
+		return q.appendSetValues(b, update), nil
+	}
+
+	b = append(b, " ON CONFLICT "...)
+	if len(conflict) > 0 {
+		b = append(b, '(')
+		b = appendColumns(b, "", conflict)
+		b = append(b, ") "...)
+	}
+	if len(update) == 0 {
+		b = append(b, "DO NOTHING"...)
+		return b, nil
+	}
+
+	b = append(b, "DO UPDATE"...)
+	b = q.appendSetExcluded(b, update)
+
+	if len(q.upsert.where) > 0 {
+		b = append(b, " WHERE "...)
+		b, err = appendWhere(gen, b, q.upsert.where)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendUpsertMerge generates MERGE which inserts the rows from VALUES aliased as _data.
+func (q *InsertQuery) appendUpsertMerge(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	if !gen.HasFeature(feature.Merge) {
+		return nil, feature.NewNotSupportError(feature.Merge)
+	}
+	if len(q.extraValues) > 0 {
+		return nil, errors.New("bun: Upsert with MERGE does not support Value")
+	}
+
+	fields, err := q.getFields()
+	if err != nil {
+		return nil, err
+	}
+	conflict, update, err := q.upsertFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflict) == 0 {
+		return nil, errors.New("bun: Upsert requires a conflict column or a primary key")
+	}
+	for _, f := range conflict {
+		if !slices.Contains(fields, f) {
+			return nil, fmt.Errorf("bun: Upsert conflict column %q is not inserted", f.Name)
+		}
+	}
+
+	src := schema.Safe(gen.AppendIdent(nil, upsertSourceAlias))
+
+	b = append(b, "MERGE "...)
+	b, err = q.appendFirstTableWithAlias(gen, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, " USING (VALUES ("...)
+	switch model := q.tableModel.(type) {
+	case *structTableModel:
+		b, err = q.appendStructValues(gen, b, fields, model.strct)
+	case *sliceTableModel:
+		b, err = q.appendSliceValues(gen, b, fields, model.slice)
+	default:
+		err = fmt.Errorf("bun: Insert does not support %T", q.tableModel)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ")) AS "...)
+	b = append(b, src...)
+	b = append(b, " ("...)
+	b = appendColumns(b, "", fields)
+	b = append(b, ")"...)
+
+	b = append(b, " ON "...)
+	for i, f := range conflict {
+		if i > 0 {
+			b = append(b, " AND "...)
+		}
+		b = append(b, q.table.SQLAlias...)
+		b = append(b, '.')
+		b = append(b, f.SQLName...)
+		b = append(b, " = "...)
+		b = append(b, src...)
+		b = append(b, '.')
+		b = append(b, f.SQLName...)
+	}
+
+	if len(update) > 0 {
+		b = append(b, " WHEN MATCHED"...)
+		if len(q.upsert.where) > 0 {
+			b = append(b, " AND "...)
+			b, err = appendWhere(gen, b, q.upsert.where)
+			if err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, " THEN UPDATE SET "...)
+		for i, f := range update {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = append(b, f.SQLName...)
+			b = append(b, " = "...)
+			b = append(b, src...)
+			b = append(b, '.')
+			b = append(b, f.SQLName...)
+		}
+	}
+
+	b = append(b, " WHEN NOT MATCHED THEN INSERT ("...)
+	b = appendColumns(b, "", fields)
+	b = append(b, ") VALUES ("...)
+	b = appendColumns(b, src, fields)
+	b = append(b, ')')
+
+	if q.hasFeature(feature.Output) && q.hasReturning() {
+		b = append(b, " OUTPUT "...)
+		b, err = q.appendOutput(gen, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A MERGE statement must be terminated by a semi-colon (;).
+	b = append(b, ';')
+
+	return b, nil
+}
@@ -0,0 +1,243 @@
+package bun
+
+import (
+	"errors"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/schema"
+)
+
+// FrameBound is the start or the end of a window frame, see Window.Rows.
+type FrameBound struct {
+	query string
+	args  []any
+
+	// offset is true for the bounds which are an offset from the current row.
+	offset bool
+}
+
+var (
+	UnboundedPreceding = FrameBound{query: "UNBOUNDED PRECEDING"}
+	CurrentRow         = FrameBound{query: "CURRENT ROW"}
+	UnboundedFollowing = FrameBound{query: "UNBOUNDED FOLLOWING"}
+)
+
+// Preceding is the frame bound offset rows (or values for RANGE frames) before the current row,
+// e.g. Preceding(3) or Preceding(bun.Safe("INTERVAL '1 day'")).
+func Preceding(offset any) FrameBound {
+	return FrameBound{query: "? PRECEDING", args: []any{offset}, offset: true}
+}
+
+// Following is the frame bound offset rows (or values for RANGE frames) after the current row.
+func Following(offset any) FrameBound {
+	return FrameBound{query: "? FOLLOWING", args: []any{offset}, offset: true}
+}
+
+// FrameExclusion excludes rows around the current row from the window frame, see Window.Exclude.
+type FrameExclusion string
+
+const (
+	ExcludeCurrentRow FrameExclusion = "CURRENT ROW"
+	ExcludeGroup      FrameExclusion = "GROUP"
+	ExcludeTies       FrameExclusion = "TIES"
+	ExcludeNoOthers   FrameExclusion = "NO OTHERS"
+)
+
+// Window is the definition of a window for window functions, which is rendered
+// in parentheses, e.g. (PARTITION BY "user_id" ORDER BY "created_at" DESC).
+// It can be used with OVER in column expressions or named with SelectQuery.Window:
+//
+//	w := bun.NewWindow().PartitionBy("user_id").Order("created_at DESC")
+//	q.ColumnExpr("row_number() OVER ? AS rank", w)
+//
+//	q.Window("w", w).ColumnExpr("row_number() OVER ? AS rank", bun.Ident("w"))
+//
+// Frames which the dialect does not support, e.g. GROUPS in MySQL, make the query fail.
+type Window struct {
+	base      string
+	partition []schema.QueryWithArgs
+	order     orderLimitOffsetQuery
+
+	frame     string
+	start     FrameBound
+	end       FrameBound
+	exclusion FrameExclusion
+}
+
+var _ schema.QueryAppender = (*Window)(nil)
+
+func NewWindow() *Window {
+	return new(Window)
+}
+
+// Base makes the window extend the window with the name defined by SelectQuery.Window.
+func (w *Window) Base(name string) *Window {
+	w.base = name
+	return w
+}
+
+func (w *Window) PartitionBy(columns ...string) *Window {
+	for _, column := range columns {
+		w.partition = append(w.partition, schema.UnsafeIdent(column))
+	}
+	return w
+}
+
+func (w *Window) PartitionByExpr(query string, args ...any) *Window {
+	w.partition = append(w.partition, schema.SafeQuery(query, args))
+	return w
+}
+
+// Order adds ORDER BY columns in the same format as SelectQuery.Order, e.g. "created_at DESC".
+func (w *Window) Order(orders ...string) *Window {
+	w.order.addOrder(orders...)
+	return w
+}
+
+func (w *Window) OrderExpr(query string, args ...any) *Window {
+	w.order.addOrderExpr(query, args...)
+	return w
+}
+
+// Rows sets the frame to the rows between start and end.
+func (w *Window) Rows(start, end FrameBound) *Window {
+	return w.setFrame("ROWS", start, end)
+}
+
+// Range sets the frame to the rows whose values in the ORDER BY column are between start and end.
+// Offset bounds, e.g. Preceding(10), require feature.WindowRangeOffset.
+func (w *Window) Range(start, end FrameBound) *Window {
+	return w.setFrame("RANGE", start, end)
+}
+
+// Groups sets the frame to the groups of peer rows between start and end.
+// It requires feature.WindowGroups.
+func (w *Window) Groups(start, end FrameBound) *Window {
+	return w.setFrame("GROUPS", start, end)
+}
+
+func (w *Window) setFrame(frame string, start, end FrameBound) *Window {
+	w.frame = frame
+	w.start = start
+	w.end = end
+	return w
+}
+
+// Exclude excludes rows from the frame. It requires feature.WindowExclude.
+func (w *Window) Exclude(exclusion FrameExclusion) *Window {
+	w.exclusion = exclusion
+	return w
+}
+
+func (w *Window) AppendQuery(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	features := gen.Dialect().Features()
+
+	b = append(b, '(')
+	sep := func() {
+		if b[len(b)-1] != '(' {
+			b = append(b, ' ')
+		}
+	}
+
+	if w.base != "" {
+		if !features.Has(feature.NamedWindow) {
+			return nil, feature.NewNotSupportError(feature.NamedWindow)
+		}
+		b = gen.AppendName(b, w.base)
+	}
+
+	if len(w.partition) > 0 {
+		sep()
+		b = append(b, "PARTITION BY "...)
+		for i, f := range w.partition {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			if b, err = f.AppendQuery(gen, b); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(w.order.order) > 0 {
+		sep()
+		b = append(b, "ORDER BY "...)
+		for i, f := range w.order.order {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			if b, err = f.AppendQuery(gen, b); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if w.frame != "" {
+		switch {
+		case w.frame == "GROUPS" && !features.Has(feature.WindowGroups):
+			return nil, feature.NewNotSupportError(feature.WindowGroups)
+		case w.frame == "RANGE" && (w.start.offset || w.end.offset) &&
+			!features.Has(feature.WindowRangeOffset):
+			return nil, feature.NewNotSupportError(feature.WindowRangeOffset)
+		}
+
+		sep()
+		b = append(b, w.frame...)
+		b = append(b, " BETWEEN "...)
+		b = gen.AppendQuery(b, w.start.query, w.start.args...)
+		b = append(b, " AND "...)
+		b = gen.AppendQuery(b, w.end.query, w.end.args...)
+	}
+
+	if w.exclusion != "" {
+		if w.frame == "" {
+			return nil, errors.New("bun: window exclusion requires a frame, see Window.Rows")
+		}
+		if !features.Has(feature.WindowExclude) {
+			return nil, feature.NewNotSupportError(feature.WindowExclude)
+		}
+		sep()
+		b = append(b, "EXCLUDE "...)
+		b = append(b, w.exclusion...)
+	}
+
+	b = append(b, ')')
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+type namedWindow struct {
+	name   string
+	window *Window
+}
+
+// Window adds the named window definition to the WINDOW clause. Window functions refer to it
+// by name, e.g. ColumnExpr("rank() OVER ?", bun.Ident("w")), and windows extend it with Window.Base.
+// It requires feature.NamedWindow.
+func (q *SelectQuery) Window(name string, window *Window) *SelectQuery {
+	q.windows = append(q.windows, namedWindow{name: name, window: window})
+	return q
+}
+
+func (q *SelectQuery) appendWindows(gen schema.QueryGen, b []byte) (_ []byte, err error) {
+	if len(q.windows) == 0 {
+		return b, nil
+	}
+	if !gen.Dialect().Features().Has(feature.NamedWindow) {
+		return nil, feature.NewNotSupportError(feature.NamedWindow)
+	}
+
+	b = append(b, " WINDOW "...)
+	for i, w := range q.windows {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = gen.AppendName(b, w.name)
+		b = append(b, " AS "...)
+		if b, err = w.window.AppendQuery(gen, b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,12 @@
+package schema
+
+// Check describes a CHECK constraint declared with the "check" field tag,
+// e.g. `bun:",check:(price > 0)"`. The expression may reference other columns of the table.
+//
+// Constraints are named after the table and the column, as Postgres names column constraints:
+// the first check on the column "price" of the table "books" is "books_price_check",
+// the following ones are "books_price_check1", "books_price_check2", etc.
+type Check struct {
+	Name string
+	Expr string
+}
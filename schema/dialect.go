@@ -44,6 +44,14 @@ type Dialect interface {
 	DefaultSchema() string
 }
 
+// MaxValuesDialect is implemented by the dialects which limit the number of values
+// in a single statement, e.g. the number of bind parameters. Inserts and IN lists
+// can be split into chunks which fit the limit, see InsertQuery.Chunk and bun.InChunks.
+type MaxValuesDialect interface {
+	// MaxValues returns the maximum number of values in a statement.
+	MaxValues() int
+}
+
 // ------------------------------------------------------------------------------
 
 type BaseDialect struct{}
@@ -26,6 +26,8 @@ type Field struct {
 	UserSQLType        string
 	CreateTableSQLType string
 	SQLDefault         string
+	Comment            string
+	EnumValues         []string // values of the Postgres enum type set with the "type" tag
 
 	OnDelete string
 	OnUpdate string
@@ -0,0 +1,41 @@
+package schema
+
+import "reflect"
+
+// Index describes an index declared on the model.
+//
+// Simple indexes can be declared with the "index" field tag: `bun:",index"` creates
+// a separate index for the column and `bun:",index:name"` groups all columns tagged
+// with the same name into one composite index. Models that need an index with
+// an expression, a custom access method, a partial WHERE condition or INCLUDE columns
+// should implement ModelIndexer instead.
+type Index struct {
+	// Name of the index. Dialects generate a name if it is empty.
+	Name string
+
+	// Unique creates a UNIQUE index.
+	Unique bool
+
+	// Columns lists the indexed columns in the order they appear in the index.
+	Columns []string
+
+	// Expr is an SQL expression, e.g. lower(email), which is indexed instead of Columns.
+	Expr string
+
+	// Using sets the index access method, e.g. gin. The database default is used if it's empty.
+	Using string
+
+	// Include lists non-key columns stored in a covering index.
+	Include []string
+
+	// Where is the predicate of a partial index.
+	Where string
+}
+
+// ModelIndexer is implemented by models which declare indexes
+// that cannot be described with the "index" field tag.
+type ModelIndexer interface {
+	ModelIndexes() []Index
+}
+
+var modelIndexerType = reflect.TypeFor[ModelIndexer]()
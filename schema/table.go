@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -65,6 +66,8 @@ type Table struct {
 	IsM2MTable bool // If true, this table is the "junction table" of an m2m relation.
 	Relations  map[string]*Relation
 	Unique     map[string][]*Field
+	Indexes    []*Index
+	Checks     []*Check
 
 	SoftDeleteField       *Field
 	UpdateSoftDeleteField func(fv reflect.Value, tm time.Time) error
@@ -110,6 +113,32 @@ func (table *Table) init(dialect Dialect, typ reflect.Type) {
 			table.flags = table.flags.Set(hook.flag)
 		}
 	}
+
+	if typ.Implements(modelIndexerType) {
+		for _, index := range table.ZeroIface.(ModelIndexer).ModelIndexes() {
+			table.Indexes = append(table.Indexes, &index)
+		}
+	}
+
+	table.initChecks()
+}
+
+// initChecks collects CHECK constraints from the "check" tags once the table name is known.
+func (t *Table) initChecks() {
+	tableName := t.Name
+	if i := strings.LastIndexByte(tableName, '.'); i >= 0 {
+		tableName = tableName[i+1:]
+	}
+
+	for _, field := range t.Fields {
+		for i, expr := range field.Tag.Options["check"] {
+			name := tableName + "_" + field.Name + "_check"
+			if i > 0 {
+				name += strconv.Itoa(i)
+			}
+			t.Checks = append(t.Checks, &Check{Name: name, Expr: expr})
+		}
+	}
 }
 
 func (t *Table) processFields(typ reflect.Type) {
@@ -275,6 +304,9 @@ func (t *Table) processFields(typ reflect.Type) {
 		if v, ok := subfield.Tag.Options["unique"]; ok {
 			t.addUnique(subfield, embfield.prefix, v)
 		}
+		if v, ok := subfield.Tag.Options["index"]; ok {
+			t.addIndex(subfield, embfield.prefix, v)
+		}
 	}
 
 	if len(ebdStructs) > 0 && t.StructMap == nil {
@@ -332,6 +364,36 @@ func (t *Table) addUnique(field *Field, prefix string, tagOptions []string) {
 	}
 }
 
+// addIndex adds the field to the indexes listed in the "index" tag.
+// Fields which share the index name make up a composite index,
+// while an unnamed index is always created for a single column.
+func (t *Table) addIndex(field *Field, prefix string, tagOptions []string) {
+	var names []string
+	if len(tagOptions) == 1 {
+		names = strings.Split(tagOptions[0], ",")
+	} else {
+		names = tagOptions
+	}
+
+NextName:
+	for _, name := range names {
+		if name == "" {
+			t.Indexes = append(t.Indexes, &Index{Columns: []string{field.Name}})
+			continue
+		}
+		if prefix != "" {
+			name = prefix + name
+		}
+		for _, index := range t.Indexes {
+			if index.Name == name {
+				index.Columns = append(index.Columns, field.Name)
+				continue NextName
+			}
+		}
+		t.Indexes = append(t.Indexes, &Index{Name: name, Columns: []string{field.Name}})
+	}
+}
+
 func (t *Table) setName(name string) {
 	t.Name = name
 	t.SQLName = t.quoteIdent(name)
@@ -547,12 +609,24 @@ func (t *Table) newField(sf reflect.StructField, tag tagparser.Tag) *Field {
 	if v, ok := tag.Options["unique"]; ok {
 		t.addUnique(field, "", v)
 	}
+	if v, ok := tag.Options["index"]; ok {
+		t.addIndex(field, "", v)
+	}
 	if s, ok := tag.Option("default"); ok {
 		field.SQLDefault = s
 	}
+	if s, ok := tag.Option("comment"); ok {
+		field.Comment = s
+	}
 	if s, ok := field.Tag.Option("type"); ok {
 		field.UserSQLType = s
 	}
+	if s, ok := tag.Option("enum"); ok {
+		if field.UserSQLType == "" {
+			panic(fmt.Errorf("bun: %s.%s: enum option requires the type option with the enum type name", t.TypeName, sf.Name))
+		}
+		field.EnumValues = parseEnumValues(s)
+	}
 	field.DiscoveredSQLType = DiscoverSQLType(field.IndirectType)
 	field.Append = FieldAppender(t.dialect, field)
 	field.Scan = FieldScanner(t.dialect, field)
@@ -1006,6 +1080,10 @@ func isKnownFieldOption(name string) bool {
 		"nullzero",
 		"default",
 		"unique",
+		"index",
+		"check",
+		"comment",
+		"enum",
 		"soft_delete",
 		"scanonly",
 		"skipupdate",
@@ -1025,6 +1103,18 @@ func isKnownFieldOption(name string) bool {
 	return false
 }
 
+// parseEnumValues parses the value of the "enum" tag, e.g. (draft, 'published', archived).
+func parseEnumValues(s string) []string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+
+	values := strings.Split(s, ",")
+	for i, v := range values {
+		values[i] = strings.Trim(strings.TrimSpace(v), "'")
+	}
+	return values
+}
+
 func isKnownFKRule(name string) bool {
 	switch name {
 	case "CASCADE",
@@ -170,6 +170,46 @@ func TestTable(t *testing.T) {
 		require.Equal(t, "foo_unique_group_id", table.Unique["foo_groupa"][0].Name)
 	})
 
+	t.Run("indexes", func(t *testing.T) {
+		type Audit struct {
+			CreatedBy string `bun:",index:audit"`
+			CreatedAt string `bun:",index:audit"`
+		}
+
+		type Post struct {
+			ID       int64  `bun:",pk"`
+			AuthorID int64  `bun:",index"`
+			Slug     string `bun:",index:slug_idx,index:tenant_slug_idx"`
+			TenantID int64  `bun:",index:tenant_slug_idx"`
+			Draft    Audit  `bun:"embed:draft_"`
+		}
+
+		table := tables.Get(reflect.TypeFor[*Post]())
+		require.Equal(t, []*Index{
+			{Columns: []string{"author_id"}},
+			{Name: "slug_idx", Columns: []string{"slug"}},
+			{Name: "tenant_slug_idx", Columns: []string{"slug", "tenant_id"}},
+			{Name: "draft_audit", Columns: []string{"draft_created_by", "draft_created_at"}},
+		}, table.Indexes)
+	})
+
+	t.Run("checks, comments and enums", func(t *testing.T) {
+		type Book struct {
+			BaseModel `bun:"table:store.books"`
+			ID        int64   `bun:",pk"`
+			Price     float64 `bun:",check:(price > 0),check:price < 1000,comment:Price in cents"`
+			Format    string  `bun:",type:book_format,enum:(paperback, 'hardcover')"`
+		}
+
+		table := tables.Get(reflect.TypeFor[*Book]())
+		require.Equal(t, []*Check{
+			{Name: "books_price_check", Expr: "(price > 0)"},
+			{Name: "books_price_check1", Expr: "price < 1000"},
+		}, table.Checks)
+		require.Equal(t, "Price in cents", table.FieldMap["price"].Comment)
+		require.Equal(t, []string{"paperback", "hardcover"}, table.FieldMap["format"].EnumValues)
+	})
+
 	t.Run("embedWithRelation", func(t *testing.T) {
 		type Profile struct {
 			ID     string `bun:",pk"`